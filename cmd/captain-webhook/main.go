@@ -0,0 +1,111 @@
+// captain-webhook serves the admission webhooks of HelmRequests and chart repos, and prints the webhook
+// configurations to register it
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alauda/helm-crds/pkg/webhook"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/klog"
+)
+
+func newRootCmd() *cobra.Command {
+	server := &webhook.Server{}
+	cmd := &cobra.Command{
+		Use:           "captain-webhook",
+		Short:         "Serve the admission webhooks of HelmRequests and chart repos",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-sig
+				cancel()
+			}()
+			return server.Run(ctx)
+		},
+	}
+	cmd.Flags().StringVar(&server.Addr, "addr", webhook.DefaultAddr, "the address to listen on")
+	cmd.Flags().StringVar(&server.CertFile, "cert-file", "/tmp/k8s-webhook-server/serving-certs/tls.crt", "the serving certificate")
+	cmd.Flags().StringVar(&server.KeyFile, "key-file", "/tmp/k8s-webhook-server/serving-certs/tls.key", "the key of the serving certificate")
+	cmd.Flags().DurationVar(&server.CertReloadInterval, "cert-reload-interval", webhook.DefaultCertReloadInterval, "how often the certificate files are checked for changes")
+
+	klogFlags := flag.NewFlagSet("klog", flag.ExitOnError)
+	klog.InitFlags(klogFlags)
+	cmd.PersistentFlags().AddGoFlagSet(klogFlags)
+
+	cmd.AddCommand(newManifestsCmd())
+	return cmd
+}
+
+func newManifestsCmd() *cobra.Command {
+	var opts webhook.ConfigOptions
+	var caFile, failurePolicy string
+	cmd := &cobra.Command{
+		Use:   "manifests",
+		Short: "Print the MutatingWebhookConfiguration and ValidatingWebhookConfiguration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.URL == "" && (opts.ServiceNamespace == "" || opts.ServiceName == "") {
+				return fmt.Errorf("--url or --service-namespace and --service-name are required")
+			}
+			if caFile != "" {
+				data, err := ioutil.ReadFile(caFile)
+				if err != nil {
+					return err
+				}
+				opts.CABundle = data
+			}
+			switch policy := admissionregistrationv1beta1.FailurePolicyType(failurePolicy); policy {
+			case admissionregistrationv1beta1.Fail, admissionregistrationv1beta1.Ignore:
+				opts.FailurePolicy = policy
+			default:
+				return fmt.Errorf("unknown failure policy %s, should be one of: Fail, Ignore", failurePolicy)
+			}
+
+			for i, obj := range []interface{}{
+				webhook.MutatingWebhookConfiguration(opts),
+				webhook.ValidatingWebhookConfiguration(opts),
+			} {
+				data, err := yaml.Marshal(obj)
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "---")
+				}
+				fmt.Fprint(cmd.OutOrStdout(), string(data))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.Name, "name", webhook.DefaultConfigurationName, "the name of the webhook configurations")
+	cmd.Flags().StringVar(&opts.ServiceNamespace, "service-namespace", "", "the namespace of the webhook service")
+	cmd.Flags().StringVar(&opts.ServiceName, "service-name", "", "the name of the webhook service")
+	cmd.Flags().Int32Var(&opts.ServicePort, "service-port", 443, "the port of the webhook service")
+	cmd.Flags().StringVar(&opts.URL, "url", "", "the url of the webhook server, used instead of the service if set")
+	cmd.Flags().StringVar(&caFile, "ca-file", "", "the CA to verify the serving certificate")
+	cmd.Flags().StringVar(&failurePolicy, "failure-policy", string(admissionregistrationv1beta1.Fail), "the failure policy of the webhooks, Fail or Ignore")
+	return cmd
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
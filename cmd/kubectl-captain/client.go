@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubeOptions are the flags to access the cluster, shared by all commands
+type kubeOptions struct {
+	kubeconfig    string
+	context       string
+	namespace     string
+	repoNamespace string
+
+	config clientcmd.ClientConfig
+}
+
+var kube = &kubeOptions{}
+
+func (o *kubeOptions) addFlags(f *pflag.FlagSet) {
+	f.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig file")
+	f.StringVar(&o.context, "context", "", "the kubeconfig context to use")
+	f.StringVarP(&o.namespace, "namespace", "n", "", "the namespace of the HelmRequest, default to the namespace of the context")
+	f.StringVar(&o.repoNamespace, "repo-namespace", "", "the namespace of the chart repos when the chart has no namespace, default to the namespace of the HelmRequest")
+}
+
+func (o *kubeOptions) clientConfig() clientcmd.ClientConfig {
+	if o.config == nil {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		rules.ExplicitPath = o.kubeconfig
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: o.context}
+		o.config = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	}
+	return o.config
+}
+
+func (o *kubeOptions) restConfig() (*rest.Config, error) {
+	return o.clientConfig().ClientConfig()
+}
+
+// getNamespace returns the namespace from the flag or the kubeconfig
+func (o *kubeOptions) getNamespace() (string, error) {
+	if o.namespace != "" {
+		return o.namespace, nil
+	}
+	ns, _, err := o.clientConfig().Namespace()
+	return ns, err
+}
+
+func (o *kubeOptions) clients() (versioned.Interface, kubernetes.Interface, error) {
+	config, err := o.restConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, kubeClient, nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/diff"
+	"github.com/alauda/helm-crds/pkg/render"
+	"github.com/alauda/helm-crds/pkg/values"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type diffOptions struct {
+	file        string
+	chart       string
+	output      string
+	context     int
+	showSecrets bool
+	exitCode    bool
+}
+
+func newDiffCmd() *cobra.Command {
+	o := &diffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff [NAME | -f FILE]",
+		Short: "Show the changes a HelmRequest will make to its deployed Release",
+		Long: `Render a HelmRequest and compare it with the latest deployed Release, resource by resource.
+
+The HelmRequest is read from the cluster by NAME, or from FILE with the ConfigMaps and Secrets it references.
+The chart is loaded from the chart repos in the cluster, unless --chart is set. Data of Secrets are masked
+unless --show-secrets is set.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(args) == 0) == (o.file == "") {
+				return fmt.Errorf("either NAME or --file is required")
+			}
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return o.run(cmd.OutOrStdout(), name)
+		},
+	}
+	f := cmd.Flags()
+	f.StringVarP(&o.file, "file", "f", "", "the HelmRequest yaml file, - for stdin")
+	f.StringVar(&o.chart, "chart", "", "the local chart directory or .tgz archive, default to the chart in the chart repos")
+	f.StringVarP(&o.output, "output", "o", "text", "output format, text or json")
+	f.IntVar(&o.context, "context-lines", 3, "the number of context lines in the diff")
+	f.BoolVar(&o.showSecrets, "show-secrets", false, "do not mask the data of Secrets")
+	f.BoolVar(&o.exitCode, "exit-code", false, "exit with 2 if there are changes")
+	return cmd
+}
+
+// errChanges is returned to exit with 2 when --exit-code is set
+type errChanges struct{}
+
+func (errChanges) Error() string { return "changes found" }
+
+func (o *diffOptions) run(out io.Writer, name string) error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format %s", o.output)
+	}
+	ctx := context.Background()
+	client, kubeClient, err := kube.clients()
+	if err != nil {
+		return err
+	}
+
+	var hr *v1beta1.HelmRequest
+	var resolver values.Resolver
+	if o.file != "" {
+		if hr, resolver, err = readHelmRequestFile(o.file); err != nil {
+			return err
+		}
+		if hr.Namespace == "" {
+			if hr.Namespace, err = kube.getNamespace(); err != nil {
+				return err
+			}
+		}
+	} else {
+		ns, err := kube.getNamespace()
+		if err != nil {
+			return err
+		}
+		if hr, err = client.AppV1beta1().HelmRequests(ns).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return err
+		}
+		resolver = values.NewClientResolver(kubeClient)
+	}
+
+	var charts values.ChartLoader = &render.RepoLoader{Client: client, KubeClient: kubeClient, RepoNamespace: kube.repoNamespace}
+	if o.chart != "" {
+		charts = render.NewLocalLoader(o.chart)
+	}
+
+	releases, err := releaseHistory(ctx, client, hr)
+	if err != nil {
+		return err
+	}
+
+	result, err := diff.HelmRequest(ctx, hr, releases, charts, resolver, diff.Options{
+		Context:     o.context,
+		ShowSecrets: o.showSecrets,
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.output == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		for _, item := range result.Changed() {
+			fmt.Fprintf(out, "%s %s\n%s", item.ResourceKey, item.Change, item.Diff)
+		}
+		if !result.HasChanges() {
+			fmt.Fprintln(out, "no changes")
+		}
+	}
+
+	if o.exitCode && result.HasChanges() {
+		return errChanges{}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/exporter"
+	"github.com/alauda/helm-crds/pkg/importer"
+	"github.com/alauda/helm-crds/pkg/values"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export HelmRequests to helm storage or GitOps bundles",
+	}
+	cmd.AddCommand(
+		newExportHelmCmd(),
+		newExportBundleCmd(),
+	)
+	return cmd
+}
+
+func newExportHelmCmd() *cobra.Command {
+	opts := exporter.HelmOptions{}
+	cmd := &cobra.Command{
+		Use:   "helm NAME",
+		Short: "Export the Release history of a HelmRequest to helm storage",
+		Long: `Export the Release history of a HelmRequest to helm storage in the release namespace, so the
+release can be managed by the helm CLI. Revisions already in helm storage are kept.
+
+The HelmRequest and its Releases are not deleted, remove the finalizer and delete them after the
+export is verified by 'helm history'.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch opts.Storage {
+			case "", importer.StorageSecret, importer.StorageConfigMap:
+			default:
+				return fmt.Errorf("unknown helm storage %s", opts.Storage)
+			}
+			ctx := context.Background()
+			client, kubeClient, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			hr, err := client.AppV1beta1().HelmRequests(ns).Get(ctx, args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			result, err := exporter.NewExporter(client, kubeClient).ToHelm(ctx, hr, opts)
+			if err != nil {
+				return err
+			}
+			printHelmExport(cmd.OutOrStdout(), result, opts.DryRun)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar((*string)(&opts.Storage), "storage", string(importer.StorageSecret), "helm storage to write, Secret or ConfigMap")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "only report the revisions would be exported")
+	return cmd
+}
+
+func printHelmExport(out io.Writer, result *exporter.HelmResult, dryRun bool) {
+	join := func(revisions []int) string {
+		sort.Ints(revisions)
+		s := make([]string, 0, len(revisions))
+		for _, r := range revisions {
+			s = append(s, strconv.Itoa(r))
+		}
+		return orNone(strings.Join(s, ","))
+	}
+	verb := "exported"
+	if dryRun {
+		verb = "would be exported"
+	}
+	fmt.Fprintf(out, "Release %s/%s revisions %s to helm %s storage: %s\n", result.Namespace, result.Name, verb,
+		result.Storage, join(result.Exported))
+	if len(result.Existing) > 0 {
+		fmt.Fprintf(out, "Revisions already in helm storage: %s\n", join(result.Existing))
+	}
+}
+
+func newExportBundleCmd() *cobra.Command {
+	var dir string
+	opts := exporter.BundleOptions{}
+	cmd := &cobra.Command{
+		Use:   "bundle NAME",
+		Short: "Export a HelmRequest to a GitOps bundle",
+		Long: `Export a HelmRequest to a directory contains the chart reference, the effective values and a
+flux HelmRelease or an Argo CD Application. Credentials of the chart repo are not exported.
+
+Values from Secrets are not written in the bundle either. The flux HelmRelease references the Secrets
+by valuesFrom, and they are dropped from the Argo CD Application with a warning. Charts in Git repos
+are pinned to the commit last synced.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			client, kubeClient, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			hr, err := client.AppV1beta1().HelmRequests(ns).Get(ctx, args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			e := exporter.NewExporter(client, kubeClient)
+			bundle, err := e.ToBundle(ctx, hr, values.NewClientResolver(kubeClient), opts)
+			if err != nil {
+				return err
+			}
+			if dir == "" {
+				dir = hr.Name
+			}
+			for _, w := range bundle.Warnings {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", w)
+			}
+			if err := bundle.Write(dir); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "HelmRequest %s/%s exported to %s\n", ns, hr.Name, dir)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&dir, "dir", "d", "", "directory to write the bundle, default to the name of the HelmRequest")
+	cmd.Flags().StringVar((*string)(&opts.Format), "format", string(exporter.FormatFlux), "format of the GitOps manifest, flux or argo")
+	cmd.Flags().StringVar(&opts.ArgoNamespace, "argo-namespace", "", "namespace of the Argo CD Application, default to argocd")
+	cmd.Flags().StringVar(&opts.ArgoProject, "argo-project", "", "project of the Argo CD Application, default to default")
+	cmd.Flags().StringVar(&opts.ArgoServer, "argo-server", "", "destination server of the Argo CD Application, default to the in cluster server")
+	return cmd
+}
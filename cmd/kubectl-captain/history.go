@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	listers "github.com/alauda/helm-crds/pkg/client/listers/app/v1beta1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newHistoryCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "history NAME",
+		Short: "List the Release revisions of a HelmRequest",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(output); err != nil {
+				return err
+			}
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			return runHistory(context.Background(), cmd.OutOrStdout(), client, ns, args[0], output)
+		},
+	}
+	addOutputFlag(cmd, &output)
+	return cmd
+}
+
+// releaseHistory returns the Releases of the HelmRequest, from the oldest to the newest
+func releaseHistory(ctx context.Context, client versioned.Interface, hr *v1beta1.HelmRequest) ([]*v1beta1.Release, error) {
+	list, err := client.AppV1beta1().Releases(hr.GetReleaseNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{v1beta1.ReleaseNameLabel: hr.GetReleaseName()}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, listers.ReleaseIndexers())
+	for i := range list.Items {
+		indexer.Add(&list.Items[i])
+	}
+	return listers.NewReleaseLister(indexer).ReleasesForHelmRequest(hr)
+}
+
+func runHistory(ctx context.Context, out io.Writer, client versioned.Interface, namespace, name, output string) error {
+	hr, err := client.AppV1beta1().HelmRequests(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	releases, err := releaseHistory(ctx, client, hr)
+	if err != nil {
+		return err
+	}
+	if output != outputTable {
+		list := &v1beta1.ReleaseList{}
+		for _, rel := range releases {
+			list.Items = append(list.Items, *rel)
+		}
+		return printObject(out, output, list)
+	}
+
+	t := newTable(out, "REVISION", "UPDATED", "STATUS", "DESCRIPTION")
+	for _, rel := range releases {
+		updated := rel.Status.LastDeployed
+		if updated.IsZero() {
+			updated = rel.CreationTimestamp
+		}
+		updatedAt := "<unknown>"
+		if !updated.IsZero() {
+			updatedAt = updated.Format("2006-01-02 15:04:05")
+		}
+		t.row(strconv.Itoa(rel.Spec.Version), updatedAt, orNone(string(rel.Status.Status)), rel.Status.Description)
+	}
+	return t.flush()
+}
+
+func newRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback NAME REVISION",
+		Short: "Rollback the release of a HelmRequest to a revision",
+		Long: `Rollback the release of a HelmRequest to a revision in its history.
+
+The rollback is requested by the ` + v1beta1.RollbackAnnotation + ` annotation, and done by captain.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			revision, err := strconv.Atoi(args[1])
+			if err != nil || revision <= 0 {
+				return fmt.Errorf("invalid revision %s", args[1])
+			}
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			return runRollback(context.Background(), cmd.OutOrStdout(), client, ns, args[0], revision)
+		},
+	}
+	return cmd
+}
+
+func runRollback(ctx context.Context, out io.Writer, client versioned.Interface, namespace, name string, revision int) error {
+	hr, err := client.AppV1beta1().HelmRequests(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	releases, err := releaseHistory(ctx, client, hr)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, rel := range releases {
+		if rel.Spec.Version == revision {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("revision %d not found in the history of %s/%s", revision, namespace, name)
+	}
+
+	if err := annotate(ctx, client, hr, v1beta1.RollbackAnnotation, strconv.Itoa(revision)); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "HelmRequest %s/%s is requested to rollback to revision %d\n", namespace, name, revision)
+	return nil
+}
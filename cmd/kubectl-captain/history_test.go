@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	"helm.sh/helm/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRelease(namespace, name string, version int, status release.Status) *v1beta1.Release {
+	return &v1beta1.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s.v%d", name, version),
+			Labels:    map[string]string{v1beta1.ReleaseNameLabel: name},
+		},
+		Spec:   v1beta1.ReleaseSpec{Name: name, Version: version},
+		Status: v1beta1.ReleaseStatus{Status: status, Description: "revision " + strconv.Itoa(version)},
+	}
+}
+
+func newHistoryClient() *fake.Clientset {
+	hr := newHelmRequest("default", "web", "stable/nginx")
+	hr.Spec.ReleaseName = "nginx"
+	hr.Spec.Namespace = "apps"
+	return fake.NewSimpleClientset([]runtime.Object{
+		hr,
+		newRelease("apps", "nginx", 2, release.StatusDeployed),
+		newRelease("apps", "nginx", 1, release.StatusSuperseded),
+		newRelease("apps", "redis", 1, release.StatusDeployed),
+		newRelease("default", "nginx", 3, release.StatusDeployed),
+	}...)
+}
+
+func TestHistory(t *testing.T) {
+	client := newHistoryClient()
+	out := &bytes.Buffer{}
+	if err := runHistory(context.Background(), out, client, "default", "web", outputTable); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expect header and 2 revisions, got:\n%s", out.String())
+	}
+	for i, expect := range []string{"1", "2"} {
+		if fields := strings.Fields(lines[i+1]); fields[0] != expect {
+			t.Errorf("expect revision %s in line %d, got %s", expect, i+1, lines[i+1])
+		}
+	}
+
+	out.Reset()
+	if err := runHistory(context.Background(), out, client, "default", "missing", outputTable); err == nil {
+		t.Error("expect error for missing HelmRequest")
+	}
+}
+
+func TestRollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision int
+		err      bool
+	}{
+		{name: "in history", revision: 1},
+		{name: "not in history", revision: 3, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHistoryClient()
+			ctx := context.Background()
+			err := runRollback(ctx, &bytes.Buffer{}, client, "default", "web", tt.revision)
+			hr, _ := client.AppV1beta1().HelmRequests("default").Get(ctx, "web", metav1.GetOptions{})
+			value, ok := hr.Annotations[v1beta1.RollbackAnnotation]
+			if tt.err {
+				if err == nil {
+					t.Error("expect error")
+				}
+				if ok {
+					t.Errorf("expect no rollback annotation, got %s", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != strconv.Itoa(tt.revision) {
+				t.Errorf("expect rollback to %d, got %s", tt.revision, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/importer"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	var output string
+	var allNamespaces bool
+	opts := importer.Options{}
+	cmd := &cobra.Command{
+		Use:   "import [RELEASE...]",
+		Short: "Import the releases installed by helm as HelmRequests",
+		Long: `Import the releases installed by the helm CLI. Releases are read from helm's Secret or ConfigMap
+storage, each revision is created as a Release, and a HelmRequest is created with the chart, version
+and values of the latest revision. All releases in the namespace are imported if no name is given.
+Releases that are uninstalled, being installed, upgraded, rolled back or uninstalled, or already have
+a HelmRequest are skipped.
+
+Use --dry-run to see what would be adopted.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(output); err != nil {
+				return err
+			}
+			switch opts.Storage {
+			case "", importer.StorageSecret, importer.StorageConfigMap:
+			default:
+				return fmt.Errorf("unknown helm storage %s", opts.Storage)
+			}
+			client, kubeClient, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			if !allNamespaces {
+				if opts.Namespace, err = kube.getNamespace(); err != nil {
+					return err
+				}
+			}
+			opts.Releases = args
+			report, err := importer.NewImporter(client, kubeClient).Import(context.Background(), opts)
+			if err != nil {
+				return err
+			}
+			return printImportReport(cmd.OutOrStdout(), report, output)
+		},
+	}
+	addOutputFlag(cmd, &output)
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "import releases in all namespaces")
+	cmd.Flags().StringVar((*string)(&opts.Storage), "storage", "", "helm storage to read, Secret or ConfigMap, both if empty")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "chart repo of the HelmRequests, looked up by the chart name and version if empty")
+	cmd.Flags().StringVar(&opts.ClusterName, "cluster", "", "cluster name of the HelmRequests")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "only report what would be adopted")
+	return cmd
+}
+
+func printImportReport(out io.Writer, report *importer.Report, output string) error {
+	if output != outputTable {
+		return printObject(out, output, report)
+	}
+
+	t := newTable(out, "NAMESPACE", "NAME", "STORAGE", "REVISIONS", "CHART", "VERSION", "ACTION", "REASON")
+	for _, item := range report.Items {
+		revisions := make([]string, 0, len(item.Revisions))
+		for _, r := range item.Revisions {
+			revisions = append(revisions, strconv.Itoa(r))
+		}
+		t.row(item.Namespace, item.Name, string(item.Storage), strings.Join(revisions, ","), orNone(item.Chart),
+			orNone(item.Version), string(item.Action), item.Reason)
+	}
+	if err := t.flush(); err != nil {
+		return err
+	}
+
+	verb := "adopted"
+	if report.DryRun {
+		verb = "would be adopted"
+	}
+	fmt.Fprintf(out, "\n%d of %d releases %s\n", report.Adopted(), len(report.Items), verb)
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newListCmd() *cobra.Command {
+	var output string
+	var allNamespaces bool
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List HelmRequests",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(output); err != nil {
+				return err
+			}
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns := metav1.NamespaceAll
+			if !allNamespaces {
+				if ns, err = kube.getNamespace(); err != nil {
+					return err
+				}
+			}
+			return runList(context.Background(), cmd.OutOrStdout(), client, ns, output)
+		},
+	}
+	addOutputFlag(cmd, &output)
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "list HelmRequests in all namespaces")
+	return cmd
+}
+
+func runList(ctx context.Context, out io.Writer, client versioned.Interface, namespace, output string) error {
+	list, err := client.AppV1beta1().HelmRequests(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		a, b := list.Items[i], list.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	if output != outputTable {
+		return printObject(out, output, list)
+	}
+
+	t := newTable(out, "NAMESPACE", "NAME", "CHART", "VERSION", "CLUSTERS", "PHASE", "AGE")
+	for _, hr := range list.Items {
+		version := hr.Status.Version
+		if version == "" {
+			version = hr.Spec.Version
+		}
+		t.row(hr.Namespace, hr.Name, hr.Spec.Chart, orNone(version), clusters(&hr), orNone(string(hr.Status.Phase)), age(hr.CreationTimestamp))
+	}
+	return t.flush()
+}
+
+// clusters returns the target clusters of the HelmRequest for display
+func clusters(hr *v1beta1.HelmRequest) string {
+	if hr.Spec.InstallToAllClusters {
+		return fmt.Sprintf("<all>(%d synced)", len(hr.Status.SyncedClusters))
+	}
+	return orNone(hr.Spec.ClusterName)
+}
+
+func newStatusCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "status NAME",
+		Short: "Show the status of a HelmRequest, with the detail of each cluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(output); err != nil {
+				return err
+			}
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			return runStatus(context.Background(), cmd.OutOrStdout(), client, ns, args[0], output)
+		},
+	}
+	addOutputFlag(cmd, &output)
+	return cmd
+}
+
+func runStatus(ctx context.Context, out io.Writer, client versioned.Interface, namespace, name, output string) error {
+	hr, err := client.AppV1beta1().HelmRequests(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if output != outputTable {
+		return printObject(out, output, hr)
+	}
+
+	fmt.Fprintf(out, "Name:              %s\n", hr.Name)
+	fmt.Fprintf(out, "Namespace:         %s\n", hr.Namespace)
+	fmt.Fprintf(out, "Chart:             %s\n", hr.Spec.Chart)
+	fmt.Fprintf(out, "Version:           %s\n", orNone(hr.Spec.Version))
+	fmt.Fprintf(out, "Installed Version: %s\n", orNone(hr.Status.Version))
+	fmt.Fprintf(out, "Release:           %s/%s\n", hr.GetReleaseNamespace(), hr.GetReleaseName())
+	fmt.Fprintf(out, "Phase:             %s\n", orNone(string(hr.Status.Phase)))
+	if hr.Status.Reason != "" {
+		fmt.Fprintf(out, "Reason:            %s\n", hr.Status.Reason)
+	}
+
+	fmt.Fprintln(out, "Clusters:")
+	t := newTable(out, "  CLUSTER", "SYNCED")
+	if hr.Spec.InstallToAllClusters {
+		synced := append([]string{}, hr.Status.SyncedClusters...)
+		sort.Strings(synced)
+		for _, c := range synced {
+			t.row("  "+c, "true")
+		}
+		if len(synced) == 0 {
+			t.row("  <all>", "false")
+		}
+	} else {
+		t.row("  "+orNone(hr.Spec.ClusterName), fmt.Sprint(hr.Status.Phase == v1beta1.HelmRequestSynced))
+	}
+	if err := t.flush(); err != nil {
+		return err
+	}
+
+	if len(hr.Status.Conditions) > 0 {
+		fmt.Fprintln(out, "Conditions:")
+		t := newTable(out, "  TYPE", "STATUS", "REASON", "AGE", "MESSAGE")
+		for _, c := range hr.Status.Conditions {
+			t.row("  "+string(c.Type), string(c.Status), orNone(c.Reason), age(c.LastTransitionTime), c.Message)
+		}
+		if err := t.flush(); err != nil {
+			return err
+		}
+	}
+	if notes := strings.TrimSpace(hr.Status.Notes); notes != "" {
+		fmt.Fprintf(out, "Notes:\n%s\n", notes)
+	}
+	return nil
+}
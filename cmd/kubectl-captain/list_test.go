@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+)
+
+func TestList(t *testing.T) {
+	web := newHelmRequest("default", "web", "stable/nginx")
+	web.Spec.Version = "1.0.0"
+	all := newHelmRequest("default", "agent", "stable/agent")
+	all.Spec.InstallToAllClusters = true
+	all.Status.SyncedClusters = []string{"a", "b"}
+	other := newHelmRequest("other", "db", "stable/redis")
+	client := fake.NewSimpleClientset(web, all, other)
+
+	out := &bytes.Buffer{}
+	if err := runList(context.Background(), out, client, "default", outputTable); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expect header and 2 rows, got:\n%s", out.String())
+	}
+	expect := [][]string{
+		{"default", "agent", "stable/agent", "<none>", "<all>(2", "synced)"},
+		{"default", "web", "stable/nginx", "1.0.0", "<none>"},
+	}
+	for i, fields := range expect {
+		got := strings.Fields(lines[i+1])
+		for j, f := range fields {
+			if got[j] != f {
+				t.Errorf("expect %v in line %d, got %v", fields, i+1, got)
+				break
+			}
+		}
+	}
+}
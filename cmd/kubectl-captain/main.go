@@ -0,0 +1,44 @@
+// kubectl-captain is a kubectl plugin to manage HelmRequests
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "kubectl-captain",
+		Short:         "Manage HelmRequests and chart repos of captain",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	kube.addFlags(cmd.PersistentFlags())
+	cmd.AddCommand(
+		newListCmd(),
+		newStatusCmd(),
+		newValuesCmd(),
+		newHistoryCmd(),
+		newRollbackCmd(),
+		newSyncCmd(),
+		newRepoCmd(),
+		newSearchCmd(),
+		newImportCmd(),
+		newExportCmd(),
+		newRenderCmd(),
+		newDiffCmd(),
+	)
+	return cmd
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		if _, ok := err.(errChanges); ok {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
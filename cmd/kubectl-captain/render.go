@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/render"
+	"github.com/alauda/helm-crds/pkg/values"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/pkg/chartutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type renderOptions struct {
+	file        string
+	chart       string
+	kubeVersion string
+	apiVersions []string
+	upgrade     bool
+	strict      bool
+	showNotes   bool
+	showHooks   bool
+}
+
+func newRenderCmd() *cobra.Command {
+	o := &renderOptions{}
+	cmd := &cobra.Command{
+		Use:   "render -f helmrequest.yaml --chart ./nginx",
+		Short: "Render a HelmRequest against a local chart without a cluster",
+		Long: `Render a HelmRequest against a local chart directory or .tgz archive without a cluster.
+
+The file may contain the ConfigMaps and Secrets referenced in .spec.valuesFrom, separated by "---".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(cmd.OutOrStdout())
+		},
+	}
+	f := cmd.Flags()
+	f.StringVarP(&o.file, "file", "f", "", "the HelmRequest yaml file, - for stdin")
+	f.StringVar(&o.chart, "chart", "", "the chart directory or .tgz archive")
+	f.StringVar(&o.kubeVersion, "kube-version", "", "the kubernetes version used for .Capabilities.KubeVersion")
+	f.StringSliceVar(&o.apiVersions, "api-versions", nil, "extra api versions used for .Capabilities.APIVersions")
+	f.BoolVar(&o.upgrade, "upgrade", false, "render as an upgrade instead of an install")
+	f.BoolVar(&o.strict, "strict", false, "fail on missing values in templates")
+	f.BoolVar(&o.showNotes, "notes", false, "print the rendered NOTES.txt")
+	f.BoolVar(&o.showHooks, "hooks", true, "print the rendered hooks")
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("chart")
+	return cmd
+}
+
+func (o *renderOptions) run(out io.Writer) error {
+	hr, resolver, err := readHelmRequestFile(o.file)
+	if err != nil {
+		return err
+	}
+	caps, err := capabilities(o.kubeVersion, o.apiVersions)
+	if err != nil {
+		return err
+	}
+
+	result, err := render.RenderWithOptions(context.Background(), hr, render.NewLocalLoader(o.chart), resolver, render.Options{
+		Capabilities: caps,
+		IsUpgrade:    o.upgrade,
+		Strict:       o.strict,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(out, result.Manifest())
+	if o.showHooks {
+		for _, h := range result.Hooks {
+			fmt.Fprintf(out, "---\n# Source: %s\n%s\n", h.Path, h.Manifest)
+		}
+	}
+	if o.showNotes && result.Notes != "" {
+		fmt.Fprintf(out, "\nNOTES:\n%s\n", result.Notes)
+	}
+	return nil
+}
+
+// capabilities returns the default capabilities with the kube version and extra api versions
+func capabilities(kubeVersion string, apiVersions []string) (*chartutil.Capabilities, error) {
+	caps := *chartutil.DefaultCapabilities
+	if kubeVersion != "" {
+		parts := strings.SplitN(strings.TrimPrefix(kubeVersion, "v"), ".", 3)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid kube version %s", kubeVersion)
+		}
+		caps.KubeVersion = chartutil.KubeVersion{
+			Version: "v" + strings.TrimPrefix(kubeVersion, "v"),
+			Major:   parts[0],
+			Minor:   parts[1],
+		}
+	}
+	if len(apiVersions) > 0 {
+		caps.APIVersions = append(append(chartutil.VersionSet{}, caps.APIVersions...), apiVersions...)
+	}
+	return &caps, nil
+}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// readHelmRequestFile reads the HelmRequest in the file, and returns a Resolver resolves values from the
+// ConfigMaps and Secrets in the same file
+func readHelmRequestFile(file string) (*v1beta1.HelmRequest, values.Resolver, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configMaps := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	var hr *v1beta1.HelmRequest
+
+	for _, doc := range documentSeparator.Split(string(data), -1) {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var meta metav1.TypeMeta
+		if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+			return nil, nil, fmt.Errorf("parse %s error: %s", file, err.Error())
+		}
+		switch meta.Kind {
+		case "HelmRequest":
+			if hr != nil {
+				return nil, nil, fmt.Errorf("found more than one HelmRequest in %s", file)
+			}
+			hr = &v1beta1.HelmRequest{}
+			if err := yaml.Unmarshal([]byte(doc), hr); err != nil {
+				return nil, nil, fmt.Errorf("parse HelmRequest error: %s", err.Error())
+			}
+		case "ConfigMap":
+			cm := &v1.ConfigMap{}
+			if err := yaml.Unmarshal([]byte(doc), cm); err != nil {
+				return nil, nil, fmt.Errorf("parse ConfigMap error: %s", err.Error())
+			}
+			configMaps.Add(cm)
+		case "Secret":
+			secret := &v1.Secret{}
+			if err := yaml.Unmarshal([]byte(doc), secret); err != nil {
+				return nil, nil, fmt.Errorf("parse Secret error: %s", err.Error())
+			}
+			for k, v := range secret.StringData {
+				if secret.Data == nil {
+					secret.Data = map[string][]byte{}
+				}
+				secret.Data[k] = []byte(v)
+			}
+			secrets.Add(secret)
+		default:
+			return nil, nil, fmt.Errorf("unexpected kind %q in %s", meta.Kind, file)
+		}
+	}
+	if hr == nil {
+		return nil, nil, fmt.Errorf("no HelmRequest found in %s", file)
+	}
+
+	// ConfigMaps and Secrets without a namespace are in the namespace of the HelmRequest
+	for _, indexer := range []cache.Indexer{configMaps, secrets} {
+		for _, obj := range indexer.List() {
+			m, _ := obj.(metav1.Object)
+			if m.GetNamespace() == "" {
+				indexer.Delete(obj)
+				m.SetNamespace(hr.Namespace)
+				indexer.Add(obj)
+			}
+		}
+	}
+
+	resolver := values.NewListerResolver(corelisters.NewConfigMapLister(configMaps), corelisters.NewSecretLister(secrets))
+	return hr, resolver, nil
+}
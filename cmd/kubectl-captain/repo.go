@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func newRepoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Manage chart repos",
+	}
+	cmd.AddCommand(
+		newRepoAddCmd(),
+		newRepoListCmd(),
+		newRepoRefreshCmd(),
+	)
+	return cmd
+}
+
+const (
+	// managedByLabel and managedBy mark the Secrets created by repo add, only these Secrets are updated
+	// when they already exist
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedBy      = "kubectl-captain"
+	// passwordEnv is read as the password of repo add if --password and --password-stdin are not given
+	passwordEnv = "CAPTAIN_REPO_PASSWORD"
+)
+
+// repoAddOptions are the options of repo add
+type repoAddOptions struct {
+	name          string
+	url           string
+	repoType      string
+	username      string
+	password      string
+	passwordStdin bool
+}
+
+// readPassword reads the password from in if --password-stdin is given, or from the passwordEnv
+// environment variable if --password is not given either
+func (o *repoAddOptions) readPassword(in io.Reader, getenv func(string) string) error {
+	if !o.passwordStdin {
+		if o.password == "" {
+			o.password = getenv(passwordEnv)
+		}
+		return nil
+	}
+	if o.password != "" {
+		return fmt.Errorf("--password and --password-stdin are mutually exclusive")
+	}
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("read password from stdin error: %s", err.Error())
+	}
+	o.password = strings.TrimRight(string(data), "\r\n")
+	return nil
+}
+
+func newRepoAddCmd() *cobra.Command {
+	opts := &repoAddOptions{}
+	cmd := &cobra.Command{
+		Use:   "add NAME URL",
+		Short: "Add a chart repo",
+		Long: `Add a chart repo to the namespace. If username or password is given, a Secret with the same
+name as the repo is created to hold them, it's owned by the repo and deleted with it. An existing
+Secret is only updated if it's created by this command or owned by the repo. Adding an existing repo
+fails without changing its Secret.
+
+To keep the password out of the shell history and the process list, pipe it with --password-stdin or
+set it in the ` + passwordEnv + ` environment variable.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name, opts.url = args[0], args[1]
+			if err := opts.readPassword(cmd.InOrStdin(), os.Getenv); err != nil {
+				return err
+			}
+			client, kubeClient, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			return runRepoAdd(context.Background(), cmd.OutOrStdout(), client, kubeClient, ns, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.repoType, "type", string(v1beta1.ChartRepoChart), "type of the repo, one of Chart, Git and SVN")
+	cmd.Flags().StringVar(&opts.username, "username", "", "username of the repo")
+	cmd.Flags().StringVar(&opts.password, "password", "", "password of the repo, prefer --password-stdin or "+passwordEnv)
+	cmd.Flags().BoolVar(&opts.passwordStdin, "password-stdin", false, "read the password of the repo from stdin")
+	return cmd
+}
+
+func runRepoAdd(ctx context.Context, out io.Writer, client versioned.Interface, kubeClient kubernetes.Interface, namespace string, opts *repoAddOptions) error {
+	switch v1beta1.ChartRepoType(opts.repoType) {
+	case v1beta1.ChartRepoChart, v1beta1.ChartRepoGit, v1beta1.ChartRepoSvn:
+	default:
+		return fmt.Errorf("unknown repo type %s", opts.repoType)
+	}
+
+	cr := &v1beta1.ChartRepo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.name,
+			Namespace: namespace,
+		},
+		Spec: v1beta1.ChartRepoSpec{
+			URL:  opts.url,
+			Type: opts.repoType,
+		},
+	}
+	if cr.Spec.Type != string(v1beta1.ChartRepoChart) {
+		cr.Spec.Source = &v1beta1.ChartRepoSource{URL: opts.url}
+	}
+	if opts.username != "" || opts.password != "" {
+		cr.Spec.Secret = &v1.SecretReference{Name: opts.name, Namespace: namespace}
+	}
+	if err := cr.ValidateCreate(); err != nil {
+		return err
+	}
+
+	if cr.Spec.Secret != nil {
+		// check before creating the repo, so it never refers to a Secret not for it
+		if err := checkRepoSecret(kubeClient, namespace, opts.name); err != nil {
+			return err
+		}
+	}
+
+	// the repo is created before the Secret, so adding an existing repo fails before its credentials are
+	// overwritten
+	created, err := client.AppV1beta1().ChartRepos(namespace).Create(ctx, cr, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	if cr.Spec.Secret != nil {
+		if err := applyRepoSecret(kubeClient, created, opts); err != nil {
+			if delErr := client.AppV1beta1().ChartRepos(namespace).Delete(ctx, opts.name, metav1.DeleteOptions{}); delErr != nil {
+				return fmt.Errorf("%s, and delete the added ChartRepo error: %s", err.Error(), delErr.Error())
+			}
+			return err
+		}
+	}
+	fmt.Fprintf(out, "ChartRepo %s/%s added\n", namespace, opts.name)
+	return nil
+}
+
+// checkRepoSecret checks whether the Secret of the repo credentials can be written by repo add, it's
+// either not found or created by repo add or owned by the ChartRepo
+func checkRepoSecret(kubeClient kubernetes.Interface, namespace, name string) error {
+	existing, err := kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get secret %s/%s error: %s", namespace, name, err.Error())
+	}
+	if !isRepoSecret(existing, name) {
+		return secretConflictError(namespace, name)
+	}
+	return nil
+}
+
+// applyRepoSecret creates the Secret of the repo credentials owned by the ChartRepo, so it's deleted with
+// the repo. If it already exists, it's updated only if it's created by repo add or owned by the ChartRepo,
+// other Secrets are never overwritten.
+func applyRepoSecret(kubeClient kubernetes.Interface, cr *v1beta1.ChartRepo, opts *repoAddOptions) error {
+	namespace := cr.Namespace
+	owner := metav1.OwnerReference{
+		APIVersion: v1beta1.SchemeGroupVersion.String(),
+		Kind:       "ChartRepo",
+		Name:       cr.Name,
+		UID:        cr.UID,
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            opts.name,
+			Namespace:       namespace,
+			Labels:          map[string]string{managedByLabel: managedBy},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Type: v1.SecretTypeOpaque,
+		StringData: map[string]string{
+			"username": opts.username,
+			"password": opts.password,
+		},
+	}
+	_, err := kubeClient.CoreV1().Secrets(namespace).Create(secret)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create secret %s/%s error: %s", namespace, opts.name, err.Error())
+	}
+
+	existing, err := kubeClient.CoreV1().Secrets(namespace).Get(opts.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get secret %s/%s error: %s", namespace, opts.name, err.Error())
+	}
+	if !isRepoSecret(existing, opts.name) {
+		return secretConflictError(namespace, opts.name)
+	}
+	secret.ResourceVersion = existing.ResourceVersion
+	// the references to the deleted repos of the same name are replaced
+	for _, ref := range existing.OwnerReferences {
+		if !isRepoOwner(ref, opts.name) {
+			secret.OwnerReferences = append(secret.OwnerReferences, ref)
+		}
+	}
+	if _, err := kubeClient.CoreV1().Secrets(namespace).Update(secret); err != nil {
+		return fmt.Errorf("update secret %s/%s error: %s", namespace, opts.name, err.Error())
+	}
+	return nil
+}
+
+func secretConflictError(namespace, name string) error {
+	return fmt.Errorf("secret %s/%s already exists and is not created by %s, please remove it or choose another repo name",
+		namespace, name, managedBy)
+}
+
+// isRepoSecret checks whether the Secret is created by repo add or owned by the ChartRepo
+func isRepoSecret(secret *v1.Secret, repo string) bool {
+	if secret.Labels[managedByLabel] == managedBy {
+		return true
+	}
+	for _, ref := range secret.OwnerReferences {
+		if isRepoOwner(ref, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRepoOwner checks whether the reference is to the ChartRepo of the name
+func isRepoOwner(ref metav1.OwnerReference, repo string) bool {
+	return ref.Kind == "ChartRepo" && ref.Name == repo && strings.HasPrefix(ref.APIVersion, v1beta1.SchemeGroupVersion.Group+"/")
+}
+
+func newRepoListCmd() *cobra.Command {
+	var output string
+	var allNamespaces bool
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the ChartRepos in the namespace and the ClusterChartRepos",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(output); err != nil {
+				return err
+			}
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns := metav1.NamespaceAll
+			if !allNamespaces {
+				if ns, err = kube.getNamespace(); err != nil {
+					return err
+				}
+			}
+			return runRepoList(context.Background(), cmd.OutOrStdout(), client, ns, output)
+		},
+	}
+	addOutputFlag(cmd, &output)
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "list ChartRepos in all namespaces")
+	return cmd
+}
+
+func runRepoList(ctx context.Context, out io.Writer, client versioned.Interface, namespace, output string) error {
+	repos, err := client.AppV1beta1().ChartRepos(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	clusterRepos, err := client.AppV1beta1().ClusterChartRepos().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(repos.Items, func(i, j int) bool {
+		a, b := repos.Items[i], repos.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(clusterRepos.Items, func(i, j int) bool {
+		return clusterRepos.Items[i].Name < clusterRepos.Items[j].Name
+	})
+
+	if output != outputTable {
+		return printObject(out, output, map[string]interface{}{
+			"chartRepos":        repos.Items,
+			"clusterChartRepos": clusterRepos.Items,
+		})
+	}
+
+	t := newTable(out, "SCOPE", "NAMESPACE", "NAME", "TYPE", "URL", "PHASE", "CHARTS", "LAST SYNC")
+	row := func(scope, namespace, name string, spec *v1beta1.ChartRepoSpec, status *v1beta1.ChartRepoStatus) {
+		lastSync := "<never>"
+		if status.LastSyncTime != nil {
+			lastSync = age(*status.LastSyncTime)
+		}
+		t.row(scope, orNone(namespace), name, orNone(spec.Type), spec.URL, orNone(string(status.Phase)),
+			strconv.Itoa(status.ChartCount), lastSync)
+	}
+	for _, cr := range repos.Items {
+		row("Namespace", cr.Namespace, cr.Name, &cr.Spec, &cr.Status)
+	}
+	for _, cr := range clusterRepos.Items {
+		row("Cluster", "", cr.Name, &cr.Spec, &cr.Status)
+	}
+	return t.flush()
+}
+
+func newRepoRefreshCmd() *cobra.Command {
+	var cluster bool
+	cmd := &cobra.Command{
+		Use:   "refresh NAME",
+		Short: "Request captain to sync a chart repo right now",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns := ""
+			if !cluster {
+				if ns, err = kube.getNamespace(); err != nil {
+					return err
+				}
+			}
+			return runRepoRefresh(context.Background(), cmd.OutOrStdout(), client, ns, args[0], time.Now())
+		},
+	}
+	cmd.Flags().BoolVar(&cluster, "cluster", false, "refresh a ClusterChartRepo instead of a ChartRepo")
+	return cmd
+}
+
+// runRepoRefresh sets the ResyncAnnotation of the repo, namespace is empty for ClusterChartRepos
+func runRepoRefresh(ctx context.Context, out io.Writer, client versioned.Interface, namespace, name string, now time.Time) error {
+	value := now.UTC().Format(time.RFC3339Nano)
+	if namespace == "" {
+		cr, err := client.AppV1beta1().ClusterChartRepos().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cr = cr.DeepCopy()
+		cr.SetAnnotations(setAnnotation(cr.GetAnnotations(), v1beta1.ResyncAnnotation, value))
+		if _, err := client.AppV1beta1().ClusterChartRepos().Update(ctx, cr, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "ClusterChartRepo %s is requested to refresh\n", name)
+		return nil
+	}
+
+	cr, err := client.AppV1beta1().ChartRepos(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	cr = cr.DeepCopy()
+	cr.SetAnnotations(setAnnotation(cr.GetAnnotations(), v1beta1.ResyncAnnotation, value))
+	if _, err := client.AppV1beta1().ChartRepos(namespace).Update(ctx, cr, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "ChartRepo %s/%s is requested to refresh\n", namespace, name)
+	return nil
+}
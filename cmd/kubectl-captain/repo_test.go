@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestRepoAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     repoAddOptions
+		existing *v1.Secret
+		repo     *v1beta1.ChartRepo
+		// secretErr fails creating the Secret, eg: created by others after checked
+		secretErr bool
+		err       string
+		secret    bool
+	}{
+		{
+			name: "no credentials",
+			opts: repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Chart"},
+		},
+		{
+			name:   "credentials",
+			opts:   repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Chart", username: "admin", password: "s3cret"},
+			secret: true,
+		},
+		{
+			name: "update the secret created by repo add",
+			opts: repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Chart", username: "admin", password: "s3cret"},
+			existing: &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name: "stable", Namespace: "default", ResourceVersion: "3", Labels: map[string]string{managedByLabel: managedBy},
+			}},
+			secret: true,
+		},
+		{
+			name: "update the secret owned by the repo",
+			opts: repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Chart", username: "admin", password: "s3cret"},
+			existing: &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name: "stable", Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "app.alauda.io/v1beta1", Kind: "ChartRepo", Name: "stable", UID: "deleted"},
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other"},
+				},
+			}},
+			secret: true,
+		},
+		{
+			name: "repo exists",
+			opts: repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Chart", username: "admin", password: "s3cret"},
+			existing: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default", Labels: map[string]string{managedByLabel: managedBy}},
+				StringData: map[string]string{"token": "other"},
+			},
+			repo: &v1beta1.ChartRepo{ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"}},
+			err:  "already exists",
+		},
+		{
+			name:      "secret failed",
+			opts:      repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Chart", username: "admin", password: "s3cret"},
+			secretErr: true,
+			err:       "create secret default/stable error",
+		},
+		{
+			name: "refuse to overwrite other secrets",
+			opts: repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Chart", username: "admin", password: "s3cret"},
+			existing: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"},
+				StringData: map[string]string{"token": "other"},
+			},
+			err: "not created by kubectl-captain",
+		},
+		{
+			name: "git",
+			opts: repoAddOptions{name: "charts", url: "https://git.example.com/charts.git", repoType: "Git"},
+		},
+		{
+			name: "unknown type",
+			opts: repoAddOptions{name: "stable", url: "https://charts.example.com", repoType: "Unknown"},
+			err:  "unknown repo type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			if tt.repo != nil {
+				client = fake.NewSimpleClientset(tt.repo)
+			}
+			kubeClient := kubefake.NewSimpleClientset()
+			if tt.existing != nil {
+				kubeClient = kubefake.NewSimpleClientset(tt.existing)
+			}
+			if tt.secretErr {
+				kubeClient.PrependReactor("create", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("forbidden")
+				})
+			}
+			out := &bytes.Buffer{}
+			opts := tt.opts
+			err := runRepoAdd(context.Background(), out, client, kubeClient, "default", &opts)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expect error %q, got %v", tt.err, err)
+				}
+				if tt.existing != nil {
+					secret, _ := kubeClient.CoreV1().Secrets("default").Get(tt.opts.name, metav1.GetOptions{})
+					if secret.StringData["token"] != "other" {
+						t.Errorf("existing secret is changed: %v", secret.StringData)
+					}
+				}
+				if tt.repo == nil {
+					if _, err := client.AppV1beta1().ChartRepos("default").Get(context.Background(), tt.opts.name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+						t.Errorf("expect no repo added, got %v", err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cr, err := client.AppV1beta1().ChartRepos("default").Get(context.Background(), tt.opts.name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cr.Spec.URL != tt.opts.url || cr.Spec.Type != tt.opts.repoType {
+				t.Errorf("unexpected spec: %+v", cr.Spec)
+			}
+			if !tt.secret {
+				if cr.Spec.Secret != nil {
+					t.Errorf("expect no secret, got %v", cr.Spec.Secret)
+				}
+				return
+			}
+			if cr.Spec.Secret == nil || cr.Spec.Secret.Name != tt.opts.name {
+				t.Fatalf("expect secret %s, got %v", tt.opts.name, cr.Spec.Secret)
+			}
+			secret, err := kubeClient.CoreV1().Secrets("default").Get(tt.opts.name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if secret.StringData["username"] != "admin" || secret.StringData["password"] != "s3cret" {
+				t.Errorf("unexpected secret data: %v", secret.StringData)
+			}
+			if tt.existing != nil && tt.existing.ResourceVersion != "" && secret.ResourceVersion != tt.existing.ResourceVersion {
+				t.Errorf("expect resourceVersion %s, got %s", tt.existing.ResourceVersion, secret.ResourceVersion)
+			}
+			if len(secret.OwnerReferences) == 0 || secret.OwnerReferences[0].Kind != "ChartRepo" ||
+				secret.OwnerReferences[0].Name != cr.Name || secret.OwnerReferences[0].UID != cr.UID {
+				t.Fatalf("expect owned by the repo, got %v", secret.OwnerReferences)
+			}
+			for _, ref := range secret.OwnerReferences[1:] {
+				if ref.Kind == "ChartRepo" {
+					t.Errorf("expect the reference to the deleted repo replaced, got %v", secret.OwnerReferences)
+				}
+			}
+			if tt.existing != nil && len(tt.existing.OwnerReferences) > 1 && len(secret.OwnerReferences) != 2 {
+				t.Errorf("expect other references kept, got %v", secret.OwnerReferences)
+			}
+		})
+	}
+}
+
+func TestRepoAddReadPassword(t *testing.T) {
+	env := func(string) string { return "from-env" }
+	noEnv := func(string) string { return "" }
+	tests := []struct {
+		name     string
+		opts     repoAddOptions
+		stdin    string
+		getenv   func(string) string
+		password string
+		err      bool
+	}{
+		{name: "flag", opts: repoAddOptions{password: "from-flag"}, getenv: env, password: "from-flag"},
+		{name: "env", getenv: env, password: "from-env"},
+		{name: "none", getenv: noEnv, password: ""},
+		{name: "stdin", opts: repoAddOptions{passwordStdin: true}, stdin: "from-stdin\n", getenv: env, password: "from-stdin"},
+		{name: "stdin with flag", opts: repoAddOptions{passwordStdin: true, password: "from-flag"}, getenv: env, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			err := opts.readPassword(strings.NewReader(tt.stdin), tt.getenv)
+			if tt.err {
+				if err == nil {
+					t.Error("expect error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.password != tt.password {
+				t.Errorf("expect password %q, got %q", tt.password, opts.password)
+			}
+		})
+	}
+}
+
+func TestRepoList(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1beta1.ChartRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"},
+			Spec:       v1beta1.ChartRepoSpec{URL: "https://charts.example.com"},
+			Status:     v1beta1.ChartRepoStatus{Phase: v1beta1.ChartRepoSynced, ChartCount: 3},
+		},
+		&v1beta1.ChartRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "kube-system"},
+			Spec:       v1beta1.ChartRepoSpec{URL: "https://other.example.com"},
+		},
+		&v1beta1.ClusterChartRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: "public"},
+			Spec:       v1beta1.ChartRepoSpec{URL: "https://public.example.com"},
+		},
+	)
+
+	out := &bytes.Buffer{}
+	if err := runRepoList(context.Background(), out, client, "default", outputTable); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expect header and 2 rows, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "Namespace" || fields[2] != "stable" || fields[6] != "3" {
+		t.Errorf("unexpected row: %s", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); fields[0] != "Cluster" || fields[2] != "public" {
+		t.Errorf("unexpected row: %s", lines[2])
+	}
+
+	out.Reset()
+	if err := runRepoList(context.Background(), out, client, metav1.NamespaceAll, outputYAML); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"stable", "other", "public"} {
+		if !strings.Contains(out.String(), "name: "+name) {
+			t.Errorf("expect %s in the output:\n%s", name, out.String())
+		}
+	}
+}
+
+func TestRepoRefresh(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1beta1.ChartRepo{ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"}},
+		&v1beta1.ClusterChartRepo{ObjectMeta: metav1.ObjectMeta{Name: "public"}},
+	)
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+	if err := runRepoRefresh(ctx, out, client, "default", "stable", testNow); err != nil {
+		t.Fatal(err)
+	}
+	cr, _ := client.AppV1beta1().ChartRepos("default").Get(ctx, "stable", metav1.GetOptions{})
+	if v := cr.Annotations[v1beta1.ResyncAnnotation]; v != testNowValue {
+		t.Errorf("expect annotation %s, got %s", testNowValue, v)
+	}
+	if err := runRepoRefresh(ctx, out, client, "", "public", testNow); err != nil {
+		t.Fatal(err)
+	}
+	ccr, _ := client.AppV1beta1().ClusterChartRepos().Get(ctx, "public", metav1.GetOptions{})
+	if v := ccr.Annotations[v1beta1.ResyncAnnotation]; v != testNowValue {
+		t.Errorf("expect annotation %s, got %s", testNowValue, v)
+	}
+	if err := runRepoRefresh(ctx, out, client, "default", "missing", testNow); err == nil {
+		t.Error("expect error for missing repo")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// searchResult is a chart version matches the keyword
+type searchResult struct {
+	Scope       string `json:"scope"`
+	Namespace   string `json:"namespace,omitempty"`
+	Repo        string `json:"repo"`
+	Chart       string `json:"chart"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func newSearchCmd() *cobra.Command {
+	var output string
+	var versions bool
+	cmd := &cobra.Command{
+		Use:   "search [KEYWORD]",
+		Short: "Search the Charts in the namespace and the ClusterCharts",
+		Long: `Search the Charts in the namespace and the ClusterCharts by the keyword in their names,
+descriptions and keywords. All charts are listed if no keyword is given.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutput(output); err != nil {
+				return err
+			}
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			keyword := ""
+			if len(args) > 0 {
+				keyword = args[0]
+			}
+			return runSearch(context.Background(), cmd.OutOrStdout(), client, ns, keyword, versions, output)
+		},
+	}
+	addOutputFlag(cmd, &output)
+	cmd.Flags().BoolVar(&versions, "versions", false, "show all versions instead of the latest one")
+	return cmd
+}
+
+func runSearch(ctx context.Context, out io.Writer, client versioned.Interface, namespace, keyword string, versions bool, output string) error {
+	charts, err := client.AppV1beta1().Charts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	clusterCharts, err := client.AppV1beta1().ClusterCharts().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var results []searchResult
+	for i := range charts.Items {
+		results = append(results, searchChart(&charts.Items[i], "Namespace", keyword, versions)...)
+	}
+	for i := range clusterCharts.Items {
+		ch := clusterCharts.Items[i].AsChart()
+		results = append(results, searchChart(ch, "Cluster", keyword, versions)...)
+	}
+	// versions of a chart are already sorted from newest to oldest
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		if a.Chart != b.Chart {
+			return a.Chart < b.Chart
+		}
+		return a.Scope > b.Scope
+	})
+
+	if output != outputTable {
+		if results == nil {
+			results = []searchResult{}
+		}
+		return printObject(out, output, results)
+	}
+	t := newTable(out, "NAME", "CHART VERSION", "APP VERSION", "SCOPE", "DESCRIPTION")
+	for _, r := range results {
+		t.row(r.Repo+"/"+r.Chart, r.Version, orNone(r.AppVersion), r.Scope, r.Description)
+	}
+	return t.flush()
+}
+
+// searchChart returns the versions of the chart if it matches the keyword, only the latest one unless
+// versions is true
+func searchChart(ch *v1beta1.Chart, scope, keyword string, versions bool) []searchResult {
+	repo := ch.Status.Repo
+	if repo == "" {
+		// the Chart is named as <chart>.<repo>, see v1beta1.ChartObjectName
+		if i := strings.Index(ch.Name, "."); i >= 0 {
+			repo = ch.Name[i+1:]
+		}
+	}
+
+	var result []searchResult
+	for _, cv := range ch.SortedVersions() {
+		if cv.Removed || !matchChart(cv, keyword) {
+			continue
+		}
+		result = append(result, searchResult{
+			Scope:       scope,
+			Namespace:   ch.Namespace,
+			Repo:        repo,
+			Chart:       cv.Name,
+			Version:     cv.Version,
+			AppVersion:  cv.AppVersion,
+			Description: cv.Description,
+		})
+		if !versions {
+			break
+		}
+	}
+	return result
+}
+
+// matchChart checks whether the keyword is in the name, description or keywords, case insensitive
+func matchChart(cv *v1beta1.ChartVersion, keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	if strings.Contains(strings.ToLower(cv.Name), keyword) || strings.Contains(strings.ToLower(cv.Description), keyword) {
+		return true
+	}
+	for _, k := range cv.Keywords {
+		if strings.Contains(strings.ToLower(k), keyword) {
+			return true
+		}
+	}
+	return false
+}
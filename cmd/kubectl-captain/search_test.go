@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newChartVersions(name, description string, versions ...string) v1beta1.ChartSpec {
+	spec := v1beta1.ChartSpec{}
+	for _, v := range versions {
+		spec.Versions = append(spec.Versions, &v1beta1.ChartVersion{
+			ChartMetadata: v1beta1.ChartMetadata{Name: name, Version: v, Description: description},
+		})
+	}
+	return spec
+}
+
+func TestSearch(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1beta1.Chart{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx.stable"},
+			Spec:       newChartVersions("nginx", "web server", "1.0.0", "1.1.0"),
+		},
+		&v1beta1.Chart{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "redis.stable"},
+			Spec:       newChartVersions("redis", "key value store", "5.0.0"),
+		},
+		&v1beta1.Chart{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "nginx.other"},
+			Spec:       newChartVersions("nginx", "web server", "2.0.0"),
+		},
+		&v1beta1.ClusterChart{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx-ingress.public"},
+			Spec:       newChartVersions("nginx-ingress", "ingress controller", "0.1.0"),
+		},
+	)
+
+	tests := []struct {
+		name     string
+		keyword  string
+		versions bool
+		expect   []string
+	}{
+		{name: "all", expect: []string{"public/nginx-ingress@0.1.0", "stable/nginx@1.1.0", "stable/redis@5.0.0"}},
+		{name: "keyword", keyword: "NGINX", expect: []string{"public/nginx-ingress@0.1.0", "stable/nginx@1.1.0"}},
+		{name: "description", keyword: "store", expect: []string{"stable/redis@5.0.0"}},
+		{name: "versions", keyword: "web", versions: true, expect: []string{"stable/nginx@1.1.0", "stable/nginx@1.0.0"}},
+		{name: "not found", keyword: "mysql", expect: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := runSearch(context.Background(), out, client, "default", tt.keyword, tt.versions, outputJSON); err != nil {
+				t.Fatal(err)
+			}
+			var results []searchResult
+			if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, r := range results {
+				got = append(got, r.Repo+"/"+r.Chart+"@"+r.Version)
+			}
+			if len(got) != len(tt.expect) {
+				t.Fatalf("expect %v, got %v", tt.expect, got)
+			}
+			for i := range got {
+				if got[i] != tt.expect[i] {
+					t.Errorf("expect %v, got %v", tt.expect, got)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync NAME",
+		Short: "Force captain to sync a HelmRequest, even if it's not changed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, _, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			return runSync(context.Background(), cmd.OutOrStdout(), client, ns, args[0], time.Now())
+		},
+	}
+	return cmd
+}
+
+func runSync(ctx context.Context, out io.Writer, client versioned.Interface, namespace, name string, now time.Time) error {
+	hr, err := client.AppV1beta1().HelmRequests(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := annotate(ctx, client, hr, v1beta1.SyncAnnotation, now.UTC().Format(time.RFC3339Nano)); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "HelmRequest %s/%s is requested to sync\n", namespace, name)
+	return nil
+}
+
+// annotate sets the annotation of the HelmRequest
+func annotate(ctx context.Context, client versioned.Interface, hr *v1beta1.HelmRequest, key, value string) error {
+	hr = hr.DeepCopy()
+	hr.SetAnnotations(setAnnotation(hr.GetAnnotations(), key, value))
+	_, err := client.AppV1beta1().HelmRequests(hr.Namespace).Update(ctx, hr, metav1.UpdateOptions{})
+	return err
+}
+
+func setAnnotation(annotations map[string]string, key, value string) map[string]string {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	return annotations
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	testNow      = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	testNowValue = "2020-01-02T03:04:05Z"
+)
+
+func newHelmRequest(namespace, name, chart string) *v1beta1.HelmRequest {
+	return &v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1beta1.HelmRequestSpec{Chart: chart},
+	}
+}
+
+func TestSync(t *testing.T) {
+	hr := newHelmRequest("default", "nginx", "stable/nginx")
+	hr.Annotations = map[string]string{"foo": "bar"}
+	client := fake.NewSimpleClientset(hr)
+	ctx := context.Background()
+
+	out := &bytes.Buffer{}
+	if err := runSync(ctx, out, client, "default", "nginx", testNow); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := client.AppV1beta1().HelmRequests("default").Get(ctx, "nginx", metav1.GetOptions{})
+	if v := got.Annotations[v1beta1.SyncAnnotation]; v != testNowValue {
+		t.Errorf("expect annotation %s, got %s", testNowValue, v)
+	}
+	if got.Annotations["foo"] != "bar" {
+		t.Errorf("other annotations are lost: %v", got.Annotations)
+	}
+	if out.String() != "HelmRequest default/nginx is requested to sync\n" {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	if err := runSync(ctx, out, client, "default", "missing", testNow); err == nil {
+		t.Error("expect error for missing HelmRequest")
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	"github.com/alauda/helm-crds/pkg/values"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func newValuesCmd() *cobra.Command {
+	output := outputYAML
+	cmd := &cobra.Command{
+		Use:   "values NAME",
+		Short: "Show the effective values of a HelmRequest, including the values from ConfigMaps and Secrets",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != outputYAML && output != outputJSON {
+				return fmt.Errorf("unknown output format %s, must be yaml or json", output)
+			}
+			client, kubeClient, err := kube.clients()
+			if err != nil {
+				return err
+			}
+			ns, err := kube.getNamespace()
+			if err != nil {
+				return err
+			}
+			return runValues(context.Background(), cmd.OutOrStdout(), client, kubeClient, ns, args[0], output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", outputYAML, "output format, yaml or json")
+	return cmd
+}
+
+func runValues(ctx context.Context, out io.Writer, client versioned.Interface, kubeClient kubernetes.Interface, namespace, name, output string) error {
+	hr, err := client.AppV1beta1().HelmRequests(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	vals, err := values.NewClientResolver(kubeClient).Resolve(ctx, hr)
+	if err != nil {
+		return err
+	}
+	return printObject(out, output, vals)
+}
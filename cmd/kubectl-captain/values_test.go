@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValues(t *testing.T) {
+	hr := newHelmRequest("default", "nginx", "stable/nginx")
+	hr.Spec.ValuesFrom = []v1beta1.ValuesFromSource{{
+		ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "nginx-values"},
+			Key:                  "values.yaml",
+		},
+	}}
+	hr.Spec.Values = map[string]interface{}{"replicas": 2}
+	client := fake.NewSimpleClientset(hr)
+	kubeClient := kubefake.NewSimpleClientset(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx-values"},
+		Data:       map[string]string{"values.yaml": "replicas: 1\nimage: nginx\n"},
+	})
+
+	tests := []struct {
+		output string
+		expect string
+	}{
+		{output: outputYAML, expect: "image: nginx\nreplicas: 2\n"},
+		{output: outputJSON, expect: "{\n  \"image\": \"nginx\",\n  \"replicas\": 2\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := runValues(context.Background(), out, client, kubeClient, "default", "nginx", tt.output); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.expect {
+				t.Errorf("expect:\n%s\ngot:\n%s", tt.expect, out.String())
+			}
+		})
+	}
+
+	if err := runValues(context.Background(), &bytes.Buffer{}, client, kubefake.NewSimpleClientset(), "default", "nginx", outputYAML); err == nil {
+		t.Error("expect error for missing ConfigMap")
+	}
+}
@@ -0,0 +1,134 @@
+// Package access checks whether a HelmRequest is allowed to use the chart repo it refers to, based on the
+// namespace selector of the ChartRepo or ClusterChartRepo.
+package access
+
+import (
+	"fmt"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	listers "github.com/alauda/helm-crds/pkg/client/listers/app/v1beta1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// Checker checks HelmRequests against the namespace selectors of chart repos
+type Checker struct {
+	// ChartRepos and Namespaces are required
+	ChartRepos listers.ChartRepoLister
+	Namespaces corelisters.NamespaceLister
+	// ClusterChartRepos is used for HelmRequests refer to ClusterCharts, nil means ClusterCharts are
+	// not allowed
+	ClusterChartRepos listers.ClusterChartRepoLister
+	// Charts and ClusterCharts are used to find the repo when it's omitted in the chart reference. If
+	// they are nil, the repo must be specified
+	Charts        listers.ChartLister
+	ClusterCharts listers.ClusterChartLister
+	// RepoNamespace is the namespace of the ChartRepos when the chart reference has no namespace, default
+	// to the namespace of the HelmRequest
+	RepoNamespace string
+}
+
+// NewChecker creates a Checker for namespaced ChartRepos only
+func NewChecker(repos listers.ChartRepoLister, namespaces corelisters.NamespaceLister) *Checker {
+	return &Checker{ChartRepos: repos, Namespaces: namespaces}
+}
+
+// Check returns an error if the HelmRequest is not allowed to use the repo of its chart. Charts not in
+// repos(OCI and URL) are not restricted.
+func (c *Checker) Check(hr *v1beta1.HelmRequest) error {
+	ref, err := hr.GetChartReference()
+	if err != nil {
+		return err
+	}
+	if ref.Kind != v1beta1.ChartReferenceRepo {
+		return nil
+	}
+
+	ns, err := c.Namespaces.Get(hr.Namespace)
+	if err != nil {
+		return fmt.Errorf("get namespace %s error: %s", hr.Namespace, err.Error())
+	}
+
+	kind, repo, err := c.findRepo(hr, ref)
+	if err != nil {
+		return err
+	}
+
+	var allowed bool
+	var repoName string
+	if kind == v1beta1.ChartKindClusterChart {
+		cr, err := c.ClusterChartRepos.Get(repo)
+		if err != nil {
+			return fmt.Errorf("get cluster chart repo %s error: %s", repo, err.Error())
+		}
+		repoName = "cluster chart repo " + cr.Name
+		allowed, err = cr.AllowsNamespace(ns)
+		if err != nil {
+			return fmt.Errorf("%s has %s", repoName, err.Error())
+		}
+	} else {
+		repoNamespace := c.repoNamespace(hr, ref)
+		cr, err := c.ChartRepos.ChartRepos(repoNamespace).Get(repo)
+		if err != nil {
+			return fmt.Errorf("get chart repo %s/%s error: %s", repoNamespace, repo, err.Error())
+		}
+		repoName = fmt.Sprintf("chart repo %s/%s", cr.Namespace, cr.Name)
+		allowed, err = cr.AllowsNamespace(ns)
+		if err != nil {
+			return fmt.Errorf("%s has %s", repoName, err.Error())
+		}
+	}
+
+	if !allowed {
+		return fmt.Errorf("HelmRequest %s/%s is not allowed to use charts from %s, namespace %s is not selected by its namespaceSelector",
+			hr.Namespace, hr.Name, repoName, hr.Namespace)
+	}
+	return nil
+}
+
+func (c *Checker) repoNamespace(hr *v1beta1.HelmRequest, ref *v1beta1.ChartReference) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	if c.RepoNamespace != "" {
+		return c.RepoNamespace
+	}
+	return hr.Namespace
+}
+
+// findRepo returns the kind and name of the repo the HelmRequest refers to, it follows the same lookup
+// order as listers.ResolveChart
+func (c *Checker) findRepo(hr *v1beta1.HelmRequest, ref *v1beta1.ChartReference) (v1beta1.ChartKind, string, error) {
+	kind := hr.Spec.ChartKind
+	if kind == v1beta1.ChartKindClusterChart && c.ClusterChartRepos == nil {
+		return "", "", fmt.Errorf("cluster charts are not allowed for chart %s", hr.Spec.Chart)
+	}
+
+	// without cluster charts, or with a namespace in the reference, it can only be a Chart
+	if kind == "" && (c.ClusterChartRepos == nil || ref.Namespace != "") {
+		kind = v1beta1.ChartKindChart
+	}
+	if ref.Repo != "" && kind != "" {
+		return kind, ref.Repo, nil
+	}
+
+	// the repo or the kind is unknown, find the chart the same way the controller does
+	if c.Charts == nil {
+		return "", "", fmt.Errorf("chart %s should specify the repo and .spec.chartKind", hr.Spec.Chart)
+	}
+	lookup := hr.DeepCopy()
+	if c.RepoNamespace != "" && ref.Namespace == "" {
+		lookup.Namespace = c.RepoNamespace
+	}
+	clusterCharts := c.ClusterCharts
+	if c.ClusterChartRepos == nil {
+		clusterCharts = nil
+	}
+	chart, kind, err := listers.ResolveChart(c.Charts, clusterCharts, lookup)
+	if err != nil {
+		return "", "", fmt.Errorf("find chart %s error: %s", hr.Spec.Chart, err.Error())
+	}
+	if chart.Status.Repo == "" {
+		return "", "", fmt.Errorf("chart %s has no repo in its status", hr.Spec.Chart)
+	}
+	return kind, chart.Status.Repo, nil
+}
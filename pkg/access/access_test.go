@@ -0,0 +1,128 @@
+package access
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	listers "github.com/alauda/helm-crds/pkg/client/listers/app/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newIndexer(objs ...interface{}) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objs {
+		indexer.Add(obj)
+	}
+	return indexer
+}
+
+func newNamespace(name string, labels map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newChartRepo(namespace, name string, selector *metav1.LabelSelector) *v1beta1.ChartRepo {
+	return &v1beta1.ChartRepo{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1beta1.ChartRepoSpec{URL: "https://charts.example.com", NamespaceSelector: selector},
+	}
+}
+
+func newChart(namespace, repo, name string) *v1beta1.Chart {
+	return &v1beta1.Chart{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: v1beta1.ChartObjectName(repo, name)},
+		Spec: v1beta1.ChartSpec{
+			Versions: []*v1beta1.ChartVersion{{ChartMetadata: v1beta1.ChartMetadata{Name: name, Version: "1.0.0"}}},
+		},
+		Status: v1beta1.ChartStatus{Repo: repo},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	prod := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+	invalid := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}}}
+
+	namespaces := corelisters.NewNamespaceLister(newIndexer(
+		newNamespace("captain", nil),
+		newNamespace("prod", map[string]string{"env": "prod"}),
+		newNamespace("dev", map[string]string{"env": "dev"}),
+	))
+	repos := listers.NewChartRepoLister(newIndexer(
+		newChartRepo("captain", "all", nil),
+		newChartRepo("captain", "empty", &metav1.LabelSelector{}),
+		newChartRepo("captain", "prod", prod),
+		newChartRepo("captain", "invalid", invalid),
+		newChartRepo("dev", "local", prod),
+	))
+	clusterRepo := newChartRepo("", "public", prod)
+	clusterRepos := listers.NewClusterChartRepoLister(newIndexer(&v1beta1.ClusterChartRepo{
+		ObjectMeta: clusterRepo.ObjectMeta,
+		Spec:       clusterRepo.Spec,
+	}))
+	charts := listers.NewChartLister(newIndexer(newChart("captain", "prod", "nginx")))
+	clusterChart := newChart("", "public", "redis")
+	clusterCharts := listers.NewClusterChartLister(newIndexer(&v1beta1.ClusterChart{
+		ObjectMeta: clusterChart.ObjectMeta,
+		Spec:       clusterChart.Spec,
+		Status:     clusterChart.Status,
+	}))
+
+	tests := []struct {
+		name      string
+		namespace string
+		chart     string
+		kind      v1beta1.ChartKind
+		cluster   bool
+		lookup    bool
+		err       string
+	}{
+		{name: "no selector", namespace: "dev", chart: "all/nginx"},
+		{name: "empty selector", namespace: "dev", chart: "empty/nginx"},
+		{name: "selected namespace", namespace: "prod", chart: "prod/nginx"},
+		{name: "not selected namespace", namespace: "dev", chart: "prod/nginx", err: "is not allowed to use charts from chart repo captain/prod"},
+		{name: "namespace without labels", namespace: "captain", chart: "dev/local/nginx", err: "is not allowed"},
+		{name: "namespace of the repo", namespace: "dev", chart: "dev/local/nginx"},
+		{name: "invalid selector", namespace: "prod", chart: "invalid/nginx", err: "invalid namespace selector"},
+		{name: "missing repo", namespace: "prod", chart: "missing/nginx", err: "get chart repo captain/missing error"},
+		{name: "missing namespace", namespace: "test", chart: "all/nginx", err: "get namespace test error"},
+		{name: "oci", namespace: "dev", chart: "oci://example.com/charts/nginx:1.0.0"},
+		{name: "invalid chart", namespace: "dev", chart: "a/b/c/nginx", err: "chart"},
+		{name: "cluster chart", namespace: "prod", chart: "public/redis", kind: v1beta1.ChartKindClusterChart, cluster: true},
+		{name: "cluster chart denied", namespace: "dev", chart: "public/redis", kind: v1beta1.ChartKindClusterChart, cluster: true, err: "cluster chart repo public"},
+		{name: "cluster charts not allowed", namespace: "prod", chart: "public/redis", kind: v1beta1.ChartKindClusterChart, err: "cluster charts are not allowed"},
+		{name: "repo omitted", namespace: "dev", chart: "nginx", kind: v1beta1.ChartKindChart, err: "should specify the repo"},
+		{name: "lookup repo", namespace: "dev", chart: "nginx", lookup: true, err: "chart repo captain/prod"},
+		{name: "lookup cluster repo", namespace: "prod", chart: "public/redis", cluster: true, lookup: true},
+		{name: "lookup cluster repo denied", namespace: "dev", chart: "redis", cluster: true, lookup: true, err: "cluster chart repo public"},
+		{name: "lookup missing", namespace: "dev", chart: "mysql", lookup: true, err: "find chart mysql error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker(repos, namespaces)
+			c.RepoNamespace = "captain"
+			if tt.cluster {
+				c.ClusterChartRepos = clusterRepos
+			}
+			if tt.lookup {
+				c.Charts, c.ClusterCharts = charts, clusterCharts
+			}
+			hr := &v1beta1.HelmRequest{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace, Name: "test"},
+				Spec:       v1beta1.HelmRequestSpec{Chart: tt.chart, ChartKind: tt.kind},
+			}
+			err := c.Check(hr)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("expect allowed, got %s", err.Error())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expect error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
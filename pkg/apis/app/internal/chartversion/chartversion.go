@@ -0,0 +1,215 @@
+// Package chartversion converts the chart versions of the api versions from and to helm's types. It's
+// shared by the api versions: their ChartVersion types have the same fields as Version here, so they are
+// converted by pointers, the same as the generated conversions of kubernetes. The tests make sure the
+// types are kept identical.
+package chartversion
+
+import (
+	"encoding/json"
+
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/repo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+)
+
+// Version is ChartVersion of the api versions
+type Version struct {
+	Metadata
+	URLs    []string
+	Created metav1.Time
+	Removed bool
+	Digest  string
+}
+
+// Metadata is ChartMetadata of the api versions
+type Metadata struct {
+	Name         string
+	Home         string
+	Sources      []string
+	Version      string
+	Description  string
+	Keywords     []string
+	Maintainers  []Maintainer
+	Icon         string
+	APIVersion   string
+	Condition    string
+	Tags         string
+	AppVersion   string
+	Deprecated   bool
+	Annotations  map[string]string
+	KubeVersion  string
+	Dependencies []Dependency
+	Type         string
+}
+
+// Maintainer is ChartMaintainer of the api versions
+type Maintainer struct {
+	Name  string
+	Email string
+	URL   string
+}
+
+// Dependency is ChartDependency of the api versions
+type Dependency struct {
+	Name         string
+	Version      string
+	Repository   string
+	Condition    string
+	Tags         []string
+	Enabled      bool
+	ImportValues []runtime.RawExtension
+	Alias        string
+}
+
+// CopyFromRepo copies a ChartVersion from helm's repo index
+func (in *Version) CopyFromRepo(cv *repo.ChartVersion) {
+	*in = Version{
+		URLs:    copyStrings(cv.URLs),
+		Created: metav1.NewTime(cv.Created),
+		Removed: cv.Removed,
+		Digest:  cv.Digest,
+	}
+	if cv.Metadata != nil {
+		in.Metadata.CopyFromChart(cv.Metadata)
+	}
+}
+
+// ToRepo converts to helm's repo ChartVersion
+func (in *Version) ToRepo() *repo.ChartVersion {
+	return &repo.ChartVersion{
+		Metadata: in.Metadata.ToChart(),
+		URLs:     copyStrings(in.URLs),
+		Created:  in.Created.Time,
+		Removed:  in.Removed,
+		Digest:   in.Digest,
+	}
+}
+
+// NewVersions converts the versions of a chart in helm's repo index
+func NewVersions(versions repo.ChartVersions) []*Version {
+	var result []*Version
+	for _, cv := range versions {
+		if cv == nil {
+			continue
+		}
+		var v Version
+		v.CopyFromRepo(cv)
+		result = append(result, &v)
+	}
+	return result
+}
+
+// CopyFromChart copies from the metadata of a helm chart
+func (in *Metadata) CopyFromChart(md *chart.Metadata) {
+	*in = Metadata{
+		Name:        md.Name,
+		Home:        md.Home,
+		Sources:     copyStrings(md.Sources),
+		Version:     md.Version,
+		Description: md.Description,
+		Keywords:    copyStrings(md.Keywords),
+		Icon:        md.Icon,
+		APIVersion:  md.APIVersion,
+		Condition:   md.Condition,
+		Tags:        md.Tags,
+		AppVersion:  md.AppVersion,
+		Deprecated:  md.Deprecated,
+		KubeVersion: md.KubeVersion,
+		Type:        md.Type,
+	}
+	for _, m := range md.Maintainers {
+		if m != nil {
+			in.Maintainers = append(in.Maintainers, Maintainer{Name: m.Name, Email: m.Email, URL: m.URL})
+		}
+	}
+	if md.Annotations != nil {
+		in.Annotations = make(map[string]string, len(md.Annotations))
+		for k, v := range md.Annotations {
+			in.Annotations[k] = v
+		}
+	}
+	for _, d := range md.Dependencies {
+		if d == nil {
+			continue
+		}
+		dep := Dependency{
+			Name:       d.Name,
+			Version:    d.Version,
+			Repository: d.Repository,
+			Condition:  d.Condition,
+			Tags:       copyStrings(d.Tags),
+			Enabled:    d.Enabled,
+			Alias:      d.Alias,
+		}
+		for _, v := range d.ImportValues {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				klog.Warningf("skip invalid import-values of dependency %s: %s", d.Name, err.Error())
+				continue
+			}
+			dep.ImportValues = append(dep.ImportValues, runtime.RawExtension{Raw: raw})
+		}
+		in.Dependencies = append(in.Dependencies, dep)
+	}
+}
+
+// ToChart converts to the metadata of a helm chart
+func (in *Metadata) ToChart() *chart.Metadata {
+	md := &chart.Metadata{
+		Name:        in.Name,
+		Home:        in.Home,
+		Sources:     copyStrings(in.Sources),
+		Version:     in.Version,
+		Description: in.Description,
+		Keywords:    copyStrings(in.Keywords),
+		Icon:        in.Icon,
+		APIVersion:  in.APIVersion,
+		Condition:   in.Condition,
+		Tags:        in.Tags,
+		AppVersion:  in.AppVersion,
+		Deprecated:  in.Deprecated,
+		KubeVersion: in.KubeVersion,
+		Type:        in.Type,
+	}
+	for _, m := range in.Maintainers {
+		md.Maintainers = append(md.Maintainers, &chart.Maintainer{Name: m.Name, Email: m.Email, URL: m.URL})
+	}
+	if in.Annotations != nil {
+		md.Annotations = make(map[string]string, len(in.Annotations))
+		for k, v := range in.Annotations {
+			md.Annotations[k] = v
+		}
+	}
+	for _, d := range in.Dependencies {
+		dep := &chart.Dependency{
+			Name:       d.Name,
+			Version:    d.Version,
+			Repository: d.Repository,
+			Condition:  d.Condition,
+			Tags:       copyStrings(d.Tags),
+			Enabled:    d.Enabled,
+			Alias:      d.Alias,
+		}
+		for _, raw := range d.ImportValues {
+			var v interface{}
+			if err := json.Unmarshal(raw.Raw, &v); err != nil {
+				klog.Warningf("skip invalid import-values of dependency %s: %s", d.Name, err.Error())
+				continue
+			}
+			dep.ImportValues = append(dep.ImportValues, v)
+		}
+		md.Dependencies = append(md.Dependencies, dep)
+	}
+	return md
+}
+
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
@@ -0,0 +1,202 @@
+package chartversion_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/internal/chartversion"
+	"github.com/alauda/helm-crds/pkg/apis/app/v1alpha1"
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/repo"
+)
+
+// sameLayout checks the two types can be converted by pointers: the structs have the same fields in the
+// same order, and the other types are the same
+func sameLayout(t *testing.T, path string, a, b reflect.Type) {
+	if a == b {
+		return
+	}
+	if a.Kind() != b.Kind() || a.Size() != b.Size() {
+		t.Errorf("%s: %s and %s are different", path, a, b)
+		return
+	}
+	switch a.Kind() {
+	case reflect.Struct:
+		if a.NumField() != b.NumField() {
+			t.Errorf("%s: %s has %d fields, %s has %d", path, a, a.NumField(), b, b.NumField())
+			return
+		}
+		for i := 0; i < a.NumField(); i++ {
+			fa, fb := a.Field(i), b.Field(i)
+			// embedded fields are named by their types
+			if fa.Anonymous != fb.Anonymous || (!fa.Anonymous && fa.Name != fb.Name) || fa.Offset != fb.Offset {
+				t.Errorf("%s: field %d is %s of %s and %s of %s", path, i, fa.Name, a, fb.Name, b)
+				continue
+			}
+			sameLayout(t, path+"."+fa.Name, fa.Type, fb.Type)
+		}
+	case reflect.Slice, reflect.Ptr:
+		sameLayout(t, path+"[]", a.Elem(), b.Elem())
+	case reflect.Map:
+		sameLayout(t, path+"[key]", a.Key(), b.Key())
+		sameLayout(t, path+"[]", a.Elem(), b.Elem())
+	default:
+		t.Errorf("%s: %s and %s are different", path, a, b)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	expect := reflect.TypeOf(chartversion.Version{})
+	sameLayout(t, "v1alpha1", reflect.TypeOf(v1alpha1.ChartVersion{}), expect)
+	sameLayout(t, "v1beta1", reflect.TypeOf(v1beta1.ChartVersion{}), expect)
+	sameLayout(t, "v1alpha1", reflect.TypeOf(v1alpha1.ChartMetadata{}), reflect.TypeOf(chartversion.Metadata{}))
+	sameLayout(t, "v1beta1", reflect.TypeOf(v1beta1.ChartMetadata{}), reflect.TypeOf(chartversion.Metadata{}))
+}
+
+func newRepoChartVersion() *repo.ChartVersion {
+	return &repo.ChartVersion{
+		Metadata: &chart.Metadata{
+			Name:        "nginx",
+			Home:        "https://nginx.org",
+			Sources:     []string{"https://github.com/nginx/nginx"},
+			Version:     "1.0.0",
+			Description: "nginx server",
+			Keywords:    []string{"web", "proxy"},
+			Maintainers: []*chart.Maintainer{{Name: "captain", Email: "captain@example.com", URL: "https://example.com"}},
+			Icon:        "https://nginx.org/icon.png",
+			APIVersion:  "v2",
+			Condition:   "nginx.enabled",
+			Tags:        "web",
+			AppVersion:  "1.17.3",
+			Deprecated:  true,
+			Annotations: map[string]string{"category": "web"},
+			KubeVersion: ">=1.13.0",
+			Dependencies: []*chart.Dependency{
+				{
+					Name:         "redis",
+					Version:      "~9.0.0",
+					Repository:   "https://charts.example.com",
+					Condition:    "redis.enabled",
+					Tags:         []string{"cache"},
+					Enabled:      true,
+					ImportValues: []interface{}{"data", map[string]interface{}{"child": "exports.port", "parent": "port"}},
+					Alias:        "cache",
+				},
+			},
+			Type: "application",
+		},
+		URLs:    []string{"charts/nginx-1.0.0.tgz"},
+		Created: time.Date(2019, 8, 29, 2, 18, 52, 123456789, time.UTC),
+		Removed: true,
+		Digest:  "sha256:0123",
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	expect := newRepoChartVersion()
+
+	var beta v1beta1.ChartVersion
+	beta.CopyFromRepoChartVersion(expect)
+	if got := beta.ToRepoChartVersion(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("v1beta1: expect %+v, got %+v", expect, got)
+	}
+	if !beta.Created.Time.Equal(expect.Created) || len(beta.Dependencies) != 1 || len(beta.Dependencies[0].ImportValues) != 2 {
+		t.Errorf("v1beta1: unexpected version %+v", beta)
+	}
+
+	var alpha v1alpha1.ChartVersion
+	alpha.CopyFromRepoChartVersion(expect)
+	if got := alpha.ToRepoChartVersion(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("v1alpha1: expect %+v, got %+v", expect, got)
+	}
+
+	// the copies don't share anything with the source
+	beta.Keywords[0] = "changed"
+	beta.Annotations["category"] = "changed"
+	beta.Dependencies[0].Tags[0] = "changed"
+	if expect.Keywords[0] != "web" || expect.Annotations["category"] != "web" || expect.Dependencies[0].Tags[0] != "cache" {
+		t.Errorf("expect the source not changed, got %+v", expect.Metadata)
+	}
+
+	versions := v1beta1.NewChartVersions(repo.ChartVersions{nil, newRepoChartVersion(), &repo.ChartVersion{URLs: []string{"a.tgz"}}})
+	if len(versions) != 2 || versions[0].Name != "nginx" || versions[1].URLs[0] != "a.tgz" {
+		t.Errorf("unexpected versions %+v", versions)
+	}
+	if md := versions[1].ToChartMetadata(); md.Name != "" || md.Dependencies != nil {
+		t.Errorf("expect empty metadata, got %+v", md)
+	}
+}
+
+// storedChart is a Chart stored before ChartVersion is defined in the api package, when the versions
+// are helm's repo.ChartVersion
+const storedChart = `{
+  "apiVersion": "app.alauda.io/v1beta1",
+  "kind": "Chart",
+  "metadata": {"name": "nginx.stable", "namespace": "default"},
+  "spec": {
+    "versions": [
+      {
+        "name": "nginx",
+        "version": "1.0.0",
+        "appVersion": "1.17.3",
+        "apiVersion": "v1",
+        "maintainers": [{"name": "captain", "email": "captain@example.com"}],
+        "dependencies": [
+          {"name": "redis", "version": "~9.0.0", "repository": "https://charts.example.com", "alias": "cache",
+           "import-values": ["data", {"child": "exports.port", "parent": "port"}]}
+        ],
+        "urls": ["https://charts.example.com/nginx-1.0.0.tgz"],
+        "created": "2019-08-29T02:18:52.123456789Z",
+        "digest": "0123"
+      }
+    ]
+  }
+}`
+
+func TestDecodeStoredChart(t *testing.T) {
+	var helm struct {
+		Spec struct {
+			Versions []*repo.ChartVersion `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(storedChart), &helm); err != nil {
+		t.Fatal(err)
+	}
+	expect := helm.Spec.Versions[0]
+
+	var beta v1beta1.Chart
+	if err := json.Unmarshal([]byte(storedChart), &beta); err != nil {
+		t.Fatal(err)
+	}
+	var alpha v1alpha1.Chart
+	if err := json.Unmarshal([]byte(storedChart), &alpha); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, got := range []*repo.ChartVersion{beta.Spec.Versions[0].ToRepoChartVersion(), alpha.Spec.Versions[0].ToRepoChartVersion()} {
+		// metav1.Time is decoded in local time
+		if !got.Created.Equal(expect.Created) {
+			t.Errorf("expect created %s, got %s", expect.Created, got.Created)
+		}
+		got.Created = expect.Created
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("expect %+v, got %+v", expect.Metadata, got.Metadata)
+		}
+	}
+
+	// and it's stored in the same format again
+	data, err := json.Marshal(beta.Spec.Versions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded repo.ChartVersion
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != "nginx" || decoded.Dependencies[0].Alias != "cache" || decoded.URLs[0] != expect.URLs[0] {
+		t.Errorf("unexpected encoded version %s", data)
+	}
+}
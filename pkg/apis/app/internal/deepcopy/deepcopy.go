@@ -0,0 +1,98 @@
+// Package deepcopy copies the values decoded from json or yaml, eg: helm values, without a round trip
+// through the encoding. It's shared by the api versions.
+package deepcopy
+
+import (
+	"reflect"
+
+	"helm.sh/helm/pkg/chartutil"
+)
+
+// Map copies a map decoded from json or yaml recursively
+func Map(in map[string]interface{}) map[string]interface{} {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[k] = Value(v)
+	}
+	return out
+}
+
+func slice(in []interface{}) []interface{} {
+	if in == nil {
+		return nil
+	}
+	out := make([]interface{}, len(in))
+	for i, v := range in {
+		out[i] = Value(v)
+	}
+	return out
+}
+
+// Value copies a value decoded from json or yaml. The common types are handled directly,
+// other maps and slices are copied by reflection, and the rest are treated as immutable scalars.
+func Value(in interface{}) interface{} {
+	switch v := in.(type) {
+	case nil, string, bool, int, int32, int64, float32, float64:
+		return v
+	case map[string]interface{}:
+		return Map(v)
+	case chartutil.Values:
+		return chartutil.Values(Map(v))
+	case []interface{}:
+		return slice(v)
+	case map[interface{}]interface{}:
+		if v == nil {
+			return v
+		}
+		out := make(map[interface{}]interface{}, len(v))
+		for k, item := range v {
+			out[k] = Value(item)
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(in)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return in
+		}
+		out := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for _, key := range rv.MapKeys() {
+			out.SetMapIndex(key, reflectValue(rv.MapIndex(key), rv.Type().Elem()))
+		}
+		return out.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return in
+		}
+		out := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out.Index(i).Set(reflectValue(rv.Index(i), rv.Type().Elem()))
+		}
+		return out.Interface()
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return in
+		}
+		out := reflect.New(rv.Elem().Type())
+		out.Elem().Set(reflectValue(rv.Elem(), rv.Elem().Type()))
+		return out.Interface()
+	}
+	return in
+}
+
+// reflectValue copies v and converts it back to type t, nil interfaces are kept as zero values
+func reflectValue(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return reflect.Zero(t)
+	}
+	copied := Value(v.Interface())
+	if copied == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(copied).Convert(t)
+}
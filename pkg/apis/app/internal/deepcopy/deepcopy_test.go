@@ -0,0 +1,177 @@
+package deepcopy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/pkg/chartutil"
+)
+
+// yamlCopy is how the values were copied before, by a yaml round trip
+func yamlCopy(in map[string]interface{}) map[string]interface{} {
+	b, err := yaml.Marshal(in)
+	if err != nil {
+		return nil
+	}
+	var out chartutil.Values
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+var strs = []string{"", "a", "nginx", "true", "null", "1", "1.5", "a: b", "- x", "{}", "中文", "line\nbreak"}
+
+// randomValues returns random values like the ones decoded from yaml, with the depth limited
+func randomValues(r *rand.Rand, depth int) map[string]interface{} {
+	out := map[string]interface{}{}
+	for i := r.Intn(6); i > 0; i-- {
+		out[strs[r.Intn(len(strs))]+string(rune('a'+r.Intn(26)))] = randomValue(r, depth-1)
+	}
+	return out
+}
+
+func randomValue(r *rand.Rand, depth int) interface{} {
+	n := 6
+	if depth > 0 {
+		n = 9
+	}
+	switch r.Intn(n) {
+	case 0:
+		return nil
+	case 1:
+		return strs[r.Intn(len(strs))]
+	case 2:
+		return r.Intn(2) == 0
+	case 3:
+		// keep in the range float64 represents exactly, as the yaml round trip decodes numbers to float64
+		return int64(r.Int31()) - int64(r.Int31())
+	case 4:
+		return r.Int()%1000 - 500
+	case 5:
+		return r.NormFloat64()
+	case 6:
+		return randomValues(r, depth)
+	case 7:
+		return chartutil.Values(randomValues(r, depth))
+	default:
+		s := make([]interface{}, r.Intn(4))
+		for i := range s {
+			s[i] = randomValue(r, depth-1)
+		}
+		return s
+	}
+}
+
+// mutate changes every map and slice in the values, to check nothing is shared with the copy
+func mutate(in interface{}) {
+	switch v := in.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			mutate(item)
+			v[k] = "mutated"
+		}
+		v["mutated"] = true
+	case chartutil.Values:
+		mutate(map[string]interface{}(v))
+	case []interface{}:
+		for i, item := range v {
+			mutate(item)
+			v[i] = "mutated"
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestMapFuzz(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		in := randomValues(r, 4)
+		before := mustJSON(t, in)
+
+		out := Map(in)
+		if !reflect.DeepEqual(in, out) {
+			t.Fatalf("copy is not equal to the original: %s", before)
+		}
+		if got, expect := mustJSON(t, out), mustJSON(t, yamlCopy(in)); got != expect {
+			t.Fatalf("expect the same as yaml copy %s, got %s", expect, got)
+		}
+
+		mutate(out)
+		if after := mustJSON(t, in); after != before {
+			t.Fatalf("original is changed by the copy, expect %s, got %s", before, after)
+		}
+	}
+}
+
+func TestValue(t *testing.T) {
+	type item struct {
+		Name string
+	}
+	tests := []struct {
+		name string
+		in   interface{}
+	}{
+		{name: "nil", in: nil},
+		{name: "nil map", in: map[string]interface{}(nil)},
+		{name: "nil slice", in: []interface{}(nil)},
+		{name: "yaml v2 map", in: map[interface{}]interface{}{"a": []interface{}{1, "b"}, 1: nil}},
+		{name: "typed slice", in: []string{"a", "b"}},
+		{name: "typed map", in: map[string][]int{"a": {1, 2}}},
+		{name: "pointer", in: &item{Name: "a"}},
+		{name: "struct", in: item{Name: "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := Value(tt.in)
+			if !reflect.DeepEqual(tt.in, out) {
+				t.Errorf("expect %#v, got %#v", tt.in, out)
+			}
+		})
+	}
+
+	in := map[string]interface{}{"list": []string{"a"}, "ptr": &item{Name: "a"}}
+	out := Map(in)
+	out["list"].([]string)[0] = "b"
+	out["ptr"].(*item).Name = "b"
+	if in["list"].([]string)[0] != "a" || in["ptr"].(*item).Name != "a" {
+		t.Errorf("original is changed by the copy: %v", in)
+	}
+}
+
+// benchmarkValues returns values about the size of a common chart's values
+func benchmarkValues() map[string]interface{} {
+	r := rand.New(rand.NewSource(1))
+	out := map[string]interface{}{}
+	for i := 0; i < 50; i++ {
+		out[fmt.Sprintf("key%d", i)] = randomValues(r, 4)
+	}
+	return out
+}
+
+func BenchmarkDeepCopyMap(b *testing.B) {
+	in := benchmarkValues()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Map(in)
+	}
+}
+
+func BenchmarkDeepCopyMapYAML(b *testing.B) {
+	in := benchmarkValues()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		yamlCopy(in)
+	}
+}
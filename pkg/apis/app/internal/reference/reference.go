@@ -0,0 +1,198 @@
+// Package reference parses the chart references of HelmRequests, it's shared by the api versions.
+package reference
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// Kind is the kind of a chart reference
+type Kind string
+
+const (
+	// Repo refers to a chart in a ChartRepo, eg: stable/nginx, nginx, default/stable/nginx
+	Repo Kind = "Repo"
+	// OCI refers to a chart in an OCI registry, eg: oci://example.com/charts/nginx:1.0.0
+	OCI Kind = "OCI"
+	// URL refers to a chart archive by url, eg: https://example.com/charts/nginx-1.0.0.tgz
+	URL Kind = "URL"
+)
+
+// Reference is the parsed form of the chart of a HelmRequest. The formats are
+// [[<namespace>/]<repo>/]<chart>[@<version>] for charts in ChartRepos,
+// oci://<host>/<path>/<chart>[:<tag>|@<version>] for charts in OCI registries and
+// http(s)://<host>/<path>/<chart>-<version>.tgz for chart archives.
+type Reference struct {
+	Kind Kind
+	// Namespace is the namespace of the ChartRepo, empty means the namespace of the ChartRepos
+	// captain watches. Only for Repo kind
+	Namespace string
+	// Repo is the name of the ChartRepo, only for Repo kind
+	Repo string
+	// Chart is the name of the chart
+	Chart string
+	// URL is the url of the chart without version, only for OCI and URL kind
+	URL string
+	// Version is the chart version, may be empty
+	Version string
+	// tagged is true if the version of an OCI reference is parsed from a tag, so it's kept as a tag
+	tagged bool
+}
+
+var chartNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9])?$`)
+
+// Parse parses a chart reference, see Reference for the formats
+func Parse(s string) (*Reference, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("chart is empty")
+	}
+
+	switch {
+	case strings.HasPrefix(s, "oci://"):
+		return parseOCIReference(s)
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		return parseURLReference(s)
+	case strings.Contains(s, "://"):
+		return nil, fmt.Errorf("unsupported chart url scheme: %s", s)
+	}
+
+	ref := &Reference{Kind: Repo}
+	name, version, err := splitVersion(s, "@")
+	if err != nil {
+		return nil, err
+	}
+	ref.Version = version
+
+	parts := strings.Split(name, "/")
+	switch len(parts) {
+	case 1:
+		ref.Chart = parts[0]
+	case 2:
+		ref.Repo, ref.Chart = parts[0], parts[1]
+	case 3:
+		ref.Namespace, ref.Repo, ref.Chart = parts[0], parts[1], parts[2]
+	default:
+		return nil, fmt.Errorf("invalid chart %s: too many segments", s)
+	}
+	for _, p := range parts {
+		if !chartNameRegex.MatchString(p) {
+			return nil, fmt.Errorf("invalid chart %s: invalid segment %q", s, p)
+		}
+	}
+	return ref, nil
+}
+
+func parseOCIReference(s string) (*Reference, error) {
+	ref := &Reference{Kind: OCI}
+
+	name, version, err := splitVersion(s, "@")
+	if err != nil {
+		return nil, err
+	}
+	// a tag in the last segment is the version too, but the port of the host is not
+	if version == "" {
+		if slash := strings.LastIndex(name, "/"); slash >= len("oci://") {
+			if i := strings.LastIndex(name[slash:], ":"); i >= 0 {
+				name, version = name[:slash+i], name[slash+i+1:]
+				if version == "" {
+					return nil, fmt.Errorf("invalid chart %s: malformed tag", s)
+				}
+				ref.tagged = true
+			}
+		}
+	}
+	ref.Version = version
+
+	u, err := url.Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart %s: %s", s, err.Error())
+	}
+	if u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return nil, fmt.Errorf("invalid chart %s: host and path are required", s)
+	}
+	ref.Chart = path.Base(u.Path)
+	if !chartNameRegex.MatchString(ref.Chart) {
+		return nil, fmt.Errorf("invalid chart %s: invalid chart name %q", s, ref.Chart)
+	}
+	ref.URL = name
+	return ref, nil
+}
+
+func parseURLReference(s string) (*Reference, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart %s: %s", s, err.Error())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid chart %s: host is required", s)
+	}
+	file := path.Base(u.Path)
+	if !strings.HasSuffix(file, ".tgz") {
+		return nil, fmt.Errorf("invalid chart %s: url should point to a .tgz archive", s)
+	}
+	name := strings.TrimSuffix(file, ".tgz")
+
+	ref := &Reference{Kind: URL, URL: s, Chart: name}
+	// archives are named <chart>-<version>.tgz, and both may contain "-"
+	for i := 0; i < len(name); i++ {
+		if name[i] != '-' {
+			continue
+		}
+		if _, err := semver.NewVersion(name[i+1:]); err == nil {
+			ref.Chart, ref.Version = name[:i], name[i+1:]
+			break
+		}
+	}
+	if !chartNameRegex.MatchString(ref.Chart) {
+		return nil, fmt.Errorf("invalid chart %s: invalid chart name %q", s, ref.Chart)
+	}
+	return ref, nil
+}
+
+// splitVersion splits s at the last sep, the version should not be empty or contain "/"
+func splitVersion(s, sep string) (string, string, error) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", nil
+	}
+	name, version := s[:i], s[i+1:]
+	if name == "" || version == "" || strings.Contains(version, "/") {
+		return "", "", fmt.Errorf("invalid chart %s: malformed version", s)
+	}
+	return name, version, nil
+}
+
+// String returns the reference in the format it's parsed from, the version of an OCI reference is
+// written as a tag only if it's parsed from a tag
+func (r *Reference) String() string {
+	switch r.Kind {
+	case OCI:
+		if r.Version == "" {
+			return r.URL
+		}
+		if r.tagged {
+			return r.URL + ":" + r.Version
+		}
+		return r.URL + "@" + r.Version
+	case URL:
+		return r.URL
+	}
+
+	s := r.Chart
+	if r.Repo != "" {
+		s = r.Repo + "/" + s
+	}
+	if r.Namespace != "" {
+		s = r.Namespace + "/" + s
+	}
+	if r.Version != "" {
+		s = s + "@" + r.Version
+	}
+	return s
+}
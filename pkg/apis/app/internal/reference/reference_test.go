@@ -0,0 +1,75 @@
+package reference
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect Reference
+		str    string
+	}{
+		{in: "nginx", expect: Reference{Kind: Repo, Chart: "nginx"}},
+		{in: "stable/nginx", expect: Reference{Kind: Repo, Repo: "stable", Chart: "nginx"}},
+		{in: "default/stable/nginx@1.0.0", expect: Reference{Kind: Repo, Namespace: "default", Repo: "stable", Chart: "nginx", Version: "1.0.0"}},
+		{in: " stable/nginx ", expect: Reference{Kind: Repo, Repo: "stable", Chart: "nginx"}, str: "stable/nginx"},
+		{in: "oci://example.com/charts/nginx", expect: Reference{Kind: OCI, URL: "oci://example.com/charts/nginx", Chart: "nginx"}},
+		{in: "oci://example.com:5000/charts/nginx:1.0.0", expect: Reference{Kind: OCI, URL: "oci://example.com:5000/charts/nginx", Chart: "nginx", Version: "1.0.0", tagged: true}},
+		{in: "oci://example.com:5000/charts/nginx@1.0.0", expect: Reference{Kind: OCI, URL: "oci://example.com:5000/charts/nginx", Chart: "nginx", Version: "1.0.0"}},
+		{in: "https://example.com/charts/nginx-ingress-1.0.0-rc.1.tgz", expect: Reference{Kind: URL, URL: "https://example.com/charts/nginx-ingress-1.0.0-rc.1.tgz", Chart: "nginx-ingress", Version: "1.0.0-rc.1"}},
+		{in: "https://example.com/charts/nginx.tgz", expect: Reference{Kind: URL, URL: "https://example.com/charts/nginx.tgz", Chart: "nginx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			ref, err := Parse(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *ref != tt.expect {
+				t.Errorf("expect %+v, got %+v", tt.expect, *ref)
+			}
+			str := tt.str
+			if str == "" {
+				str = tt.in
+			}
+			if ref.String() != str {
+				t.Errorf("expect %s, got %s", str, ref.String())
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"a/b/c/d",
+		"stable/nginx@",
+		"@1.0.0",
+		"stable/-nginx",
+		"ftp://example.com/nginx.tgz",
+		"oci://example.com",
+		"oci://example.com/nginx:",
+		"https://example.com/charts/nginx",
+	} {
+		if ref, err := Parse(in); err == nil {
+			t.Errorf("expect error for %q, got %+v", in, *ref)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		ref    Reference
+		expect string
+	}{
+		{ref: Reference{Kind: Repo, Chart: "nginx", Version: "1.0.0"}, expect: "nginx@1.0.0"},
+		{ref: Reference{Kind: OCI, URL: "oci://example.com/nginx", Chart: "nginx", Version: "1.0.0"}, expect: "oci://example.com/nginx@1.0.0"},
+		{ref: Reference{Kind: OCI, URL: "oci://example.com/nginx", Chart: "nginx"}, expect: "oci://example.com/nginx"},
+	}
+	for _, tt := range tests {
+		if got := tt.ref.String(); got != tt.expect {
+			t.Errorf("expect %s, got %s", tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package v1alpha1
+
+import (
+	"github.com/alauda/helm-crds/pkg/apis/app/internal/deepcopy"
+	"helm.sh/helm/pkg/chartutil"
+)
+
+// DeepCopyInto copies the values recursively
+func (in *HelmValues) DeepCopyInto(out *HelmValues) {
+	if in == nil {
+		return
+	}
+	if in.Values == nil {
+		out.Values = nil
+		return
+	}
+	out.Values = chartutil.Values(deepcopy.Map(in.Values))
+}
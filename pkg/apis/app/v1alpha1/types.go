@@ -7,16 +7,15 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 
-	"github.com/ghodss/yaml"
 	"helm.sh/helm/pkg/chartutil"
 
 	"github.com/thoas/go-funk"
 
 	"github.com/alauda/component-base/regex"
+	"github.com/alauda/helm-crds/pkg/apis/app/internal/reference"
 	"github.com/fatih/structs"
 	"helm.sh/helm/pkg/release"
 
-	"helm.sh/helm/pkg/repo"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog"
@@ -189,51 +188,45 @@ type ChartSpec struct {
 	Versions []*ChartVersion `json:"versions,omitempty"`
 }
 
+// ChartVersion is a version of a chart in the chart repo index. It has the same json format as the
+// ChartVersion in helm's repo package, but does not depend on it.
 type ChartVersion struct {
-	repo.ChartVersion
-}
-
-func (in *ChartVersion) DeepCopyInto(out *ChartVersion) {
-	if in == nil {
-		return
-	}
-
-	b, err := yaml.Marshal(in.ChartVersion)
-	if err != nil {
-		return
-	}
-	var r repo.ChartVersion
-	err = yaml.Unmarshal(b, &r)
-	if err != nil {
-		return
-	}
-
-	out.ChartVersion = r
+	ChartMetadata `json:",inline"`
+	// URLs are the urls to download this chart version
+	URLs []string `json:"urls"`
+	// Created is when this chart version is packaged
+	Created metav1.Time `json:"created,omitempty"`
+	// Removed is true if this version has been removed from the repo
+	Removed bool `json:"removed,omitempty"`
+	// Digest is the sha256 digest of the chart archive
+	Digest string `json:"digest,omitempty"`
 }
 
+// ChartMetadata models the structure of a Chart.yaml file.
 // ref: https://github.com/helm/helm/blob/master/docs/charts.md
-/*type ChartVersion struct {
+type ChartMetadata struct {
+	// The name of the chart
+	Name string `json:"name,omitempty"`
 	// The URL to a relevant project page, git repo, or contact person
 	Home string `json:"home,omitempty"`
 	// Source is the URL to the source code of this chart
-	Sources string `json:"sources,omitempty"`
+	Sources []string `json:"sources,omitempty"`
 	// A SemVer 2 conformant version string of the chart
 	Version string `json:"version,omitempty"`
 	// A one-sentence description of the chart
 	Description string `json:"description,omitempty"`
 	// A list of string keywords
-	Keywords []string `json:"keywords, omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
 	// A list of name and URL/email address combinations for the maintainer(s)
-	Maintainers []*chart.Maintainer `json:"maintainers,omitempty"`
+	Maintainers []ChartMaintainer `json:"maintainers,omitempty"`
 	// The URL to an icon file.
 	Icon string `json:"icon,omitempty"`
-
 	// The API Version of this chart.
-	APIVersion string `json:"apiVersion, omitempty"`
-
+	APIVersion string `json:"apiVersion,omitempty"`
 	// The condition to check to enable chart
 	Condition string `json:"condition,omitempty"`
-
+	// The tags to check to enable chart
+	Tags string `json:"tags,omitempty"`
 	// The version of the application enclosed inside of this chart.
 	AppVersion string `json:"appVersion,omitempty"`
 	// Whether or not this chart is deprecated
@@ -243,11 +236,42 @@ func (in *ChartVersion) DeepCopyInto(out *ChartVersion) {
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// KubeVersion is a SemVer constraint specifying the version of Kubernetes required.
 	KubeVersion string `json:"kubeVersion,omitempty"`
+	// Dependencies are a list of dependencies for a chart.
+	Dependencies []ChartDependency `json:"dependencies,omitempty"`
+	// Specifies the chart type: application or library
+	Type string `json:"type,omitempty"`
+}
 
-	Created metav1.Time `json:"created,omitempty"`
+// ChartMaintainer describes a Chart maintainer.
+type ChartMaintainer struct {
+	// Name is a user name or organization name
+	Name string `json:"name,omitempty"`
+	// Email is an optional email address to contact the named maintainer
+	Email string `json:"email,omitempty"`
+	// URL is an optional URL to an address for the named maintainer
+	URL string `json:"url,omitempty"`
+}
 
-	Digest string `json:"digest,omitempty"`
-}*/
+// ChartDependency describes a chart upon which another chart depends.
+type ChartDependency struct {
+	// Name is the name of the dependency.
+	Name string `json:"name"`
+	// Version is the version (range) of this chart.
+	Version string `json:"version,omitempty"`
+	// The URL to the repository.
+	Repository string `json:"repository"`
+	// A yaml path that resolves to a boolean, used for enabling/disabling charts
+	Condition string `json:"condition,omitempty"`
+	// Tags can be used to group charts for enabling/disabling together
+	Tags []string `json:"tags,omitempty"`
+	// Enabled bool determines if chart should be loaded
+	Enabled bool `json:"enabled,omitempty"`
+	// ImportValues holds the mapping of source values to parent key to be imported. Each item can be a
+	// string or a map
+	ImportValues []runtime.RawExtension `json:"import-values,omitempty"`
+	// Alias usable alias to be used for the chart
+	Alias string `json:"alias,omitempty"`
+}
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -312,23 +336,6 @@ type HelmValues struct {
 	chartutil.Values `json:"values,omitempty"`
 }
 
-func (in *HelmValues) DeepCopyInto(out *HelmValues) {
-	if in == nil {
-		return
-	}
-
-	b, err := yaml.Marshal(in.Values)
-	if err != nil {
-		return
-	}
-	var values chartutil.Values
-	err = yaml.Unmarshal(b, &values)
-	if err != nil {
-		return
-	}
-	out.Values = values
-}
-
 // HelmRequestPhase is a label for the condition of a HelmRequest at the current time.
 type HelmRequestPhase string
 
@@ -440,14 +447,56 @@ func (in *HelmRequest) Default() {
 		klog.Info("use helmrequest namespace as release namespace: ", in.GetNamespace())
 	}
 
-	in.Finalizers = []string{FinalizerName}
-	klog.V(4).Info("append finalizers to helmrequest: ", in.GetName())
+	// keep the finalizers added by others
+	if in.AddFinalizer(FinalizerName) {
+		klog.V(4).Info("append finalizers to helmrequest: ", in.GetName())
+	}
+}
+
+// HasFinalizer returns true if the HelmRequest has the finalizer
+func (in *HelmRequest) HasFinalizer(name string) bool {
+	for _, f := range in.Finalizers {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer appends the finalizer if it's not present, the existing ones are kept.
+// Returns true if the finalizers are changed
+func (in *HelmRequest) AddFinalizer(name string) bool {
+	if in.HasFinalizer(name) {
+		return false
+	}
+	in.Finalizers = append(in.Finalizers, name)
+	return true
+}
+
+// RemoveFinalizer removes the finalizer, the others are kept in order.
+// Returns true if the finalizers are changed
+func (in *HelmRequest) RemoveFinalizer(name string) bool {
+	if !in.HasFinalizer(name) {
+		return false
+	}
+	var finalizers []string
+	for _, f := range in.Finalizers {
+		if f != name {
+			finalizers = append(finalizers, f)
+		}
+	}
+	in.Finalizers = finalizers
+	return true
 }
 
 //ValidateCreate implements webhook.Validator
 // 1. check filed regex
 func (in *HelmRequest) ValidateCreate() error {
 	klog.V(4).Info("validate HelmRequest create: ", in.GetName())
+	if _, err := in.validateChart(); err != nil {
+		return err
+	}
+
 	if in.Spec.ClusterName != "" && !regex.IsValidResourceName(in.Spec.ClusterName) {
 		return in.nameRegexError(".spec.clusterName", in.Spec.ClusterName)
 	}
@@ -490,11 +539,13 @@ func (in *HelmRequest) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("expect old object to be a %T instead of %T", oldHR, old)
 	}
 
-	// check chart name
+	// check chart name, the repo and version can be changed
+	ref, err := in.validateChart()
+	if err != nil {
+		return err
+	}
 	_, oldChart := ParseChartName(oldHR.Spec.Chart)
-	_, newChart := ParseChartName(in.Spec.Chart)
-
-	if oldChart != newChart {
+	if oldChart != ref.Chart {
 		return fmt.Errorf("chart name cannot be updated after create")
 	}
 
@@ -568,8 +619,25 @@ func (in *HelmRequest) GetReleaseNamespace() string {
 	return ns
 }
 
-// ParseChartName is a simple function that parse chart name
+// validateChart checks .spec.chart is valid and does not conflict with .spec.version
+func (in *HelmRequest) validateChart() (*reference.Reference, error) {
+	ref, err := reference.Parse(in.Spec.Chart)
+	if err != nil {
+		return nil, fmt.Errorf(".spec.chart is invalid: %s", err.Error())
+	}
+	if ref.Version != "" && in.Spec.Version != "" && ref.Version != in.Spec.Version {
+		return nil, fmt.Errorf(".spec.chart has version %s, but .spec.version is %s", ref.Version, in.Spec.Version)
+	}
+	return ref, nil
+}
+
+// ParseChartName is a simple function that parse chart name, it also handles namespaced repos, OCI
+// references, urls and versions. If name is not a valid reference, it's split by "/" as before.
 func ParseChartName(name string) (repo, chart string) {
+	if ref, err := reference.Parse(name); err == nil {
+		return ref.Repo, ref.Chart
+	}
+
 	data := strings.Split(name, "/")
 	if len(data) == 1 {
 		return "", name
@@ -0,0 +1,162 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseChartName(t *testing.T) {
+	tests := []struct {
+		name  string
+		repo  string
+		chart string
+	}{
+		{name: "nginx", repo: "", chart: "nginx"},
+		{name: "stable/nginx", repo: "stable", chart: "nginx"},
+		{name: "stable/nginx@1.0.0", repo: "stable", chart: "nginx"},
+		{name: "default/stable/nginx", repo: "stable", chart: "nginx"},
+		{name: "oci://example.com:5000/charts/nginx:1.0.0", repo: "", chart: "nginx"},
+		{name: "https://example.com/charts/nginx-1.0.0.tgz", repo: "", chart: "nginx"},
+		// not a valid reference, split as before
+		{name: "stable/-nginx", repo: "stable", chart: "-nginx"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, chart := ParseChartName(tt.name)
+			if repo != tt.repo || chart != tt.chart {
+				t.Errorf("expect %s/%s, got %s/%s", tt.repo, tt.chart, repo, chart)
+			}
+		})
+	}
+}
+
+func newHelmRequest(chart, version string) *HelmRequest {
+	return &HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec:       HelmRequestSpec{Chart: chart, Version: version},
+	}
+}
+
+func TestHelmRequestValidateChart(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		chart   string
+		version string
+		valid   bool
+	}{
+		{name: "repo", chart: "stable/nginx", valid: true},
+		{name: "version in chart", chart: "stable/nginx@1.0.0", version: "1.0.0", valid: true},
+		{name: "conflict version", chart: "stable/nginx@1.0.0", version: "1.1.0", valid: false},
+		{name: "oci", chart: "oci://example.com/charts/nginx:1.0.0", valid: true},
+		{name: "invalid", chart: "a/b/c/nginx", valid: false},
+		{name: "change repo", old: "stable/nginx", chart: "default/incubator/nginx@1.0.0", valid: true},
+		{name: "change chart", old: "stable/nginx", chart: "stable/nginx-ingress", valid: false},
+		{name: "change to invalid", old: "stable/nginx", chart: "stable/nginx@", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := newHelmRequest(tt.chart, tt.version)
+			var err error
+			if tt.old == "" {
+				err = hr.ValidateCreate()
+			} else {
+				err = hr.ValidateUpdate(newHelmRequest(tt.old, ""))
+			}
+			if tt.valid && err != nil {
+				t.Errorf("expect valid, got %s", err.Error())
+			}
+			if !tt.valid && err == nil {
+				t.Error("expect error")
+			}
+		})
+	}
+}
+
+func TestHelmRequestDefault(t *testing.T) {
+	deleting := metav1.Now()
+	tests := []struct {
+		name       string
+		finalizers []string
+		deletion   *metav1.Time
+		expect     []string
+	}{
+		{name: "nil finalizers", finalizers: nil, expect: []string{FinalizerName}},
+		{name: "third-party only", finalizers: []string{"foregroundDeletion", "example.com/cleanup"}, expect: []string{"foregroundDeletion", "example.com/cleanup", FinalizerName}},
+		{name: "already added", finalizers: []string{FinalizerName, "example.com/cleanup"}, expect: []string{FinalizerName, "example.com/cleanup"}},
+		{name: "deleting", finalizers: []string{"example.com/cleanup"}, deletion: &deleting, expect: []string{"example.com/cleanup"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := newHelmRequest("stable/nginx", "")
+			hr.Finalizers = tt.finalizers
+			hr.DeletionTimestamp = tt.deletion
+			hr.Default()
+			if !reflect.DeepEqual(hr.Finalizers, tt.expect) {
+				t.Errorf("expect finalizers %v, got %v", tt.expect, hr.Finalizers)
+			}
+			// Default is called on every update, it should be idempotent
+			hr.Default()
+			if !reflect.DeepEqual(hr.Finalizers, tt.expect) {
+				t.Errorf("expect finalizers %v after defaulted twice, got %v", tt.expect, hr.Finalizers)
+			}
+
+			if tt.deletion != nil {
+				if hr.Spec.ReleaseName != "" || hr.Spec.Namespace != "" {
+					t.Errorf("expect spec untouched, got %+v", hr.Spec)
+				}
+			} else if hr.Spec.ReleaseName != "nginx" || hr.Spec.Namespace != "default" {
+				t.Errorf("expect release nginx in default, got %s in %s", hr.Spec.ReleaseName, hr.Spec.Namespace)
+			}
+		})
+	}
+}
+
+func TestHelmRequestFinalizers(t *testing.T) {
+	tests := []struct {
+		name       string
+		finalizers []string
+		add        string
+		remove     string
+		has        bool
+		changed    bool
+		expect     []string
+	}{
+		{name: "add to nil", add: "b", changed: true, expect: []string{"b"}},
+		{name: "add to the end", finalizers: []string{"c", "a"}, add: "b", changed: true, expect: []string{"c", "a", "b"}},
+		{name: "add existing", finalizers: []string{"c", "b", "a"}, add: "b", has: true, expect: []string{"c", "b", "a"}},
+		{name: "remove from nil", remove: "b"},
+		{name: "remove missing", finalizers: []string{"c", "a"}, remove: "b", expect: []string{"c", "a"}},
+		{name: "remove in the middle", finalizers: []string{"c", "b", "a"}, remove: "b", has: true, changed: true, expect: []string{"c", "a"}},
+		{name: "remove duplicated", finalizers: []string{"b", "c", "b", "a"}, remove: "b", has: true, changed: true, expect: []string{"c", "a"}},
+		{name: "remove the last one", finalizers: []string{"b"}, remove: "b", has: true, changed: true, expect: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := newHelmRequest("stable/nginx", "")
+			hr.Finalizers = tt.finalizers
+			name := tt.add + tt.remove
+			if has := hr.HasFinalizer(name); has != tt.has {
+				t.Errorf("expect HasFinalizer(%s) %v, got %v", name, tt.has, has)
+			}
+
+			var changed bool
+			if tt.add != "" {
+				changed = hr.AddFinalizer(tt.add)
+			} else {
+				changed = hr.RemoveFinalizer(tt.remove)
+			}
+			if changed != tt.changed {
+				t.Errorf("expect changed %v, got %v", tt.changed, changed)
+			}
+			if !reflect.DeepEqual(hr.Finalizers, tt.expect) {
+				t.Errorf("expect finalizers %v, got %v", tt.expect, hr.Finalizers)
+			}
+			if has := hr.HasFinalizer(name); has != (tt.add != "") {
+				t.Errorf("expect HasFinalizer(%s) %v after changed, got %v", name, tt.add != "", has)
+			}
+		})
+	}
+}
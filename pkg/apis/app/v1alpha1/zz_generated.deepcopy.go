@@ -52,6 +52,34 @@ func (in *Chart) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChartDependency) DeepCopyInto(out *ChartDependency) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImportValues != nil {
+		in, out := &in.ImportValues, &out.ImportValues
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartDependency.
+func (in *ChartDependency) DeepCopy() *ChartDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChartList) DeepCopyInto(out *ChartList) {
 	*out = *in
@@ -85,6 +113,67 @@ func (in *ChartList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChartMaintainer) DeepCopyInto(out *ChartMaintainer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartMaintainer.
+func (in *ChartMaintainer) DeepCopy() *ChartMaintainer {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartMaintainer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChartMetadata) DeepCopyInto(out *ChartMetadata) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keywords != nil {
+		in, out := &in.Keywords, &out.Keywords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintainers != nil {
+		in, out := &in.Maintainers, &out.Maintainers
+		*out = make([]ChartMaintainer, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]ChartDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartMetadata.
+func (in *ChartMetadata) DeepCopy() *ChartMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChartRepo) DeepCopyInto(out *ChartRepo) {
 	*out = *in
@@ -209,6 +298,19 @@ func (in *ChartSpec) DeepCopy() *ChartSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChartVersion) DeepCopyInto(out *ChartVersion) {
+	*out = *in
+	in.ChartMetadata.DeepCopyInto(&out.ChartMetadata)
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Created.DeepCopyInto(&out.Created)
+	return
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartVersion.
 func (in *ChartVersion) DeepCopy() *ChartVersion {
 	if in == nil {
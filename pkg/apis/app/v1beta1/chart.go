@@ -0,0 +1,127 @@
+package v1beta1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// ChartObjectName returns the name of the Chart resource generated for a chart in a ChartRepo.
+// eg: chart nginx in repo stable is stored as nginx.stable
+func ChartObjectName(repo, chart string) string {
+	return fmt.Sprintf("%s.%s", strings.ToLower(chart), repo)
+}
+
+// SortedVersions returns the versions from newest to oldest by semver. Versions that are not valid
+// semver are put at the end, in the reverse order of their string value.
+func (in *Chart) SortedVersions() []*ChartVersion {
+	type parsed struct {
+		cv  *ChartVersion
+		ver *semver.Version
+	}
+
+	var valid, invalid []parsed
+	for _, cv := range in.Spec.Versions {
+		if cv == nil {
+			continue
+		}
+		ver, err := semver.NewVersion(cv.Version)
+		if err != nil {
+			invalid = append(invalid, parsed{cv: cv})
+			continue
+		}
+		valid = append(valid, parsed{cv: cv, ver: ver})
+	}
+
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].ver.GreaterThan(valid[j].ver)
+	})
+	sort.SliceStable(invalid, func(i, j int) bool {
+		return invalid[i].cv.Version > invalid[j].cv.Version
+	})
+
+	result := make([]*ChartVersion, 0, len(valid)+len(invalid))
+	for _, item := range append(valid, invalid...) {
+		result = append(result, item.cv)
+	}
+	return result
+}
+
+// Latest returns the newest version that has not been removed from the repo. Prerelease versions are
+// skipped unless includePrerelease is true. Versions that are not valid semver are never returned.
+func (in *Chart) Latest(includePrerelease bool) *ChartVersion {
+	for _, cv := range in.SortedVersions() {
+		if cv.Removed {
+			continue
+		}
+		ver, err := semver.NewVersion(cv.Version)
+		if err != nil {
+			return nil
+		}
+		if ver.Prerelease() != "" && !includePrerelease {
+			continue
+		}
+		return cv
+	}
+	return nil
+}
+
+// Get returns the version exactly matches version. If not found, a semver equal one is returned,
+// eg: v1.0.0 matches 1.0.0. Returns nil if not found.
+func (in *Chart) Get(version string) *ChartVersion {
+	for _, cv := range in.Spec.Versions {
+		if cv != nil && cv.Version == version {
+			return cv
+		}
+	}
+
+	ver, err := semver.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+	for _, cv := range in.SortedVersions() {
+		if v, err := semver.NewVersion(cv.Version); err == nil && v.Equal(ver) {
+			return cv
+		}
+	}
+	return nil
+}
+
+// Match returns the newest version that satisfies the semver constraint, eg: ~1.2 or ">=1.0.0, <2.0.0".
+// An empty constraint means the latest stable version. Returns an error if the constraint is invalid
+// and nil if no version matches.
+func (in *Chart) Match(constraint string) (*ChartVersion, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return in.Latest(false), nil
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint %s: %s", constraint, err.Error())
+	}
+	for _, cv := range in.SortedVersions() {
+		if cv.Removed {
+			continue
+		}
+		ver, err := semver.NewVersion(cv.Version)
+		if err != nil {
+			break
+		}
+		if c.Check(ver) {
+			return cv, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByAppVersion returns the newest chart version packages the app version, nil if not found
+func (in *Chart) FindByAppVersion(appVersion string) *ChartVersion {
+	for _, cv := range in.SortedVersions() {
+		if !cv.Removed && cv.AppVersion == appVersion {
+			return cv
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newTestChart creates a chart with versions, versions prefixed with "-" are removed
+func newTestChart(versions ...string) *Chart {
+	chart := newChart()
+	for _, v := range versions {
+		cv := newChartVersion(v)
+		if v[0] == '-' {
+			cv.Version = v[1:]
+			cv.Removed = true
+		}
+		cv.AppVersion = "app-" + cv.Version
+		chart.Spec.Versions = append(chart.Spec.Versions, cv)
+	}
+	return chart
+}
+
+func version(cv *ChartVersion) string {
+	if cv == nil {
+		return ""
+	}
+	return cv.Version
+}
+
+func TestChartObjectName(t *testing.T) {
+	if name := ChartObjectName("stable", "Nginx"); name != "nginx.stable" {
+		t.Errorf("expect nginx.stable, got %s", name)
+	}
+}
+
+func TestChartSortedVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		expect   []string
+	}{
+		{name: "empty", expect: []string{}},
+		{name: "semver", versions: []string{"1.0.0", "1.10.0", "1.2.0", "0.9.0"}, expect: []string{"1.10.0", "1.2.0", "1.0.0", "0.9.0"}},
+		{name: "prerelease", versions: []string{"2.0.0-rc.1", "2.0.0", "2.0.0-beta.2", "1.9.0"}, expect: []string{"2.0.0", "2.0.0-rc.1", "2.0.0-beta.2", "1.9.0"}},
+		{name: "v prefix", versions: []string{"v1.0.0", "1.1.0"}, expect: []string{"1.1.0", "v1.0.0"}},
+		{name: "invalid at the end", versions: []string{"abc", "1.0.0", "latest", "2.0.0"}, expect: []string{"2.0.0", "1.0.0", "latest", "abc"}},
+		{name: "removed are kept", versions: []string{"1.0.0", "-1.1.0"}, expect: []string{"1.1.0", "1.0.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := newTestChart(tt.versions...)
+			chart.Spec.Versions = append(chart.Spec.Versions, nil)
+			got := []string{}
+			for _, cv := range chart.SortedVersions() {
+				got = append(got, cv.Version)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expect %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestChartLatest(t *testing.T) {
+	tests := []struct {
+		name       string
+		versions   []string
+		stable     string
+		prerelease string
+	}{
+		{name: "empty"},
+		{name: "stable", versions: []string{"1.0.0", "1.1.0"}, stable: "1.1.0", prerelease: "1.1.0"},
+		{name: "prerelease", versions: []string{"1.0.0", "2.0.0-rc.1"}, stable: "1.0.0", prerelease: "2.0.0-rc.1"},
+		{name: "removed", versions: []string{"1.0.0", "-1.1.0", "-2.0.0-rc.1"}, stable: "1.0.0", prerelease: "1.0.0"},
+		{name: "all removed", versions: []string{"-1.0.0"}},
+		{name: "only prerelease", versions: []string{"1.0.0-alpha"}, prerelease: "1.0.0-alpha"},
+		{name: "invalid are never latest", versions: []string{"latest", "abc"}},
+		{name: "invalid after removed", versions: []string{"-1.0.0", "latest"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := newTestChart(tt.versions...)
+			if got := version(chart.Latest(false)); got != tt.stable {
+				t.Errorf("expect latest stable %q, got %q", tt.stable, got)
+			}
+			if got := version(chart.Latest(true)); got != tt.prerelease {
+				t.Errorf("expect latest %q, got %q", tt.prerelease, got)
+			}
+		})
+	}
+}
+
+func TestChartGet(t *testing.T) {
+	chart := newTestChart("1.0.0", "v2.0.0", "-3.0.0", "latest", "1.0")
+	tests := []struct {
+		version string
+		expect  string
+	}{
+		{version: "1.0.0", expect: "1.0.0"},
+		{version: "v1.0.0", expect: "1.0.0"},
+		{version: "v2.0.0", expect: "v2.0.0"},
+		{version: "2.0.0", expect: "v2.0.0"},
+		{version: "1.0", expect: "1.0"},
+		{version: "latest", expect: "latest"},
+		// removed versions can still be got, so installed releases can be resolved
+		{version: "3.0.0", expect: "3.0.0"},
+		{version: "4.0.0", expect: ""},
+		{version: "abc", expect: ""},
+		{version: "", expect: ""},
+	}
+	for _, tt := range tests {
+		if got := version(chart.Get(tt.version)); got != tt.expect {
+			t.Errorf("Get(%q): expect %q, got %q", tt.version, tt.expect, got)
+		}
+	}
+}
+
+func TestChartMatch(t *testing.T) {
+	chart := newTestChart("1.0.0", "1.2.0", "1.2.3", "-1.2.4", "1.10.0", "2.0.0-rc.1", "latest")
+	tests := []struct {
+		constraint string
+		expect     string
+		err        bool
+	}{
+		{constraint: "", expect: "1.10.0"},
+		{constraint: " ", expect: "1.10.0"},
+		{constraint: "1.2.0", expect: "1.2.0"},
+		{constraint: "~1.2", expect: "1.2.3"},
+		{constraint: "^1.0.0", expect: "1.10.0"},
+		{constraint: ">=1.0.0, <1.10.0", expect: "1.2.3"},
+		{constraint: "~1.0 || ~1.2", expect: "1.2.3"},
+		{constraint: "1.2.4", expect: ""},
+		{constraint: ">=2.0.0-0", expect: "2.0.0-rc.1"},
+		{constraint: ">2.0.0", expect: ""},
+		{constraint: "not a constraint", err: true},
+	}
+	for _, tt := range tests {
+		cv, err := chart.Match(tt.constraint)
+		if (err != nil) != tt.err {
+			t.Errorf("Match(%q): unexpected error %v", tt.constraint, err)
+			continue
+		}
+		if got := version(cv); got != tt.expect {
+			t.Errorf("Match(%q): expect %q, got %q", tt.constraint, tt.expect, got)
+		}
+	}
+}
+
+func TestChartFindByAppVersion(t *testing.T) {
+	chart := newTestChart("1.0.0", "1.1.0", "-1.2.0")
+	// two chart versions package the same app version
+	chart.Spec.Versions[0].AppVersion = "app-1.1.0"
+	tests := []struct {
+		appVersion string
+		expect     string
+	}{
+		{appVersion: "app-1.1.0", expect: "1.1.0"},
+		{appVersion: "app-1.2.0", expect: ""},
+		{appVersion: "app-1.0.0", expect: ""},
+	}
+	for _, tt := range tests {
+		if got := version(chart.FindByAppVersion(tt.appVersion)); got != tt.expect {
+			t.Errorf("FindByAppVersion(%q): expect %q, got %q", tt.appVersion, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package v1beta1
+
+import (
+	"unsafe"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/internal/chartversion"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/repo"
+)
+
+// ChartVersion and ChartMetadata have the same fields as Version and Metadata in internal/chartversion,
+// so the conversions there are shared by pointers.
+
+// CopyFromRepoChartVersion copies a ChartVersion from helm's repo index
+func (in *ChartVersion) CopyFromRepoChartVersion(cv *repo.ChartVersion) {
+	(*chartversion.Version)(unsafe.Pointer(in)).CopyFromRepo(cv)
+}
+
+// ToRepoChartVersion converts to helm's repo ChartVersion
+func (in *ChartVersion) ToRepoChartVersion() *repo.ChartVersion {
+	return (*chartversion.Version)(unsafe.Pointer(in)).ToRepo()
+}
+
+// NewChartVersions converts the versions of a chart in helm's repo index
+func NewChartVersions(versions repo.ChartVersions) []*ChartVersion {
+	result := chartversion.NewVersions(versions)
+	return *(*[]*ChartVersion)(unsafe.Pointer(&result))
+}
+
+// CopyFromChartMetadata copies from the metadata of a helm chart
+func (in *ChartMetadata) CopyFromChartMetadata(md *chart.Metadata) {
+	(*chartversion.Metadata)(unsafe.Pointer(in)).CopyFromChart(md)
+}
+
+// ToChartMetadata converts to the metadata of a helm chart
+func (in *ChartMetadata) ToChartMetadata() *chart.Metadata {
+	return (*chartversion.Metadata)(unsafe.Pointer(in)).ToChart()
+}
@@ -0,0 +1,75 @@
+package v1beta1
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelmRequestConditionType is the type of a HelmRequest condition
+type HelmRequestConditionType string
+
+const (
+	// HelmRequestValuesValid means the values of the HelmRequest, including ValuesFrom, are valid
+	// against the values.schema.json of the chart
+	HelmRequestValuesValid HelmRequestConditionType = "ValuesValid"
+	// HelmRequestDrifted means the live objects in the cluster differ from the deployed Release
+	HelmRequestDrifted HelmRequestConditionType = "Drifted"
+)
+
+// HelmRequestCondition is an observation of a HelmRequest
+type HelmRequestCondition struct {
+	Type   HelmRequestConditionType `json:"type"`
+	Status v1.ConditionStatus       `json:"status"`
+	// LastTransitionTime is the last time the status changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a brief CamelCase reason of the last transition
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message about the last transition
+	Message string `json:"message,omitempty"`
+}
+
+// GetCondition returns the condition of the type, nil if not found
+func (in *HelmRequestStatus) GetCondition(t HelmRequestConditionType) *HelmRequestCondition {
+	for i := range in.Conditions {
+		if in.Conditions[i].Type == t {
+			return &in.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue checks whether the condition of the type exists and is true
+func (in *HelmRequestStatus) IsConditionTrue(t HelmRequestConditionType) bool {
+	c := in.GetCondition(t)
+	return c != nil && c.Status == v1.ConditionTrue
+}
+
+// SetCondition adds or updates the condition by it's type. The LastTransitionTime is only changed to now
+// when the status changes.
+func (in *HelmRequestStatus) SetCondition(c HelmRequestCondition, now time.Time) {
+	existing := in.GetCondition(c.Type)
+	if existing == nil {
+		c.LastTransitionTime = metav1.NewTime(now)
+		in.Conditions = append(in.Conditions, c)
+		return
+	}
+	if existing.Status != c.Status {
+		existing.LastTransitionTime = metav1.NewTime(now)
+	}
+	existing.Status = c.Status
+	existing.Reason = c.Reason
+	existing.Message = c.Message
+}
+
+// RemoveCondition removes the condition of the type
+func (in *HelmRequestStatus) RemoveCondition(t HelmRequestConditionType) {
+	var conditions []HelmRequestCondition
+	for _, c := range in.Conditions {
+		if c.Type != t {
+			conditions = append(conditions, c)
+		}
+	}
+	in.Conditions = conditions
+}
@@ -0,0 +1,193 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	fuzz "github.com/google/gofuzz"
+	"helm.sh/helm/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// yamlCopy is how the fields were copied before, by a yaml round trip
+func yamlCopy(t *testing.T, in, out interface{}) {
+	b, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(b, out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// fuzzValue fills a value like the ones decoded from yaml, the depth is limited by fuzz.MaxDepth
+func fuzzValue(c fuzz.Continue) interface{} {
+	switch c.Intn(7) {
+	case 0:
+		return nil
+	case 1:
+		return c.RandString()
+	case 2:
+		return c.RandBool()
+	case 3:
+		// the yaml round trip decodes numbers to float64, keep them exact
+		return int64(c.Int31())
+	case 4:
+		return c.Float64()
+	case 5:
+		m := map[string]interface{}{}
+		c.Fuzz(&m)
+		return m
+	default:
+		var s []interface{}
+		c.Fuzz(&s)
+		return s
+	}
+}
+
+func newFuzzer(seed int64) *fuzz.Fuzzer {
+	return fuzz.New().RandSource(rand.NewSource(seed)).NilChance(.2).NumElements(0, 4).MaxDepth(8).Funcs(
+		func(v *interface{}, c fuzz.Continue) {
+			*v = fuzzValue(c)
+		},
+		func(v *chartutil.Values, c fuzz.Continue) {
+			m := map[string]interface{}{}
+			c.Fuzz(&m)
+			*v = m
+		},
+		func(v *runtime.RawExtension, c fuzz.Continue) {
+			// import-values are strings or maps
+			if c.RandBool() {
+				v.Raw = []byte(mustMarshal(c.RandString()))
+				return
+			}
+			v.Raw = []byte(mustMarshal(map[string]string{"child": c.RandString(), "parent": c.RandString()}))
+		},
+		func(v *metav1.Time, c fuzz.Continue) {
+			// metav1.Time is encoded in seconds
+			*v = metav1.Unix(c.Int63n(1<<32), 0)
+		},
+	)
+}
+
+func mustMarshal(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func TestDeepCopyFuzz(t *testing.T) {
+	tests := []struct {
+		name string
+		new  func() interface{}
+		copy func(interface{}) interface{}
+	}{
+		{
+			name: "HelmValues",
+			new:  func() interface{} { return &HelmValues{} },
+			copy: func(in interface{}) interface{} { return in.(*HelmValues).DeepCopy() },
+		},
+		{
+			name: "ChartVersion",
+			new:  func() interface{} { return &ChartVersion{} },
+			copy: func(in interface{}) interface{} { return in.(*ChartVersion).DeepCopy() },
+		},
+		{
+			name: "ChartDependency",
+			new:  func() interface{} { return &ChartDependency{} },
+			copy: func(in interface{}) interface{} { return in.(*ChartDependency).DeepCopy() },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 500; i++ {
+				in := tt.new()
+				newFuzzer(int64(i + 1)).Fuzz(in)
+				before := mustJSON(t, in)
+
+				out := tt.copy(in)
+				if !reflect.DeepEqual(in, out) {
+					t.Fatalf("copy is not equal to the original: %s", before)
+				}
+				expect := tt.new()
+				yamlCopy(t, in, expect)
+				if got, expect := mustJSON(t, out), mustJSON(t, expect); got != expect {
+					t.Fatalf("expect the same as yaml copy %s, got %s", expect, got)
+				}
+
+				// fuzz the copy again, the original should not be affected
+				newFuzzer(int64(i+1) * 7919).Fuzz(out)
+				if after := mustJSON(t, in); after != before {
+					t.Fatalf("original is changed by the copy, expect %s, got %s", before, after)
+				}
+			}
+		})
+	}
+}
+
+func benchmarkChartVersion() *ChartVersion {
+	cv := &ChartVersion{
+		ChartMetadata: ChartMetadata{
+			Name:        "nginx",
+			Version:     "1.0.0",
+			Description: "nginx chart",
+			Keywords:    []string{"web", "proxy"},
+			Maintainers: []ChartMaintainer{{Name: "alauda", Email: "alauda@example.com"}},
+		},
+		URLs:    []string{"https://charts.example.com/nginx-1.0.0.tgz"},
+		Created: metav1.NewTime(time.Unix(1570000000, 0)),
+		Digest:  "sha256:0123456789abcdef",
+	}
+	for i := 0; i < 5; i++ {
+		cv.Dependencies = append(cv.Dependencies, ChartDependency{
+			Name:         fmt.Sprintf("dep%d", i),
+			Version:      "~1.0.0",
+			Repository:   "https://charts.example.com",
+			ImportValues: []runtime.RawExtension{{Raw: []byte(`{"child":"a","parent":"b"}`)}},
+		})
+	}
+	return cv
+}
+
+func BenchmarkDeepCopyChartVersion(b *testing.B) {
+	cv := benchmarkChartVersion()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cv.DeepCopy()
+	}
+}
+
+func BenchmarkDeepCopyChartVersionYAML(b *testing.B) {
+	cv := benchmarkChartVersion()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := yaml.Marshal(cv)
+		var out ChartVersion
+		yaml.Unmarshal(data, &out)
+	}
+}
+
+func BenchmarkDeepCopyHelmValues(b *testing.B) {
+	in := &HelmValues{}
+	newFuzzer(1).NilChance(0).NumElements(4, 8).MaxDepth(6).Fuzz(in)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		in.DeepCopy()
+	}
+}
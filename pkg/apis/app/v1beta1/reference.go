@@ -0,0 +1,78 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/internal/reference"
+)
+
+// ChartReferenceKind is the kind of a chart reference
+type ChartReferenceKind = reference.Kind
+
+const (
+	// ChartReferenceRepo refers to a chart in a ChartRepo, eg: stable/nginx, nginx, default/stable/nginx
+	ChartReferenceRepo = reference.Repo
+	// ChartReferenceOCI refers to a chart in an OCI registry, eg: oci://example.com/charts/nginx:1.0.0
+	ChartReferenceOCI = reference.OCI
+	// ChartReferenceURL refers to a chart archive by url, eg: https://example.com/charts/nginx-1.0.0.tgz
+	ChartReferenceURL = reference.URL
+)
+
+// ChartReference is the parsed form of HelmRequest.Spec.Chart. The formats are
+// [[<namespace>/]<repo>/]<chart>[@<version>] for charts in ChartRepos,
+// oci://<host>/<path>/<chart>[:<tag>|@<version>] for charts in OCI registries and
+// http(s)://<host>/<path>/<chart>-<version>.tgz for chart archives.
+type ChartReference = reference.Reference
+
+// ParseChartReference parses a chart reference, see ChartReference for the formats
+func ParseChartReference(s string) (*ChartReference, error) {
+	return reference.Parse(s)
+}
+
+// GetChartReference parses .spec.chart, the version in .spec.version is used if the reference
+// has no version
+func (in *HelmRequest) GetChartReference() (*ChartReference, error) {
+	ref, err := ParseChartReference(in.Spec.Chart)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Version == "" {
+		ref.Version = in.Spec.Version
+	}
+	return ref, nil
+}
+
+// validateChart checks .spec.chart is valid and does not conflict with .spec.version
+func (in *HelmRequest) validateChart() (*ChartReference, error) {
+	ref, err := ParseChartReference(in.Spec.Chart)
+	if err != nil {
+		return nil, fmt.Errorf(".spec.chart is invalid: %s", err.Error())
+	}
+	if ref.Version != "" && in.Spec.Version != "" && ref.Version != in.Spec.Version {
+		return nil, fmt.Errorf(".spec.chart has version %s, but .spec.version is %s", ref.Version, in.Spec.Version)
+	}
+	return ref, nil
+}
+
+// validateChartKind checks .spec.chartKind is known and only used for charts in repos
+func (in *HelmRequest) validateChartKind() error {
+	switch in.Spec.ChartKind {
+	case "":
+		return nil
+	case ChartKindChart, ChartKindClusterChart:
+	default:
+		return fmt.Errorf("unknown .spec.chartKind: %s", in.Spec.ChartKind)
+	}
+
+	ref, err := ParseChartReference(in.Spec.Chart)
+	if err != nil {
+		return err
+	}
+	if ref.Kind != ChartReferenceRepo {
+		return fmt.Errorf(".spec.chartKind is only allowed for charts in repos")
+	}
+	if in.Spec.ChartKind == ChartKindClusterChart && ref.Namespace != "" {
+		return fmt.Errorf(".spec.chart should not contain a namespace when .spec.chartKind is %s", ChartKindClusterChart)
+	}
+	return nil
+}
@@ -42,6 +42,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ChartRepoList{},
 		&Chart{},
 		&ChartList{},
+		&ClusterChartRepo{},
+		&ClusterChartRepoList{},
+		&ClusterChart{},
+		&ClusterChartList{},
 	)
 
 	scheme.AddKnownTypes(
@@ -0,0 +1,135 @@
+package v1beta1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReleaseNameLabel is the label of the release name on Release objects
+	ReleaseNameLabel = "name"
+	// ReleaseVersionLabel is the label of the release version on Release objects
+	ReleaseVersionLabel = "version"
+	// ReleaseStatusLabel is the label of the release status on Release objects
+	ReleaseStatusLabel = "status"
+)
+
+// ReleaseObjectName returns the name of the Release object for a revision of a release, eg: nginx.v1
+func ReleaseObjectName(name string, version int) string {
+	return fmt.Sprintf("%s.v%d", name, version)
+}
+
+// NewRelease converts a helm release to a Release object. The chart, config and hooks are stored as
+// base64 encoded gzipped json, the same way as helm's storage drivers.
+func NewRelease(rls *release.Release) (*Release, error) {
+	chartData, err := encodeReleaseData(rls.Chart)
+	if err != nil {
+		return nil, fmt.Errorf("encode chart of release %s error: %s", rls.Name, err.Error())
+	}
+	configData, err := encodeReleaseData(rls.Config)
+	if err != nil {
+		return nil, fmt.Errorf("encode config of release %s error: %s", rls.Name, err.Error())
+	}
+	hooksData, err := encodeReleaseData(rls.Hooks)
+	if err != nil {
+		return nil, fmt.Errorf("encode hooks of release %s error: %s", rls.Name, err.Error())
+	}
+
+	rel := &Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ReleaseObjectName(rls.Name, rls.Version),
+			Namespace: rls.Namespace,
+			Labels: map[string]string{
+				ReleaseNameLabel:    rls.Name,
+				ReleaseVersionLabel: strconv.Itoa(rls.Version),
+			},
+		},
+		Spec: ReleaseSpec{
+			ChartData:    chartData,
+			ConfigData:   configData,
+			ManifestData: rls.Manifest,
+			HooksData:    hooksData,
+			Version:      rls.Version,
+			Name:         rls.Name,
+		},
+	}
+	if rls.Info != nil {
+		rel.Status.CopyFromReleaseInfo(rls.Info)
+		rel.Labels[ReleaseStatusLabel] = string(rls.Info.Status)
+	}
+	return rel, nil
+}
+
+// ToHelmRelease converts the Release object back to a helm release
+func (in *Release) ToHelmRelease() (*release.Release, error) {
+	rls := &release.Release{
+		Name:      in.Spec.Name,
+		Info:      in.Status.ToReleaseInfo(),
+		Manifest:  in.Spec.ManifestData,
+		Version:   in.Spec.Version,
+		Namespace: in.Namespace,
+	}
+	if in.Spec.ChartData != "" {
+		rls.Chart = &chart.Chart{}
+		if err := decodeReleaseData(in.Spec.ChartData, rls.Chart); err != nil {
+			return nil, fmt.Errorf("decode chart of release %s error: %s", in.Name, err.Error())
+		}
+	}
+	if err := decodeReleaseData(in.Spec.ConfigData, &rls.Config); err != nil {
+		return nil, fmt.Errorf("decode config of release %s error: %s", in.Name, err.Error())
+	}
+	if err := decodeReleaseData(in.Spec.HooksData, &rls.Hooks); err != nil {
+		return nil, fmt.Errorf("decode hooks of release %s error: %s", in.Name, err.Error())
+	}
+	return rls, nil
+}
+
+func encodeReleaseData(obj interface{}) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeReleaseData decodes the data encoded by encodeReleaseData, empty data is ignored. Data not
+// gzipped is accepted too
+func decodeReleaseData(data string, obj interface{}) error {
+	if data == "" {
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return err
+	}
+	if len(b) >= 2 && b[0] == 0x1f && b[1] == 0x8b {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		if b, err = ioutil.ReadAll(r); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(b, obj)
+}
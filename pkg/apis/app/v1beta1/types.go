@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 
-	"github.com/ghodss/yaml"
 	"helm.sh/helm/pkg/chartutil"
 
 	"github.com/thoas/go-funk"
@@ -16,8 +16,8 @@ import (
 	"github.com/fatih/structs"
 	"helm.sh/helm/pkg/release"
 
-	"helm.sh/helm/pkg/repo"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog"
 )
@@ -25,6 +25,23 @@ import (
 const (
 	// FinalizerName is the finalizer name we append to each HelmRequest resource
 	FinalizerName = "captain.cpaas.io"
+
+	// ResyncAnnotation can be set on a ChartRepo to request a sync right now. The value is opaque(a timestamp
+	// is recommended), every new value will trigger a new sync.
+	ResyncAnnotation = "captain.cpaas.io/resync-requested-at"
+
+	// SyncAnnotation can be set on a HelmRequest to force a sync even if the spec is not changed. The value
+	// is opaque(a timestamp is recommended), every new value will trigger a new sync.
+	SyncAnnotation = "captain.cpaas.io/sync-requested-at"
+
+	// RollbackAnnotation can be set on a HelmRequest to rollback its release to the revision in the value
+	RollbackAnnotation = "captain.cpaas.io/rollback-to"
+
+	// DefaultChartRepoSyncInterval is the sync interval used when ChartRepo.Spec.SyncInterval is not set
+	DefaultChartRepoSyncInterval = 10 * time.Minute
+
+	// MinChartRepoSyncInterval is the minimal sync interval allowed, to protect the repo server
+	MinChartRepoSyncInterval = 1 * time.Minute
 )
 
 // +genclient
@@ -133,6 +150,65 @@ type ChartRepoSpec struct {
 	Type string `json:"type"`
 	// new in v1beta1.if type is Chart, this is optional and it will provide some compatible with v1alpha1
 	Source *ChartRepoSource `json:"source"`
+	// SyncInterval is how often this repo will be re-synced, default to DefaultChartRepoSyncInterval
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
+	// Verification defines how to verify the charts downloaded from this repo. Default to no verification
+	Verification *ChartVerification `json:"verification,omitempty"`
+	// NamespaceSelector selects the namespaces whose HelmRequests may install charts from this repo. The
+	// namespace of the repo itself is always allowed. Default to all namespaces
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// AllowsNamespace checks whether HelmRequests in the namespace may use charts from this repo
+func (in *ChartRepo) AllowsNamespace(ns *v1.Namespace) (bool, error) {
+	if ns.Name == in.Namespace {
+		return true, nil
+	}
+	return namespaceSelected(in.Spec.NamespaceSelector, ns)
+}
+
+// AllowsNamespace checks whether HelmRequests in the namespace may use charts from this repo
+func (in *ClusterChartRepo) AllowsNamespace(ns *v1.Namespace) (bool, error) {
+	return namespaceSelected(in.Spec.NamespaceSelector, ns)
+}
+
+func namespaceSelected(selector *metav1.LabelSelector, ns *v1.Namespace) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid namespace selector: %s", err.Error())
+	}
+	return s.Matches(labels.Set(ns.Labels)), nil
+}
+
+// ChartVerificationMode is how the charts are verified
+type ChartVerificationMode string
+
+const (
+	// ChartVerificationNone means charts are not verified
+	ChartVerificationNone ChartVerificationMode = "None"
+	// ChartVerificationDigest means chart archives are checked against the digest in the repo index
+	ChartVerificationDigest ChartVerificationMode = "Digest"
+	// ChartVerificationProvenance means chart archives are checked against their .prov files, which
+	// must be signed by a key in the keyring
+	ChartVerificationProvenance ChartVerificationMode = "Provenance"
+)
+
+// ChartVerification defines how to verify the charts of a ChartRepo
+type ChartVerification struct {
+	Mode ChartVerificationMode `json:"mode,omitempty"`
+	// Keyring is the Secret contains the public keyring(in the `keyring` key), required in Provenance mode
+	Keyring *v1.SecretReference `json:"keyring,omitempty"`
+}
+
+// GetVerificationMode returns the verification mode of this repo, default to ChartVerificationNone
+func (in *ChartRepo) GetVerificationMode() ChartVerificationMode {
+	if in.Spec.Verification == nil || in.Spec.Verification.Mode == "" {
+		return ChartVerificationNone
+	}
+	return in.Spec.Verification.Mode
 }
 
 type ChartRepoStatus struct {
@@ -142,6 +218,19 @@ type ChartRepoStatus struct {
 	Phase ChartRepoPhase `json:"phase,omitempty"`
 	// Reason is the failed reason
 	Reason string `json:"reason,omitempty"`
+	// LastSyncTime is when the last sync finished, no matter it's succeed or not
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// LastSyncDuration is how long the last sync took
+	LastSyncDuration *metav1.Duration `json:"lastSyncDuration,omitempty"`
+	// ObservedGeneration is the generation of the spec that last synced
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ObservedResyncRequest is the value of the ResyncAnnotation that last handled
+	ObservedResyncRequest string `json:"observedResyncRequest,omitempty"`
+	// ChartCount is the number of charts found in the last sync
+	ChartCount int `json:"chartCount,omitempty"`
+	// Revision is the source revision of the last sync, eg: commit id for Git and revision number for SVN.
+	// Empty for Chart type
+	Revision string `json:"revision,omitempty"`
 }
 
 // ChartRepoSource defines how this ChartRepo is generated  from when it's not a normal chart repo.
@@ -151,6 +240,9 @@ type ChartRepoSource struct {
 	URL string `json:"url"`
 	// may be root, may be a subdir
 	Path string `json:"path"`
+	// Ref is the branch, tag or commit to use for git, or the revision to use for svn.
+	// Default to the remote HEAD
+	Ref string `json:"ref,omitempty"`
 }
 
 // ChartRepoType ...
@@ -178,9 +270,52 @@ const (
 	ChartRepoPending ChartRepoPhase = "Pending"
 )
 
+// Default set the default sync interval for ChartRepo
+func (in *ChartRepo) Default() {
+	if in.Spec.SyncInterval == nil {
+		in.Spec.SyncInterval = &metav1.Duration{Duration: DefaultChartRepoSyncInterval}
+	}
+}
+
 func (in *ChartRepo) ValidateCreate() error {
+	if err := in.validateVerification(); err != nil {
+		return err
+	}
+	if err := in.validateNamespaceSelector(); err != nil {
+		return err
+	}
+	return in.validateSyncInterval()
+}
+
+func (in *ChartRepo) validateNamespaceSelector() error {
+	if in.Spec.NamespaceSelector == nil {
+		return nil
+	}
+	if _, err := metav1.LabelSelectorAsSelector(in.Spec.NamespaceSelector); err != nil {
+		return fmt.Errorf(".spec.namespaceSelector is invalid: %s", err.Error())
+	}
 	return nil
+}
+
+func (in *ChartRepo) validateVerification() error {
+	switch in.GetVerificationMode() {
+	case ChartVerificationNone, ChartVerificationDigest:
+		return nil
+	case ChartVerificationProvenance:
+		if in.Spec.Verification.Keyring == nil || in.Spec.Verification.Keyring.Name == "" {
+			return fmt.Errorf(".spec.verification.keyring is required in %s mode", ChartVerificationProvenance)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown .spec.verification.mode: %s", in.Spec.Verification.Mode)
+	}
+}
 
+func (in *ChartRepo) validateSyncInterval() error {
+	if in.Spec.SyncInterval != nil && in.Spec.SyncInterval.Duration < MinChartRepoSyncInterval {
+		return fmt.Errorf(".spec.syncInterval should not be less than %s", MinChartRepoSyncInterval)
+	}
+	return nil
 }
 
 func (in *ChartRepo) ValidateUpdate(old runtime.Object) error {
@@ -194,71 +329,243 @@ func (in *ChartRepo) ValidateUpdate(old runtime.Object) error {
 	if in.Spec.URL != oldRepo.Spec.URL {
 		return fmt.Errorf(".spec.url is immutable")
 	}
-	return nil
+	if err := in.validateVerification(); err != nil {
+		return err
+	}
+	if err := in.validateNamespaceSelector(); err != nil {
+		return err
+	}
+	return in.validateSyncInterval()
 }
 
 func (in *ChartRepo) ValidateDelete() error {
 	return nil
 }
 
+// GetSyncInterval returns the sync interval of this repo, use DefaultChartRepoSyncInterval if not set.
+// Intervals less than MinChartRepoSyncInterval(eg: created before the webhook is enabled) are raised to it.
+func (in *ChartRepo) GetSyncInterval() time.Duration {
+	if in.Spec.SyncInterval == nil || in.Spec.SyncInterval.Duration <= 0 {
+		return DefaultChartRepoSyncInterval
+	}
+	if in.Spec.SyncInterval.Duration < MinChartRepoSyncInterval {
+		return MinChartRepoSyncInterval
+	}
+	return in.Spec.SyncInterval.Duration
+}
+
+// NeedsResync checks if this repo should be synced right now regardless of the interval:
+// 1. never synced
+// 2. spec changed since last sync
+// 3. a new resync request is set by ResyncAnnotation
+func (in *ChartRepo) NeedsResync() bool {
+	if in.Status.LastSyncTime == nil {
+		return true
+	}
+	if in.Generation != in.Status.ObservedGeneration {
+		return true
+	}
+	return in.GetAnnotations()[ResyncAnnotation] != in.Status.ObservedResyncRequest
+}
+
+// NextSyncTime returns when the next sync is due. If a resync is needed, it's the zero time.
+func (in *ChartRepo) NextSyncTime() time.Time {
+	if in.NeedsResync() {
+		return time.Time{}
+	}
+	return in.Status.LastSyncTime.Add(in.GetSyncInterval())
+}
+
+// RequeueAfter returns how long to wait from now to the next sync, so it can be used by controllers
+// as RequeueAfter. 0 means sync now.
+func (in *ChartRepo) RequeueAfter(now time.Time) time.Duration {
+	next := in.NextSyncTime()
+	if next.IsZero() || !next.After(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// MarkSynced records a finished sync in status. start is when this sync begins, charts is the number
+// of charts found and revision is the source revision, empty for Chart type
+func (in *ChartRepo) MarkSynced(start, end time.Time, charts int, revision string) {
+	in.Status.LastSyncTime = &metav1.Time{Time: end}
+	in.Status.LastSyncDuration = &metav1.Duration{Duration: end.Sub(start)}
+	in.Status.ObservedGeneration = in.Generation
+	in.Status.ObservedResyncRequest = in.GetAnnotations()[ResyncAnnotation]
+	in.Status.ChartCount = charts
+	in.Status.Revision = revision
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type Chart struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ChartSpec `json:"spec"`
+	Spec   ChartSpec   `json:"spec"`
+	Status ChartStatus `json:"status,omitempty"`
 }
 
 type ChartSpec struct {
 	Versions []*ChartVersion `json:"versions,omitempty"`
 }
 
-type ChartVersion struct {
-	repo.ChartVersion
+// ChartStatus is the status of a Chart, it's refreshed every time the ChartRepo synced
+type ChartStatus struct {
+	// Repo is the name of the ChartRepo this chart comes from
+	Repo string `json:"repo,omitempty"`
+	// LastRefreshTime is when this chart was last refreshed from the repo
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+	// LatestVersion is the latest available and stable version of this chart
+	LatestVersion string `json:"latestVersion,omitempty"`
+	// Versions contains the status of each version, including versions removed from the repo
+	Versions []ChartVersionStatus `json:"versions,omitempty"`
 }
 
-func (in *ChartVersion) DeepCopyInto(out *ChartVersion) {
-	if in == nil {
-		return
+// ChartVersionStatus is the status of a chart version
+type ChartVersionStatus struct {
+	Version string `json:"version"`
+	// Available is false if this version has been removed(yanked) from the repo index
+	Available bool `json:"available"`
+	// Deprecated is true if the chart version is marked as deprecated in Chart.yaml
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Verification is the verification result of this version, nil means it's not verified yet
+	Verification *ChartVerificationStatus `json:"verification,omitempty"`
+}
+
+// ChartVerificationStatus is the verification result of a chart version
+type ChartVerificationStatus struct {
+	// Mode is the verification mode used
+	Mode ChartVerificationMode `json:"mode"`
+	// Verified is true if the chart archive passed the verification, it's always false in None mode
+	Verified bool `json:"verified"`
+	// Digest is the sha256 digest of the verified chart archive
+	Digest string `json:"digest,omitempty"`
+	// SignedBy is the identity who signed the provenance file, only for Provenance mode
+	SignedBy string `json:"signedBy,omitempty"`
+	// Fingerprint is the fingerprint of the signing key, only for Provenance mode
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Reason is why the verification failed
+	Reason string `json:"reason,omitempty"`
+	// Time is when the verification happened
+	Time metav1.Time `json:"time,omitempty"`
+}
+
+// GetVersionStatus returns the status of a chart version, nil if not found
+func (in *Chart) GetVersionStatus(version string) *ChartVersionStatus {
+	for i := range in.Status.Versions {
+		if in.Status.Versions[i].Version == version {
+			return &in.Status.Versions[i]
+		}
 	}
+	return nil
+}
 
-	b, err := yaml.Marshal(in.ChartVersion)
-	if err != nil {
-		return
+// RefreshStatus refreshes the status from the spec versions. Versions that once existed but no longer
+// in the spec are kept in status as unavailable, so consumers know they are yanked.
+func (in *Chart) RefreshStatus(repo string, now time.Time) {
+	in.Status.Repo = repo
+	in.Status.LastRefreshTime = &metav1.Time{Time: now}
+
+	current := map[string]*ChartVersion{}
+	for _, v := range in.Spec.Versions {
+		if v != nil {
+			current[v.Version] = v
+		}
 	}
-	var r repo.ChartVersion
-	err = yaml.Unmarshal(b, &r)
-	if err != nil {
+
+	var versions []ChartVersionStatus
+	for _, status := range in.Status.Versions {
+		if _, ok := current[status.Version]; !ok {
+			status.Available = false
+			versions = append(versions, status)
+		}
+	}
+	for _, v := range in.Spec.Versions {
+		if v == nil {
+			continue
+		}
+		status := ChartVersionStatus{Version: v.Version}
+		if old := in.GetVersionStatus(v.Version); old != nil {
+			status.Verification = old.Verification
+		}
+		status.Available = !v.Removed
+		status.Deprecated = v.Deprecated
+		versions = append(versions, status)
+	}
+	in.Status.Versions = versions
+
+	in.Status.LatestVersion = ""
+	if latest := in.Latest(false); latest != nil {
+		in.Status.LatestVersion = latest.Version
+	}
+}
+
+// IsVersionAvailable checks if the version exists in the repo and not removed
+func (in *Chart) IsVersionAvailable(version string) bool {
+	status := in.GetVersionStatus(version)
+	return status != nil && status.Available
+}
+
+// SetVerification records the verification result of a chart version
+func (in *Chart) SetVerification(version string, result *ChartVerificationStatus) {
+	if status := in.GetVersionStatus(version); status != nil {
+		status.Verification = result
 		return
 	}
+	in.Status.Versions = append(in.Status.Versions, ChartVersionStatus{Version: version, Available: true, Verification: result})
+}
+
+// IsVersionVerified checks if the chart version passed verification. Versions from repos
+// without verification are never verified.
+func (in *Chart) IsVersionVerified(version string) bool {
+	status := in.GetVersionStatus(version)
+	if status == nil || status.Verification == nil {
+		return false
+	}
+	return status.Verification.Verified && status.Verification.Mode != ChartVerificationNone
+}
 
-	out.ChartVersion = r
+// ChartVersion is a version of a chart in the chart repo index. It has the same json format as the
+// ChartVersion in helm's repo package, but does not depend on it.
+type ChartVersion struct {
+	ChartMetadata `json:",inline"`
+	// URLs are the urls to download this chart version
+	URLs []string `json:"urls"`
+	// Created is when this chart version is packaged
+	Created metav1.Time `json:"created,omitempty"`
+	// Removed is true if this version has been removed from the repo
+	Removed bool `json:"removed,omitempty"`
+	// Digest is the sha256 digest of the chart archive
+	Digest string `json:"digest,omitempty"`
 }
 
+// ChartMetadata models the structure of a Chart.yaml file.
 // ref: https://github.com/helm/helm/blob/master/docs/charts.md
-/*type ChartVersion struct {
+type ChartMetadata struct {
+	// The name of the chart
+	Name string `json:"name,omitempty"`
 	// The URL to a relevant project page, git repo, or contact person
 	Home string `json:"home,omitempty"`
 	// Source is the URL to the source code of this chart
-	Sources string `json:"sources,omitempty"`
+	Sources []string `json:"sources,omitempty"`
 	// A SemVer 2 conformant version string of the chart
 	Version string `json:"version,omitempty"`
 	// A one-sentence description of the chart
 	Description string `json:"description,omitempty"`
 	// A list of string keywords
-	Keywords []string `json:"keywords, omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
 	// A list of name and URL/email address combinations for the maintainer(s)
-	Maintainers []*chart.Maintainer `json:"maintainers,omitempty"`
+	Maintainers []ChartMaintainer `json:"maintainers,omitempty"`
 	// The URL to an icon file.
 	Icon string `json:"icon,omitempty"`
-
 	// The API Version of this chart.
-	APIVersion string `json:"apiVersion, omitempty"`
-
+	APIVersion string `json:"apiVersion,omitempty"`
 	// The condition to check to enable chart
 	Condition string `json:"condition,omitempty"`
-
+	// The tags to check to enable chart
+	Tags string `json:"tags,omitempty"`
 	// The version of the application enclosed inside of this chart.
 	AppVersion string `json:"appVersion,omitempty"`
 	// Whether or not this chart is deprecated
@@ -268,11 +575,42 @@ func (in *ChartVersion) DeepCopyInto(out *ChartVersion) {
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// KubeVersion is a SemVer constraint specifying the version of Kubernetes required.
 	KubeVersion string `json:"kubeVersion,omitempty"`
+	// Dependencies are a list of dependencies for a chart.
+	Dependencies []ChartDependency `json:"dependencies,omitempty"`
+	// Specifies the chart type: application or library
+	Type string `json:"type,omitempty"`
+}
 
-	Created metav1.Time `json:"created,omitempty"`
+// ChartMaintainer describes a Chart maintainer.
+type ChartMaintainer struct {
+	// Name is a user name or organization name
+	Name string `json:"name,omitempty"`
+	// Email is an optional email address to contact the named maintainer
+	Email string `json:"email,omitempty"`
+	// URL is an optional URL to an address for the named maintainer
+	URL string `json:"url,omitempty"`
+}
 
-	Digest string `json:"digest,omitempty"`
-}*/
+// ChartDependency describes a chart upon which another chart depends.
+type ChartDependency struct {
+	// Name is the name of the dependency.
+	Name string `json:"name"`
+	// Version is the version (range) of this chart.
+	Version string `json:"version,omitempty"`
+	// The URL to the repository.
+	Repository string `json:"repository"`
+	// A yaml path that resolves to a boolean, used for enabling/disabling charts
+	Condition string `json:"condition,omitempty"`
+	// Tags can be used to group charts for enabling/disabling together
+	Tags []string `json:"tags,omitempty"`
+	// Enabled bool determines if chart should be loaded
+	Enabled bool `json:"enabled,omitempty"`
+	// ImportValues holds the mapping of source values to parent key to be imported. Each item can be a
+	// string or a map
+	ImportValues []runtime.RawExtension `json:"import-values,omitempty"`
+	// Alias usable alias to be used for the chart
+	Alias string `json:"alias,omitempty"`
+}
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -284,6 +622,99 @@ type ChartList struct {
 	Items []Chart `json:"items"`
 }
 
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterChartRepo is a cluster scoped ChartRepo, its charts are available to HelmRequests in all
+// namespaces and stored as ClusterCharts
+type ClusterChartRepo struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ChartRepoSpec   `json:"spec"`
+	Status            ChartRepoStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ClusterChartRepoList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterChartRepo `json:"items"`
+}
+
+// AsChartRepo returns a ChartRepo shares the same spec and status, so the sync and validation logic of
+// ChartRepo can be reused. Changes to the returned object are not reflected to the ClusterChartRepo.
+func (in *ClusterChartRepo) AsChartRepo() *ChartRepo {
+	out := &ChartRepo{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return out
+}
+
+// Default set the default sync interval for ClusterChartRepo
+func (in *ClusterChartRepo) Default() {
+	if in.Spec.SyncInterval == nil {
+		in.Spec.SyncInterval = &metav1.Duration{Duration: DefaultChartRepoSyncInterval}
+	}
+}
+
+func (in *ClusterChartRepo) ValidateCreate() error {
+	return in.AsChartRepo().ValidateCreate()
+}
+
+func (in *ClusterChartRepo) ValidateUpdate(old runtime.Object) error {
+	oldRepo, ok := old.(*ClusterChartRepo)
+	if !ok {
+		return fmt.Errorf("expect old object to be a %T instead of %T", oldRepo, old)
+	}
+	return in.AsChartRepo().ValidateUpdate(oldRepo.AsChartRepo())
+}
+
+func (in *ClusterChartRepo) ValidateDelete() error {
+	return nil
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterChart is a chart in a ClusterChartRepo, named the same way as Chart, see ChartObjectName
+type ClusterChart struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChartSpec   `json:"spec"`
+	Status ChartStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ClusterChartList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterChart `json:"items"`
+}
+
+// AsChart returns a Chart shares the same spec and status, so the version helpers of Chart can be used
+// on ClusterChart. The returned object is a copy.
+func (in *ClusterChart) AsChart() *Chart {
+	out := &Chart{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return out
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -320,10 +751,47 @@ type HelmRequestSpec struct {
 	Namespace string `json:"namespace,omitempty"`
 	// ValuesFrom represents values from ConfigMap/Secret...
 	ValuesFrom []ValuesFromSource `json:"valuesFrom,omitempty"`
+	// ChartKind is the kind of the chart resource .spec.chart refers to, Chart or ClusterChart. If it's
+	// empty, Chart in the namespace is looked up first, then ClusterChart. Only for charts in repos
+	ChartKind ChartKind `json:"chartKind,omitempty"`
+	// DriftPolicy is what to do when the live objects drift from the Release, default to DriftPolicyDetect
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// RequireVerifiedChart will refuse to install the chart version if it's not verified, see ChartVerification
+	RequireVerifiedChart bool `json:"requireVerifiedChart,omitempty"`
 	// values is a map
 	HelmValues `json:",inline"`
 }
 
+// ChartKind is the kind of the resource a chart is stored as
+type ChartKind string
+
+const (
+	// ChartKindChart is the namespaced Chart, synced from a ChartRepo
+	ChartKindChart ChartKind = "Chart"
+	// ChartKindClusterChart is the cluster scoped ClusterChart, synced from a ClusterChartRepo
+	ChartKindClusterChart ChartKind = "ClusterChart"
+)
+
+// DriftPolicy is the policy of handling the drift between the live objects and the Release
+type DriftPolicy string
+
+const (
+	// DriftPolicyIgnore disables the drift detection
+	DriftPolicyIgnore DriftPolicy = "Ignore"
+	// DriftPolicyDetect detects the drift and records it in the Drifted condition
+	DriftPolicyDetect DriftPolicy = "Detect"
+	// DriftPolicyCorrect detects the drift and patches the drifted objects back to the Release
+	DriftPolicyCorrect DriftPolicy = "Correct"
+)
+
+// GetDriftPolicy returns the drift policy, default to DriftPolicyDetect
+func (in *HelmRequest) GetDriftPolicy() DriftPolicy {
+	if in.Spec.DriftPolicy == "" {
+		return DriftPolicyDetect
+	}
+	return in.Spec.DriftPolicy
+}
+
 //ValuesFromSource represents a source of values, only one of it's fields may be set
 type ValuesFromSource struct {
 	// ConfigMapKeyRef selects a key of a ConfigMap
@@ -337,23 +805,6 @@ type HelmValues struct {
 	chartutil.Values `json:"values,omitempty"`
 }
 
-func (in *HelmValues) DeepCopyInto(out *HelmValues) {
-	if in == nil {
-		return
-	}
-
-	b, err := yaml.Marshal(in.Values)
-	if err != nil {
-		return
-	}
-	var values chartutil.Values
-	err = yaml.Unmarshal(b, &values)
-	if err != nil {
-		return
-	}
-	out.Values = values
-}
-
 // HelmRequestPhase is a label for the condition of a HelmRequest at the current time.
 type HelmRequestPhase string
 
@@ -388,6 +839,9 @@ type HelmRequestStatus struct {
 
 	// Reason will store the reason why the HelmRequest deploy failed
 	Reason string `json:"reason,omitempty"`
+
+	// Conditions are the latest observations of the HelmRequest, see HelmRequestConditionType
+	Conditions []HelmRequestCondition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -426,14 +880,63 @@ func (in *HelmRequest) Default() {
 		klog.Info("use helmrequest namespace as release namespace: ", in.GetNamespace())
 	}
 
-	in.Finalizers = []string{FinalizerName}
-	klog.V(4).Info("append finalizers to helmrequest: ", in.GetName())
+	// keep the finalizers added by others
+	if in.AddFinalizer(FinalizerName) {
+		klog.V(4).Info("append finalizers to helmrequest: ", in.GetName())
+	}
+}
+
+// HasFinalizer returns true if the HelmRequest has the finalizer
+func (in *HelmRequest) HasFinalizer(name string) bool {
+	for _, f := range in.Finalizers {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer appends the finalizer if it's not present, the existing ones are kept.
+// Returns true if the finalizers are changed
+func (in *HelmRequest) AddFinalizer(name string) bool {
+	if in.HasFinalizer(name) {
+		return false
+	}
+	in.Finalizers = append(in.Finalizers, name)
+	return true
+}
+
+// RemoveFinalizer removes the finalizer, the others are kept in order.
+// Returns true if the finalizers are changed
+func (in *HelmRequest) RemoveFinalizer(name string) bool {
+	if !in.HasFinalizer(name) {
+		return false
+	}
+	var finalizers []string
+	for _, f := range in.Finalizers {
+		if f != name {
+			finalizers = append(finalizers, f)
+		}
+	}
+	in.Finalizers = finalizers
+	return true
 }
 
 //ValidateCreate implements webhook.Validator
 // 1. check filed regex
+// 2. check chart reference
 func (in *HelmRequest) ValidateCreate() error {
 	klog.V(4).Info("validate HelmRequest create: ", in.GetName())
+	if _, err := in.validateChart(); err != nil {
+		return err
+	}
+	if err := in.validateChartKind(); err != nil {
+		return err
+	}
+	if err := in.validateDriftPolicy(); err != nil {
+		return err
+	}
+
 	if in.Spec.ClusterName != "" && !regex.IsValidResourceName(in.Spec.ClusterName) {
 		return in.nameRegexError(".spec.clusterName", in.Spec.ClusterName)
 	}
@@ -476,13 +979,21 @@ func (in *HelmRequest) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("expect old object to be a %T instead of %T", oldHR, old)
 	}
 
-	// check chart name
+	// check chart name, the repo and version can be changed
+	ref, err := in.validateChart()
+	if err != nil {
+		return err
+	}
 	_, oldChart := ParseChartName(oldHR.Spec.Chart)
-	_, newChart := ParseChartName(in.Spec.Chart)
-
-	if oldChart != newChart {
+	if oldChart != ref.Chart {
 		return fmt.Errorf("chart name cannot be updated after create")
 	}
+	if err := in.validateChartKind(); err != nil {
+		return err
+	}
+	if err := in.validateDriftPolicy(); err != nil {
+		return err
+	}
 
 	// check dependency
 	if !reflect.DeepEqual(oldHR.Spec.Dependencies, in.Spec.Dependencies) {
@@ -518,6 +1029,14 @@ func (in *HelmRequest) ValidateUpdate(old runtime.Object) error {
 
 }
 
+func (in *HelmRequest) validateDriftPolicy() error {
+	switch in.Spec.DriftPolicy {
+	case "", DriftPolicyIgnore, DriftPolicyDetect, DriftPolicyCorrect:
+		return nil
+	}
+	return fmt.Errorf("unknown .spec.driftPolicy: %s", in.Spec.DriftPolicy)
+}
+
 func (in *HelmRequest) ValidateDelete() error {
 	return nil
 }
@@ -555,7 +1074,13 @@ func (in *HelmRequest) GetReleaseNamespace() string {
 }
 
 // ParseChartName is a simple function that parse chart name
+// Deprecated: use ParseChartReference, which also handles namespaced repos, OCI references, urls and versions.
+// If name is not a valid reference, it's split by "/" as before.
 func ParseChartName(name string) (repo, chart string) {
+	if ref, err := ParseChartReference(name); err == nil {
+		return ref.Repo, ref.Chart
+	}
+
 	data := strings.Split(name, "/")
 	if len(data) == 1 {
 		return "", name
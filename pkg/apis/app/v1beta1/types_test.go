@@ -0,0 +1,341 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newHelmRequest(chart, version string) *HelmRequest {
+	return &HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec:       HelmRequestSpec{Chart: chart, Version: version},
+	}
+}
+
+func TestHelmRequestDefault(t *testing.T) {
+	deleting := metav1.Now()
+	tests := []struct {
+		name       string
+		finalizers []string
+		deletion   *metav1.Time
+		expect     []string
+	}{
+		{name: "nil finalizers", finalizers: nil, expect: []string{FinalizerName}},
+		{name: "third-party only", finalizers: []string{"foregroundDeletion", "example.com/cleanup"}, expect: []string{"foregroundDeletion", "example.com/cleanup", FinalizerName}},
+		{name: "already added", finalizers: []string{FinalizerName, "example.com/cleanup"}, expect: []string{FinalizerName, "example.com/cleanup"}},
+		{name: "deleting", finalizers: []string{"example.com/cleanup"}, deletion: &deleting, expect: []string{"example.com/cleanup"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := newHelmRequest("stable/nginx", "")
+			hr.Finalizers = tt.finalizers
+			hr.DeletionTimestamp = tt.deletion
+			hr.Default()
+			if !reflect.DeepEqual(hr.Finalizers, tt.expect) {
+				t.Errorf("expect finalizers %v, got %v", tt.expect, hr.Finalizers)
+			}
+			// Default is called on every update, it should be idempotent
+			hr.Default()
+			if !reflect.DeepEqual(hr.Finalizers, tt.expect) {
+				t.Errorf("expect finalizers %v after defaulted twice, got %v", tt.expect, hr.Finalizers)
+			}
+
+			if tt.deletion != nil {
+				if hr.Spec.ReleaseName != "" || hr.Spec.Namespace != "" {
+					t.Errorf("expect spec untouched, got %+v", hr.Spec)
+				}
+			} else if hr.Spec.ReleaseName != "nginx" || hr.Spec.Namespace != "default" {
+				t.Errorf("expect release nginx in default, got %s in %s", hr.Spec.ReleaseName, hr.Spec.Namespace)
+			}
+		})
+	}
+}
+
+func TestHelmRequestFinalizers(t *testing.T) {
+	tests := []struct {
+		name       string
+		finalizers []string
+		add        string
+		remove     string
+		has        bool
+		changed    bool
+		expect     []string
+	}{
+		{name: "add to nil", add: "b", changed: true, expect: []string{"b"}},
+		{name: "add to the end", finalizers: []string{"c", "a"}, add: "b", changed: true, expect: []string{"c", "a", "b"}},
+		{name: "add existing", finalizers: []string{"c", "b", "a"}, add: "b", has: true, expect: []string{"c", "b", "a"}},
+		{name: "remove from nil", remove: "b"},
+		{name: "remove missing", finalizers: []string{"c", "a"}, remove: "b", expect: []string{"c", "a"}},
+		{name: "remove in the middle", finalizers: []string{"c", "b", "a"}, remove: "b", has: true, changed: true, expect: []string{"c", "a"}},
+		{name: "remove duplicated", finalizers: []string{"b", "c", "b", "a"}, remove: "b", has: true, changed: true, expect: []string{"c", "a"}},
+		{name: "remove the last one", finalizers: []string{"b"}, remove: "b", has: true, changed: true, expect: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := newHelmRequest("stable/nginx", "")
+			hr.Finalizers = tt.finalizers
+			name := tt.add + tt.remove
+			if has := hr.HasFinalizer(name); has != tt.has {
+				t.Errorf("expect HasFinalizer(%s) %v, got %v", name, tt.has, has)
+			}
+
+			var changed bool
+			if tt.add != "" {
+				changed = hr.AddFinalizer(tt.add)
+			} else {
+				changed = hr.RemoveFinalizer(tt.remove)
+			}
+			if changed != tt.changed {
+				t.Errorf("expect changed %v, got %v", tt.changed, changed)
+			}
+			if !reflect.DeepEqual(hr.Finalizers, tt.expect) {
+				t.Errorf("expect finalizers %v, got %v", tt.expect, hr.Finalizers)
+			}
+			if has := hr.HasFinalizer(name); has != (tt.add != "") {
+				t.Errorf("expect HasFinalizer(%s) %v after changed, got %v", name, tt.add != "", has)
+			}
+		})
+	}
+}
+
+func TestChartRepoDefault(t *testing.T) {
+	repo := &ChartRepo{}
+	repo.Default()
+	if repo.Spec.SyncInterval == nil || repo.Spec.SyncInterval.Duration != DefaultChartRepoSyncInterval {
+		t.Errorf("expect sync interval %s, got %v", DefaultChartRepoSyncInterval, repo.Spec.SyncInterval)
+	}
+
+	repo.Spec.SyncInterval = &metav1.Duration{Duration: time.Hour}
+	repo.Default()
+	if repo.Spec.SyncInterval.Duration != time.Hour {
+		t.Errorf("expect sync interval %s, got %s", time.Hour, repo.Spec.SyncInterval.Duration)
+	}
+}
+
+func TestChartRepoGetSyncInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval *metav1.Duration
+		expect   time.Duration
+	}{
+		{name: "not set", expect: DefaultChartRepoSyncInterval},
+		{name: "zero", interval: &metav1.Duration{}, expect: DefaultChartRepoSyncInterval},
+		{name: "negative", interval: &metav1.Duration{Duration: -time.Minute}, expect: DefaultChartRepoSyncInterval},
+		{name: "less than min", interval: &metav1.Duration{Duration: time.Second}, expect: MinChartRepoSyncInterval},
+		{name: "min", interval: &metav1.Duration{Duration: MinChartRepoSyncInterval}, expect: MinChartRepoSyncInterval},
+		{name: "set", interval: &metav1.Duration{Duration: time.Hour}, expect: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ChartRepo{Spec: ChartRepoSpec{SyncInterval: tt.interval}}
+			if got := repo.GetSyncInterval(); got != tt.expect {
+				t.Errorf("expect %s, got %s", tt.expect, got)
+			}
+			err := repo.ValidateCreate()
+			if tt.interval != nil && tt.interval.Duration < MinChartRepoSyncInterval && err == nil {
+				t.Error("expect error for interval less than min")
+			}
+		})
+	}
+}
+
+func newSyncedChartRepo(generation, observed int64, annotation, observedRequest string, lastSync time.Time) *ChartRepo {
+	repo := &ChartRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default", Generation: generation},
+		Spec:       ChartRepoSpec{SyncInterval: &metav1.Duration{Duration: 10 * time.Minute}},
+		Status: ChartRepoStatus{
+			ObservedGeneration:    observed,
+			ObservedResyncRequest: observedRequest,
+		},
+	}
+	if annotation != "" {
+		repo.Annotations = map[string]string{ResyncAnnotation: annotation}
+	}
+	if !lastSync.IsZero() {
+		repo.Status.LastSyncTime = &metav1.Time{Time: lastSync}
+	}
+	return repo
+}
+
+func TestChartRepoNeedsResync(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		repo         *ChartRepo
+		needsResync  bool
+		requeueAfter time.Duration
+	}{
+		{name: "never synced", repo: newSyncedChartRepo(1, 0, "", "", time.Time{}), needsResync: true},
+		{name: "synced", repo: newSyncedChartRepo(1, 1, "", "", now.Add(-time.Minute)), requeueAfter: 9 * time.Minute},
+		{name: "due", repo: newSyncedChartRepo(1, 1, "", "", now.Add(-10*time.Minute))},
+		{name: "overdue", repo: newSyncedChartRepo(1, 1, "", "", now.Add(-time.Hour))},
+		{name: "spec changed", repo: newSyncedChartRepo(2, 1, "", "", now.Add(-time.Minute)), needsResync: true},
+		{name: "resync requested", repo: newSyncedChartRepo(1, 1, "t1", "", now.Add(-time.Minute)), needsResync: true},
+		{name: "new resync requested", repo: newSyncedChartRepo(1, 1, "t2", "t1", now.Add(-time.Minute)), needsResync: true},
+		{name: "resync handled", repo: newSyncedChartRepo(1, 1, "t1", "t1", now.Add(-time.Minute)), requeueAfter: 9 * time.Minute},
+		{name: "annotation removed", repo: newSyncedChartRepo(1, 1, "", "t1", now.Add(-time.Minute)), needsResync: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.NeedsResync(); got != tt.needsResync {
+				t.Errorf("expect NeedsResync %v, got %v", tt.needsResync, got)
+			}
+			if next := tt.repo.NextSyncTime(); next.IsZero() != tt.needsResync {
+				t.Errorf("expect zero NextSyncTime %v, got %s", tt.needsResync, next)
+			}
+			if got := tt.repo.RequeueAfter(now); got != tt.requeueAfter {
+				t.Errorf("expect RequeueAfter %s, got %s", tt.requeueAfter, got)
+			}
+		})
+	}
+}
+
+func TestChartRepoMarkSynced(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Second)
+	repo := newSyncedChartRepo(3, 1, "t2", "t1", time.Time{})
+	if !repo.NeedsResync() {
+		t.Fatal("expect resync before synced")
+	}
+
+	repo.MarkSynced(start, end, 5, "abc")
+	if !repo.Status.LastSyncTime.Time.Equal(end) || repo.Status.LastSyncDuration.Duration != 3*time.Second {
+		t.Errorf("unexpected sync time: %s, %s", repo.Status.LastSyncTime, repo.Status.LastSyncDuration)
+	}
+	if repo.Status.ObservedGeneration != 3 || repo.Status.ObservedResyncRequest != "t2" {
+		t.Errorf("expect observed generation 3 and request t2, got %d and %s", repo.Status.ObservedGeneration, repo.Status.ObservedResyncRequest)
+	}
+	if repo.Status.ChartCount != 5 || repo.Status.Revision != "abc" {
+		t.Errorf("expect 5 charts at abc, got %d at %s", repo.Status.ChartCount, repo.Status.Revision)
+	}
+	if repo.NeedsResync() {
+		t.Error("expect no resync after synced")
+	}
+	if got := repo.RequeueAfter(end); got != 10*time.Minute {
+		t.Errorf("expect RequeueAfter %s, got %s", 10*time.Minute, got)
+	}
+}
+
+func newChart(versions ...*ChartVersion) *Chart {
+	return &Chart{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx.stable", Namespace: "default"},
+		Spec:       ChartSpec{Versions: versions},
+	}
+}
+
+func newChartVersion(version string) *ChartVersion {
+	return &ChartVersion{ChartMetadata: ChartMetadata{Name: "nginx", Version: version}}
+}
+
+func TestChartRefreshStatus(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	verified := &ChartVerificationStatus{Mode: ChartVerificationDigest, Verified: true}
+
+	removed := newChartVersion("1.1.0")
+	removed.Removed = true
+	deprecated := newChartVersion("0.9.0")
+	deprecated.Deprecated = true
+	chart := newChart(newChartVersion("1.0.0"), removed, deprecated, newChartVersion("2.0.0-rc.1"))
+	chart.RefreshStatus("stable", now)
+
+	if chart.Status.Repo != "stable" || !chart.Status.LastRefreshTime.Time.Equal(now) {
+		t.Errorf("unexpected status: %+v", chart.Status)
+	}
+	if chart.Status.LatestVersion != "1.0.0" {
+		t.Errorf("expect latest version 1.0.0, got %s", chart.Status.LatestVersion)
+	}
+	for _, tt := range []struct {
+		version    string
+		available  bool
+		deprecated bool
+	}{
+		{version: "1.0.0", available: true},
+		{version: "1.1.0", available: false},
+		{version: "0.9.0", available: true, deprecated: true},
+		{version: "2.0.0-rc.1", available: true},
+	} {
+		status := chart.GetVersionStatus(tt.version)
+		if status == nil {
+			t.Errorf("expect status of %s", tt.version)
+			continue
+		}
+		if status.Available != tt.available || status.Deprecated != tt.deprecated {
+			t.Errorf("expect %s available %v and deprecated %v, got %+v", tt.version, tt.available, tt.deprecated, status)
+		}
+		if chart.IsVersionAvailable(tt.version) != tt.available {
+			t.Errorf("expect IsVersionAvailable(%s) %v", tt.version, tt.available)
+		}
+	}
+
+	// 1.0.0 is removed from the index, the verification of 2.0.0-rc.1 is kept
+	chart.SetVerification("1.0.0", verified)
+	chart.SetVerification("2.0.0-rc.1", verified)
+	chart.Spec.Versions = []*ChartVersion{newChartVersion("2.0.0-rc.1"), nil, newChartVersion("2.0.0")}
+	chart.RefreshStatus("stable", now.Add(time.Minute))
+
+	if chart.Status.LatestVersion != "2.0.0" {
+		t.Errorf("expect latest version 2.0.0, got %s", chart.Status.LatestVersion)
+	}
+	if chart.IsVersionAvailable("1.0.0") || chart.IsVersionAvailable("1.1.0") || chart.IsVersionAvailable("0.9.0") {
+		t.Errorf("expect versions removed from the index unavailable, got %+v", chart.Status.Versions)
+	}
+	if status := chart.GetVersionStatus("1.0.0"); status == nil || status.Verification != verified {
+		t.Errorf("expect the removed version keeps its status, got %+v", status)
+	}
+	if !chart.IsVersionAvailable("2.0.0") || !chart.IsVersionVerified("2.0.0-rc.1") || chart.IsVersionVerified("2.0.0") {
+		t.Errorf("unexpected versions status: %+v", chart.Status.Versions)
+	}
+	if len(chart.Status.Versions) != 5 {
+		t.Errorf("expect 5 versions in status, got %+v", chart.Status.Versions)
+	}
+
+	// no available stable version
+	chart.Spec.Versions = []*ChartVersion{removed}
+	chart.RefreshStatus("stable", now)
+	if chart.Status.LatestVersion != "" {
+		t.Errorf("expect no latest version, got %s", chart.Status.LatestVersion)
+	}
+}
+
+func TestChartVerification(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *ChartVerificationStatus
+		verified bool
+	}{
+		{name: "not verified yet"},
+		{name: "digest", result: &ChartVerificationStatus{Mode: ChartVerificationDigest, Verified: true}, verified: true},
+		{name: "provenance", result: &ChartVerificationStatus{Mode: ChartVerificationProvenance, Verified: true, SignedBy: "captain"}, verified: true},
+		{name: "failed", result: &ChartVerificationStatus{Mode: ChartVerificationDigest, Reason: "digest mismatch"}},
+		{name: "none", result: &ChartVerificationStatus{Mode: ChartVerificationNone, Verified: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := newChart(newChartVersion("1.0.0"), newChartVersion("1.1.0"))
+			chart.RefreshStatus("stable", time.Now())
+			if tt.result != nil {
+				chart.SetVerification("1.0.0", tt.result)
+			}
+			if got := chart.IsVersionVerified("1.0.0"); got != tt.verified {
+				t.Errorf("expect verified %v, got %v", tt.verified, got)
+			}
+			// the other versions are not affected
+			if chart.IsVersionVerified("1.1.0") {
+				t.Error("expect 1.1.0 not verified")
+			}
+		})
+	}
+
+	// the status is created for versions not refreshed yet
+	chart := newChart()
+	chart.SetVerification("1.0.0", &ChartVerificationStatus{Mode: ChartVerificationDigest, Verified: true})
+	if !chart.IsVersionVerified("1.0.0") || !chart.IsVersionAvailable("1.0.0") {
+		t.Errorf("unexpected status: %+v", chart.Status.Versions)
+	}
+	if chart.IsVersionVerified("2.0.0") {
+		t.Error("expect unknown version not verified")
+	}
+}
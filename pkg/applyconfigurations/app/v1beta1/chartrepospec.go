@@ -0,0 +1,82 @@
+package v1beta1
+
+import (
+	appv1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChartRepoSpecApplyConfiguration represents an declarative configuration of the ChartRepoSpec type for use
+// with apply.
+// Nested structs are applied as a whole, so they are set by the API types.
+type ChartRepoSpecApplyConfiguration struct {
+	URL               *string                       `json:"url,omitempty"`
+	Secret            *corev1.SecretReference       `json:"secret,omitempty"`
+	Type              *string                       `json:"type,omitempty"`
+	Source            *appv1beta1.ChartRepoSource   `json:"source,omitempty"`
+	SyncInterval      *metav1.Duration              `json:"syncInterval,omitempty"`
+	Verification      *appv1beta1.ChartVerification `json:"verification,omitempty"`
+	NamespaceSelector *metav1.LabelSelector         `json:"namespaceSelector,omitempty"`
+}
+
+// ChartRepoSpec constructs an declarative configuration of the ChartRepoSpec type for use with
+// apply.
+func ChartRepoSpec() *ChartRepoSpecApplyConfiguration {
+	return &ChartRepoSpecApplyConfiguration{}
+}
+
+// WithURL sets the URL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the URL field is set to the value of the last call.
+func (b *ChartRepoSpecApplyConfiguration) WithURL(value string) *ChartRepoSpecApplyConfiguration {
+	b.URL = &value
+	return b
+}
+
+// WithSecret sets the Secret field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Secret field is set to the value of the last call.
+func (b *ChartRepoSpecApplyConfiguration) WithSecret(value corev1.SecretReference) *ChartRepoSpecApplyConfiguration {
+	b.Secret = &value
+	return b
+}
+
+// WithType sets the Type field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Type field is set to the value of the last call.
+func (b *ChartRepoSpecApplyConfiguration) WithType(value string) *ChartRepoSpecApplyConfiguration {
+	b.Type = &value
+	return b
+}
+
+// WithSource sets the Source field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Source field is set to the value of the last call.
+func (b *ChartRepoSpecApplyConfiguration) WithSource(value appv1beta1.ChartRepoSource) *ChartRepoSpecApplyConfiguration {
+	b.Source = &value
+	return b
+}
+
+// WithSyncInterval sets the SyncInterval field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SyncInterval field is set to the value of the last call.
+func (b *ChartRepoSpecApplyConfiguration) WithSyncInterval(value metav1.Duration) *ChartRepoSpecApplyConfiguration {
+	b.SyncInterval = &value
+	return b
+}
+
+// WithVerification sets the Verification field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Verification field is set to the value of the last call.
+func (b *ChartRepoSpecApplyConfiguration) WithVerification(value appv1beta1.ChartVerification) *ChartRepoSpecApplyConfiguration {
+	b.Verification = &value
+	return b
+}
+
+// WithNamespaceSelector sets the NamespaceSelector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NamespaceSelector field is set to the value of the last call.
+func (b *ChartRepoSpecApplyConfiguration) WithNamespaceSelector(value metav1.LabelSelector) *ChartRepoSpecApplyConfiguration {
+	b.NamespaceSelector = &value
+	return b
+}
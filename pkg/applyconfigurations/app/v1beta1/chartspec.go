@@ -0,0 +1,26 @@
+package v1beta1
+
+import (
+	appv1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+)
+
+// ChartSpecApplyConfiguration represents an declarative configuration of the ChartSpec type for use
+// with apply.
+// Nested structs are applied as a whole, so they are set by the API types.
+type ChartSpecApplyConfiguration struct {
+	Versions []*appv1beta1.ChartVersion `json:"versions,omitempty"`
+}
+
+// ChartSpec constructs an declarative configuration of the ChartSpec type for use with
+// apply.
+func ChartSpec() *ChartSpecApplyConfiguration {
+	return &ChartSpecApplyConfiguration{}
+}
+
+// WithVersions adds the given value to the Versions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Versions field.
+func (b *ChartSpecApplyConfiguration) WithVersions(values ...*appv1beta1.ChartVersion) *ChartSpecApplyConfiguration {
+	b.Versions = append(b.Versions, values...)
+	return b
+}
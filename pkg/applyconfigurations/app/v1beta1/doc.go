@@ -0,0 +1,5 @@
+// Package v1beta1 contains the apply configurations of the app.alauda.io/v1beta1 types, they are used
+// by the Apply methods of the typed clients for server-side apply. Only the fields set in a
+// configuration are sent, and owned by the field manager. They are written by hand, since the
+// code-generator in use doesn't generate apply configurations, so keep them in sync with the types.
+package v1beta1
@@ -0,0 +1,131 @@
+package v1beta1
+
+import (
+	appv1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+)
+
+// HelmRequestSpecApplyConfiguration represents an declarative configuration of the HelmRequestSpec type for use
+// with apply.
+// Nested structs are applied as a whole, so they are set by the API types.
+type HelmRequestSpecApplyConfiguration struct {
+	ClusterName          *string                       `json:"clusterName,omitempty"`
+	InstallToAllClusters *bool                         `json:"installToAllClusters,omitempty"`
+	Dependencies         []string                      `json:"dependencies,omitempty"`
+	ReleaseName          *string                       `json:"releaseName,omitempty"`
+	Chart                *string                       `json:"chart,omitempty"`
+	Version              *string                       `json:"version,omitempty"`
+	Namespace            *string                       `json:"namespace,omitempty"`
+	ValuesFrom           []appv1beta1.ValuesFromSource `json:"valuesFrom,omitempty"`
+	ChartKind            *appv1beta1.ChartKind         `json:"chartKind,omitempty"`
+	DriftPolicy          *appv1beta1.DriftPolicy       `json:"driftPolicy,omitempty"`
+	RequireVerifiedChart *bool                         `json:"requireVerifiedChart,omitempty"`
+	Values               map[string]interface{}        `json:"values,omitempty"`
+}
+
+// HelmRequestSpec constructs an declarative configuration of the HelmRequestSpec type for use with
+// apply.
+func HelmRequestSpec() *HelmRequestSpecApplyConfiguration {
+	return &HelmRequestSpecApplyConfiguration{}
+}
+
+// WithClusterName sets the ClusterName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClusterName field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithClusterName(value string) *HelmRequestSpecApplyConfiguration {
+	b.ClusterName = &value
+	return b
+}
+
+// WithInstallToAllClusters sets the InstallToAllClusters field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the InstallToAllClusters field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithInstallToAllClusters(value bool) *HelmRequestSpecApplyConfiguration {
+	b.InstallToAllClusters = &value
+	return b
+}
+
+// WithDependencies adds the given value to the Dependencies field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Dependencies field.
+func (b *HelmRequestSpecApplyConfiguration) WithDependencies(values ...string) *HelmRequestSpecApplyConfiguration {
+	b.Dependencies = append(b.Dependencies, values...)
+	return b
+}
+
+// WithReleaseName sets the ReleaseName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReleaseName field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithReleaseName(value string) *HelmRequestSpecApplyConfiguration {
+	b.ReleaseName = &value
+	return b
+}
+
+// WithChart sets the Chart field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Chart field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithChart(value string) *HelmRequestSpecApplyConfiguration {
+	b.Chart = &value
+	return b
+}
+
+// WithVersion sets the Version field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Version field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithVersion(value string) *HelmRequestSpecApplyConfiguration {
+	b.Version = &value
+	return b
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithNamespace(value string) *HelmRequestSpecApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithValuesFrom adds the given value to the ValuesFrom field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ValuesFrom field.
+func (b *HelmRequestSpecApplyConfiguration) WithValuesFrom(values ...appv1beta1.ValuesFromSource) *HelmRequestSpecApplyConfiguration {
+	b.ValuesFrom = append(b.ValuesFrom, values...)
+	return b
+}
+
+// WithChartKind sets the ChartKind field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ChartKind field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithChartKind(value appv1beta1.ChartKind) *HelmRequestSpecApplyConfiguration {
+	b.ChartKind = &value
+	return b
+}
+
+// WithDriftPolicy sets the DriftPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DriftPolicy field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithDriftPolicy(value appv1beta1.DriftPolicy) *HelmRequestSpecApplyConfiguration {
+	b.DriftPolicy = &value
+	return b
+}
+
+// WithRequireVerifiedChart sets the RequireVerifiedChart field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequireVerifiedChart field is set to the value of the last call.
+func (b *HelmRequestSpecApplyConfiguration) WithRequireVerifiedChart(value bool) *HelmRequestSpecApplyConfiguration {
+	b.RequireVerifiedChart = &value
+	return b
+}
+
+// WithValues puts the entries into the Values field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Values field,
+// overwriting an existing map entries in Values field with the same key.
+func (b *HelmRequestSpecApplyConfiguration) WithValues(entries map[string]interface{}) *HelmRequestSpecApplyConfiguration {
+	if b.Values == nil && len(entries) > 0 {
+		b.Values = make(map[string]interface{}, len(entries))
+	}
+	for k, v := range entries {
+		b.Values[k] = v
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+package v1beta1
+
+// ReleaseSpecApplyConfiguration represents an declarative configuration of the ReleaseSpec type for use
+// with apply.
+type ReleaseSpecApplyConfiguration struct {
+	ChartData    *string `json:"chartData,omitempty"`
+	ConfigData   *string `json:"configData,omitempty"`
+	ManifestData *string `json:"manifestData,omitempty"`
+	HooksData    *string `json:"hooksData,omitempty"`
+	Version      *int    `json:"version,omitempty"`
+	Name         *string `json:"name,omitempty"`
+}
+
+// ReleaseSpec constructs an declarative configuration of the ReleaseSpec type for use with
+// apply.
+func ReleaseSpec() *ReleaseSpecApplyConfiguration {
+	return &ReleaseSpecApplyConfiguration{}
+}
+
+// WithChartData sets the ChartData field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ChartData field is set to the value of the last call.
+func (b *ReleaseSpecApplyConfiguration) WithChartData(value string) *ReleaseSpecApplyConfiguration {
+	b.ChartData = &value
+	return b
+}
+
+// WithConfigData sets the ConfigData field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ConfigData field is set to the value of the last call.
+func (b *ReleaseSpecApplyConfiguration) WithConfigData(value string) *ReleaseSpecApplyConfiguration {
+	b.ConfigData = &value
+	return b
+}
+
+// WithManifestData sets the ManifestData field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ManifestData field is set to the value of the last call.
+func (b *ReleaseSpecApplyConfiguration) WithManifestData(value string) *ReleaseSpecApplyConfiguration {
+	b.ManifestData = &value
+	return b
+}
+
+// WithHooksData sets the HooksData field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the HooksData field is set to the value of the last call.
+func (b *ReleaseSpecApplyConfiguration) WithHooksData(value string) *ReleaseSpecApplyConfiguration {
+	b.HooksData = &value
+	return b
+}
+
+// WithVersion sets the Version field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Version field is set to the value of the last call.
+func (b *ReleaseSpecApplyConfiguration) WithVersion(value int) *ReleaseSpecApplyConfiguration {
+	b.Version = &value
+	return b
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *ReleaseSpecApplyConfiguration) WithName(value string) *ReleaseSpecApplyConfiguration {
+	b.Name = &value
+	return b
+}
@@ -0,0 +1,106 @@
+package chartserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// blobLabel marks the ConfigMaps managed by ConfigMapStore
+	blobLabel = "chartserver.captain.cpaas.io/blob"
+	// blobKeyAnnotation stores the original key of the blob
+	blobKeyAnnotation = "chartserver.captain.cpaas.io/key"
+	// blobDataKey is the key in ConfigMap.BinaryData
+	blobDataKey = "blob"
+)
+
+// ConfigMapStore is a Store keeps each blob in a ConfigMap, so the blobs can be shared by multi replicas
+// without a volume. A ConfigMap can not be larger than 1MB, so it only works for small charts.
+type ConfigMapStore struct {
+	Client    kubernetes.Interface
+	Namespace string
+}
+
+// NewConfigMapStore creates a ConfigMapStore keeps ConfigMaps in namespace
+func NewConfigMapStore(client kubernetes.Interface, namespace string) *ConfigMapStore {
+	return &ConfigMapStore{Client: client, Namespace: namespace}
+}
+
+func (c *ConfigMapStore) Get(_ context.Context, key string) ([]byte, error) {
+	cm, err := c.Client.CoreV1().ConfigMaps(c.Namespace).Get(configMapName(key), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cm.BinaryData[blobDataKey], nil
+}
+
+func (c *ConfigMapStore) Put(_ context.Context, key string, data []byte) error {
+	client := c.Client.CoreV1().ConfigMaps(c.Namespace)
+	name := configMapName(key)
+
+	old, err := client.Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   c.Namespace,
+				Labels:      map[string]string{blobLabel: "true"},
+				Annotations: map[string]string{blobKeyAnnotation: key},
+			},
+			BinaryData: map[string][]byte{blobDataKey: data},
+		}
+		_, err = client.Create(cm)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	cm := old.DeepCopy()
+	cm.BinaryData = map[string][]byte{blobDataKey: data}
+	_, err = client.Update(cm)
+	return err
+}
+
+func (c *ConfigMapStore) Delete(_ context.Context, key string) error {
+	err := c.Client.CoreV1().ConfigMaps(c.Namespace).Delete(configMapName(key), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (c *ConfigMapStore) List(_ context.Context, prefix string) ([]string, error) {
+	list, err := c.Client.CoreV1().ConfigMaps(c.Namespace).List(metav1.ListOptions{
+		LabelSelector: blobLabel + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, item := range list.Items {
+		key := item.GetAnnotations()[blobKeyAnnotation]
+		if key != "" && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// configMapName generates a valid ConfigMap name from the key
+func configMapName(key string) string {
+	return fmt.Sprintf("chart-blob-%x", sha256.Sum256([]byte(key)))[:51]
+}
@@ -0,0 +1,104 @@
+package chartserver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiskStore is a Store keeps blobs as files under a directory
+type DiskStore struct {
+	Dir string
+}
+
+// NewDiskStore creates a DiskStore in dir, dir will be created if not exist
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskStore{Dir: dir}, nil
+}
+
+func (d *DiskStore) Get(_ context.Context, key string) ([]byte, error) {
+	file, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Put writes to a temp file and renames it, so readers will never get a partial file
+func (d *DiskStore) Put(_ context.Context, key string, data []byte) error {
+	file, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (d *DiskStore) Delete(_ context.Context, key string) error {
+	file, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (d *DiskStore) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(d.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(d.Dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return keys, err
+}
+
+// path returns the file path of key, and make sure it's under the store dir
+func (d *DiskStore) path(key string) (string, error) {
+	file := filepath.Join(d.Dir, filepath.Clean("/"+key))
+	if !strings.HasPrefix(file, filepath.Clean(d.Dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid key %s", key)
+	}
+	return file, nil
+}
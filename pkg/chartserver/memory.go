@@ -0,0 +1,56 @@
+package chartserver
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is a Store keeps everything in memory. It's useful for tests and single replica servers.
+type MemoryStore struct {
+	lock  sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{blobs: map[string][]byte{}}
+}
+
+func (m *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (m *MemoryStore) Put(_ context.Context, key string, data []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.blobs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.blobs, key)
+	return nil
+}
+
+func (m *MemoryStore) List(_ context.Context, prefix string) ([]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var keys []string
+	for key := range m.blobs {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
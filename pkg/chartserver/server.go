@@ -0,0 +1,75 @@
+package chartserver
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+// Handler serves the index files and chart archives in the Store. The urls are
+// /<namespace>/<chartrepo>/index.yaml and /<namespace>/<chartrepo>/<chart>-<version>.tgz, and
+// /_cluster/<clusterchartrepo>/... for ClusterChartRepos, see RepoURL.
+// It can be mounted under a sub path with http.StripPrefix.
+type Handler struct {
+	Store Store
+}
+
+// NewHandler creates a Handler for store
+func NewHandler(store Store) *Handler {
+	return &Handler{Store: store}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 || !validRepoPath(parts[0], parts[1]) {
+		http.NotFound(w, r)
+		return
+	}
+
+	var contentType string
+	switch {
+	case parts[2] == IndexFileName:
+		contentType = "application/x-yaml"
+	case strings.HasSuffix(parts[2], ".tgz"):
+		contentType = "application/gzip"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := h.Store.Get(r.Context(), key)
+	if err == ErrNotFound {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		klog.Errorf("get blob %s error: %s", key, err.Error())
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(data); err != nil {
+		klog.V(4).Infof("write blob %s error: %s", key, err.Error())
+	}
+}
+
+// validRepoPath checks the first two segments are a namespace or ClusterPrefix, and a repo name
+func validRepoPath(namespace, name string) bool {
+	if name == "" || strings.HasPrefix(name, "_") {
+		return false
+	}
+	return namespace == ClusterPrefix || (namespace != "" && !strings.HasPrefix(namespace, "_"))
+}
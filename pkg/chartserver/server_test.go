@@ -0,0 +1,62 @@
+package chartserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	store := NewMemoryStore()
+	store.Put(context.Background(), "default/stable/index.yaml", []byte("apiVersion: v1"))
+	store.Put(context.Background(), "default/stable/nginx-1.0.0.tgz", []byte("archive"))
+	store.Put(context.Background(), "default/stable/nginx-1.0.0.tgz.prov", []byte("prov"))
+	store.Put(context.Background(), RepoPrefix("", "public")+IndexFileName, []byte("apiVersion: v1"))
+	store.Put(context.Background(), "_other/public/index.yaml", []byte("apiVersion: v1"))
+	handler := NewHandler(store)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		code        int
+		contentType string
+		body        string
+	}{
+		{name: "index", method: http.MethodGet, path: "/default/stable/index.yaml", code: http.StatusOK, contentType: "application/x-yaml", body: "apiVersion: v1"},
+		{name: "archive", method: http.MethodGet, path: "/default/stable/nginx-1.0.0.tgz", code: http.StatusOK, contentType: "application/gzip", body: "archive"},
+		{name: "head", method: http.MethodHead, path: "/default/stable/nginx-1.0.0.tgz", code: http.StatusOK, contentType: "application/gzip"},
+		{name: "cleaned path", method: http.MethodGet, path: "/default/other/../stable/index.yaml", code: http.StatusOK, contentType: "application/x-yaml", body: "apiVersion: v1"},
+		{name: "cluster repo", method: http.MethodGet, path: "/_cluster/public/index.yaml", code: http.StatusOK, contentType: "application/x-yaml", body: "apiVersion: v1"},
+		{name: "reserved prefix", method: http.MethodGet, path: "/_other/public/index.yaml", code: http.StatusNotFound},
+		{name: "post", method: http.MethodPost, path: "/default/stable/index.yaml", code: http.StatusMethodNotAllowed},
+		{name: "missing archive", method: http.MethodGet, path: "/default/stable/redis-1.0.0.tgz", code: http.StatusNotFound},
+		{name: "missing repo", method: http.MethodGet, path: "/default/incubator/index.yaml", code: http.StatusNotFound},
+		{name: "other files", method: http.MethodGet, path: "/default/stable/nginx-1.0.0.tgz.prov", code: http.StatusNotFound},
+		{name: "too short", method: http.MethodGet, path: "/default/index.yaml", code: http.StatusNotFound},
+		{name: "too long", method: http.MethodGet, path: "/a/default/stable/index.yaml", code: http.StatusNotFound},
+		{name: "root", method: http.MethodGet, path: "/", code: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.code {
+				t.Fatalf("expect status %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if tt.code == http.StatusMethodNotAllowed && w.Header().Get("Allow") != "GET, HEAD" {
+				t.Errorf("expect Allow header, got %q", w.Header().Get("Allow"))
+			}
+			if tt.code != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("expect content type %s, got %s", tt.contentType, ct)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("expect body %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Package chartserver serves the chart repositories built from VCS sources (see pkg/source), so the
+// generated charts have a url to download from.
+package chartserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/alauda/helm-crds/pkg/source"
+	"github.com/ghodss/yaml"
+)
+
+// IndexFileName is the name of the index file of each repo
+const IndexFileName = "index.yaml"
+
+// ErrNotFound is returned by Store when the blob does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// Store is where the index files and chart archives are stored. Keys are slash separated paths,
+// eg: default/stable/index.yaml
+type Store interface {
+	// Get returns the blob of the key, or ErrNotFound if not exist
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put creates or overwrites the blob of the key
+	Put(ctx context.Context, key string, data []byte) error
+	// Delete deletes the blob of the key, it's not an error if the key not exist
+	Delete(ctx context.Context, key string) error
+	// List lists all the keys with the prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ClusterPrefix is the first path segment of the ClusterChartRepos. It can't be a namespace name, because
+// namespaces are DNS labels, which can not contain "_".
+const ClusterPrefix = "_cluster"
+
+// RepoPrefix is the key prefix of all the blobs of a ChartRepo, or a ClusterChartRepo if namespace is empty
+func RepoPrefix(namespace, name string) string {
+	return repoPath(namespace, name) + "/"
+}
+
+// RepoURL returns the url of the repo served by a server at baseURL. It should be used as
+// source.Options.BaseURL when build the repo, so the chart urls point back to the server.
+// namespace is empty for ClusterChartRepos.
+func RepoURL(baseURL, namespace, name string) string {
+	return fmt.Sprintf("%s/%s", trimSlash(baseURL), repoPath(namespace, name))
+}
+
+// repoPath returns <namespace>/<name> for ChartRepos and _cluster/<name> for ClusterChartRepos
+func repoPath(namespace, name string) string {
+	if namespace == "" {
+		namespace = ClusterPrefix
+	}
+	return path.Join(namespace, name)
+}
+
+// Publish stores the built repo of a ChartRepo, or a ClusterChartRepo if namespace is empty. Archives no longer in the repo are removed.
+func Publish(ctx context.Context, store Store, namespace, name string, result *source.Result) error {
+	prefix := RepoPrefix(namespace, name)
+
+	for file, data := range result.Archives {
+		if err := store.Put(ctx, prefix+file, data); err != nil {
+			return err
+		}
+	}
+
+	index, err := yaml.Marshal(result.Index)
+	if err != nil {
+		return err
+	}
+	if err := store.Put(ctx, prefix+IndexFileName, index); err != nil {
+		return err
+	}
+
+	keys, err := store.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		file := key[len(prefix):]
+		if _, ok := result.Archives[file]; ok || file == IndexFileName {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unpublish removes all the blobs of a ChartRepo, it should be called when the ChartRepo is deleted
+func Unpublish(ctx context.Context, store Store, namespace, name string) error {
+	keys, err := store.List(ctx, RepoPrefix(namespace, name))
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func trimSlash(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '/' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,198 @@
+package chartserver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/source"
+	"helm.sh/helm/pkg/repo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+// testStore runs the same cases against all the Store implementations
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "default/stable/index.yaml"); err != ErrNotFound {
+		t.Errorf("expect ErrNotFound, got %v", err)
+	}
+	for key, data := range map[string]string{
+		"default/stable/index.yaml":       "v1",
+		"default/stable/nginx-1.0.0.tgz":  "nginx",
+		"default/stable2/index.yaml":      "other",
+		"kube-system/stable/index.yaml":   "kube-system",
+		"default/stable/redis-1.0.0.tgz":  "redis",
+		"default/stable/nginx-1.0.0.prov": "prov",
+	} {
+		if err := store.Put(ctx, key, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Put(ctx, "default/stable/index.yaml", []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.Get(ctx, "default/stable/index.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "v2" {
+		t.Errorf("expect v2, got %s", data)
+	}
+
+	keys, err := store.List(ctx, "default/stable/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"default/stable/index.yaml", "default/stable/nginx-1.0.0.prov", "default/stable/nginx-1.0.0.tgz", "default/stable/redis-1.0.0.tgz"}
+	if !reflect.DeepEqual(keys, expect) {
+		t.Errorf("expect keys %v, got %v", expect, keys)
+	}
+
+	if err := store.Delete(ctx, "default/stable/redis-1.0.0.tgz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, "default/stable/redis-1.0.0.tgz"); err != nil {
+		t.Errorf("expect no error deleting a missing key, got %v", err)
+	}
+	if _, err := store.Get(ctx, "default/stable/redis-1.0.0.tgz"); err != ErrNotFound {
+		t.Errorf("expect ErrNotFound after deleted, got %v", err)
+	}
+	if keys, _ := store.List(ctx, "default/stable/"); len(keys) != 3 {
+		t.Errorf("expect 3 keys after deleted, got %v", keys)
+	}
+	if keys, _ := store.List(ctx, "missing/"); len(keys) != 0 {
+		t.Errorf("expect no keys, got %v", keys)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestDiskStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chartserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewDiskStore(dir + "/blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, store)
+
+	// keys can not escape the store dir
+	if err := store.Put(context.Background(), "../../escaped", []byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir + "/escaped"); !os.IsNotExist(err) {
+		t.Errorf("expect no file out of the store dir, got %v", err)
+	}
+}
+
+func TestConfigMapStore(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	testStore(t, NewConfigMapStore(client, "captain-system"))
+
+	list, err := client.CoreV1().ConfigMaps("captain-system").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cm := range list.Items {
+		if len(cm.Name) > 63 || cm.Labels[blobLabel] != "true" {
+			t.Errorf("unexpected ConfigMap %s with labels %v", cm.Name, cm.Labels)
+		}
+	}
+}
+
+func newResult(archives ...string) *source.Result {
+	result := &source.Result{Index: repo.NewIndexFile(), Archives: map[string][]byte{}}
+	for _, file := range archives {
+		result.Archives[file] = []byte(file)
+	}
+	return result
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Put(ctx, "default/stable2/nginx-0.1.0.tgz", []byte("other"))
+
+	if err := Publish(ctx, store, "default", "stable", newResult("nginx-0.1.0.tgz", "nginx-0.2.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Publish(ctx, store, "default", "stable", newResult("nginx-0.2.0.tgz", "nginx-0.3.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := store.List(ctx, "")
+	expect := []string{
+		"default/stable/index.yaml",
+		"default/stable/nginx-0.2.0.tgz",
+		"default/stable/nginx-0.3.0.tgz",
+		"default/stable2/nginx-0.1.0.tgz",
+	}
+	if !reflect.DeepEqual(keys, expect) {
+		t.Errorf("expect keys %v, got %v", expect, keys)
+	}
+	index, err := store.Get(ctx, "default/stable/index.yaml")
+	if err != nil || len(index) == 0 {
+		t.Errorf("expect index published, got %q, %v", index, err)
+	}
+
+	if err := Unpublish(ctx, store, "default", "stable"); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ = store.List(ctx, "")
+	if !reflect.DeepEqual(keys, []string{"default/stable2/nginx-0.1.0.tgz"}) {
+		t.Errorf("expect only the other repo left, got %v", keys)
+	}
+}
+
+func TestRepoURL(t *testing.T) {
+	tests := []struct {
+		baseURL   string
+		namespace string
+		name      string
+		prefix    string
+		url       string
+	}{
+		{baseURL: "http://captain/charts", namespace: "default", name: "stable", prefix: "default/stable/", url: "http://captain/charts/default/stable"},
+		{baseURL: "http://captain/charts/", namespace: "default", name: "stable", prefix: "default/stable/", url: "http://captain/charts/default/stable"},
+		{baseURL: "http://captain/charts", namespace: "", name: "public", prefix: "_cluster/public/", url: "http://captain/charts/_cluster/public"},
+	}
+	for _, tt := range tests {
+		if prefix := RepoPrefix(tt.namespace, tt.name); prefix != tt.prefix {
+			t.Errorf("expect prefix %s, got %s", tt.prefix, prefix)
+		}
+		if url := RepoURL(tt.baseURL, tt.namespace, tt.name); url != tt.url {
+			t.Errorf("expect url %s, got %s", tt.url, url)
+		}
+	}
+}
+
+func TestPublishClusterRepo(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	if err := Publish(ctx, store, "", "public", newResult("nginx-0.1.0.tgz")); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	NewHandler(store).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_cluster/public/nginx-0.1.0.tgz", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "nginx-0.1.0.tgz" {
+		t.Errorf("expect the archive served, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if err := Unpublish(ctx, store, "", "public"); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := store.List(ctx, ""); len(keys) != 0 {
+		t.Errorf("expect no keys, got %v", keys)
+	}
+}
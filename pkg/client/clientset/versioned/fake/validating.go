@@ -0,0 +1,316 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/testing"
+)
+
+// defaulter is implemented by the kinds with a mutating webhook
+type defaulter interface {
+	Default()
+}
+
+// validator is implemented by the kinds with a validating webhook
+type validator interface {
+	ValidateCreate() error
+	ValidateUpdate(old runtime.Object) error
+	ValidateDelete() error
+}
+
+// NewValidatingClientset returns a clientset like NewSimpleClientset, but writes to the app group are
+// handled like a real api server with the captain webhooks installed:
+//  1. Default() and ValidateCreate/ValidateUpdate/ValidateDelete are called for the kinds implementing them,
+//     rejections are returned as admission webhook errors
+//  2. name is generated from generateName if it's empty
+//  3. resourceVersion is set on every write, updates and patches with a stale one fail with a conflict
+//  4. the status is ignored when writing the main resource, and only the status can be changed through the
+//     status subresource
+//  5. generation is set to 1 on create, and increased when anything other than the metadata and the status
+//     is changed
+//  6. objects with finalizers are marked as deleting instead of removed, and removed when the last finalizer
+//     is gone
+//
+// The objects passed in are added as-is, without defaulting or validation.
+func NewValidatingClientset(objects ...runtime.Object) *Clientset {
+	cs := NewSimpleClientset(objects...)
+	a := &admission{tracker: cs.tracker}
+	cs.PrependReactor("*", "*", a.react)
+	return cs
+}
+
+// admission handles the writes to the app group for the validating clientset
+type admission struct {
+	tracker testing.ObjectTracker
+
+	lock            sync.Mutex
+	resourceVersion uint64
+}
+
+func (a *admission) react(action testing.Action) (bool, runtime.Object, error) {
+	if action.GetResource().Group != v1beta1.SchemeGroupVersion.Group {
+		return false, nil, nil
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	switch action := action.(type) {
+	case testing.CreateActionImpl:
+		return a.create(action)
+	case testing.UpdateActionImpl:
+		return a.update(action.GetResource(), action.GetNamespace(), action.GetSubresource(), action.GetObject().DeepCopyObject())
+	case testing.PatchActionImpl:
+		return a.patch(action)
+	case testing.DeleteActionImpl:
+		return a.delete(action)
+	}
+	return false, nil, nil
+}
+
+func (a *admission) create(action testing.CreateActionImpl) (bool, runtime.Object, error) {
+	gvr := action.GetResource()
+	obj := action.GetObject().DeepCopyObject()
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return true, nil, err
+	}
+
+	if m.GetName() == "" && m.GetGenerateName() != "" {
+		m.SetName(m.GetGenerateName() + utilrand.String(5))
+	}
+	if m.GetName() == "" {
+		return true, nil, errors.NewInvalid(kindOf(obj), "", field.ErrorList{
+			field.Required(field.NewPath("metadata", "name"), "name or generateName is required"),
+		})
+	}
+	if action.GetNamespace() != "" {
+		m.SetNamespace(action.GetNamespace())
+	}
+	if status := statusOf(obj); status.IsValid() {
+		status.Set(reflect.Zero(status.Type()))
+	}
+
+	if d, ok := obj.(defaulter); ok {
+		d.Default()
+	}
+	if v, ok := obj.(validator); ok {
+		if err := v.ValidateCreate(); err != nil {
+			return true, nil, denied(gvr, err)
+		}
+	}
+
+	m.SetGeneration(1)
+	m.SetResourceVersion(a.nextResourceVersion())
+	if err := a.tracker.Create(gvr, obj, m.GetNamespace()); err != nil {
+		return true, nil, err
+	}
+	return true, obj, nil
+}
+
+func (a *admission) update(gvr schema.GroupVersionResource, ns, subresource string, obj runtime.Object) (bool, runtime.Object, error) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return true, nil, err
+	}
+	existing, err := a.tracker.Get(gvr, ns, m.GetName())
+	if err != nil {
+		return true, nil, err
+	}
+	old, err := meta.Accessor(existing)
+	if err != nil {
+		return true, nil, err
+	}
+
+	// an empty resourceVersion means an unconditional update
+	if rv := m.GetResourceVersion(); rv != "" && rv != old.GetResourceVersion() {
+		return true, nil, errors.NewConflict(gvr.GroupResource(), m.GetName(),
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+
+	switch subresource {
+	case "status":
+		status := statusOf(obj)
+		if !status.IsValid() {
+			return true, nil, errors.NewNotFound(gvr.GroupResource(), m.GetName())
+		}
+		updated := existing.DeepCopyObject()
+		statusOf(updated).Set(status)
+		obj = updated
+		if m, err = meta.Accessor(obj); err != nil {
+			return true, nil, err
+		}
+	case "":
+		if status := statusOf(obj); status.IsValid() {
+			status.Set(statusOf(existing))
+		}
+		// deletionTimestamp can only be set by delete
+		m.SetDeletionTimestamp(old.GetDeletionTimestamp())
+		m.SetGeneration(old.GetGeneration())
+
+		if d, ok := obj.(defaulter); ok {
+			d.Default()
+		}
+		if v, ok := obj.(validator); ok {
+			if err := v.ValidateUpdate(existing); err != nil {
+				return true, nil, denied(gvr, err)
+			}
+		}
+		if specChanged(existing, obj) {
+			m.SetGeneration(old.GetGeneration() + 1)
+		}
+	default:
+		return true, nil, errors.NewNotFound(gvr.GroupResource(), m.GetName())
+	}
+
+	m.SetResourceVersion(a.nextResourceVersion())
+	if m.GetDeletionTimestamp() != nil && len(m.GetFinalizers()) == 0 {
+		if err := a.tracker.Delete(gvr, ns, m.GetName()); err != nil {
+			return true, nil, err
+		}
+		return true, obj, nil
+	}
+	if err := a.tracker.Update(gvr, obj, ns); err != nil {
+		return true, nil, err
+	}
+	return true, obj, nil
+}
+
+func (a *admission) patch(action testing.PatchActionImpl) (bool, runtime.Object, error) {
+	gvr := action.GetResource()
+	existing, err := a.tracker.Get(gvr, action.GetNamespace(), action.GetName())
+	if err != nil {
+		return true, nil, err
+	}
+	old, err := json.Marshal(existing)
+	if err != nil {
+		return true, nil, err
+	}
+
+	var data []byte
+	switch action.GetPatchType() {
+	case types.JSONPatchType:
+		var patch jsonpatch.Patch
+		if patch, err = jsonpatch.DecodePatch(action.GetPatch()); err == nil {
+			data, err = patch.Apply(old)
+		}
+	case types.MergePatchType:
+		data, err = jsonpatch.MergePatch(old, action.GetPatch())
+	default:
+		// custom resources don't support strategic merge patch, and the fake Apply methods send merge patches
+		return true, nil, errors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "patch", gvr.GroupResource(),
+			action.GetName(), fmt.Sprintf("patch type %s is not supported", action.GetPatchType()), 0, false)
+	}
+	if err != nil {
+		return true, nil, errors.NewBadRequest(err.Error())
+	}
+
+	// decode to a new object, so the fields removed by the patch are not kept
+	obj := reflect.New(reflect.TypeOf(existing).Elem()).Interface().(runtime.Object)
+	if err := json.Unmarshal(data, obj); err != nil {
+		return true, nil, errors.NewBadRequest(err.Error())
+	}
+	return a.update(gvr, action.GetNamespace(), action.GetSubresource(), obj)
+}
+
+func (a *admission) delete(action testing.DeleteActionImpl) (bool, runtime.Object, error) {
+	gvr := action.GetResource()
+	existing, err := a.tracker.Get(gvr, action.GetNamespace(), action.GetName())
+	if err != nil {
+		return true, nil, err
+	}
+	if v, ok := existing.(validator); ok {
+		if err := v.ValidateDelete(); err != nil {
+			return true, nil, denied(gvr, err)
+		}
+	}
+
+	m, err := meta.Accessor(existing)
+	if err != nil {
+		return true, nil, err
+	}
+	if len(m.GetFinalizers()) == 0 {
+		return true, nil, a.tracker.Delete(gvr, action.GetNamespace(), action.GetName())
+	}
+
+	// wait for the finalizers to be removed
+	if m.GetDeletionTimestamp() == nil {
+		obj := existing.DeepCopyObject()
+		m, _ = meta.Accessor(obj)
+		now := metav1.Now()
+		m.SetDeletionTimestamp(&now)
+		m.SetResourceVersion(a.nextResourceVersion())
+		if err := a.tracker.Update(gvr, obj, action.GetNamespace()); err != nil {
+			return true, nil, err
+		}
+	}
+	return true, nil, nil
+}
+
+func (a *admission) nextResourceVersion() string {
+	a.resourceVersion++
+	return strconv.FormatUint(a.resourceVersion, 10)
+}
+
+// statusOf returns the Status field of obj, it's invalid if obj has no status
+func statusOf(obj runtime.Object) reflect.Value {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.Elem().FieldByName("Status")
+}
+
+// specChanged returns true if anything other than the metadata and the status is changed
+func specChanged(old, cur runtime.Object) bool {
+	strip := func(obj runtime.Object) interface{} {
+		v := reflect.ValueOf(obj.DeepCopyObject()).Elem()
+		for _, name := range []string{"TypeMeta", "ObjectMeta", "Status"} {
+			if f := v.FieldByName(name); f.IsValid() {
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+		return v.Interface()
+	}
+	return !equality.Semantic.DeepEqual(strip(old), strip(cur))
+}
+
+func kindOf(obj runtime.Object) schema.GroupKind {
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil || len(gvks) == 0 {
+		return schema.GroupKind{}
+	}
+	return gvks[0].GroupKind()
+}
+
+// denied returns the error the api server returns when a validating webhook rejects the request
+func denied(gvr schema.GroupVersionResource, err error) error {
+	return &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  metav1.StatusReasonForbidden,
+		Message: fmt.Sprintf("admission webhook %q denied the request: %s", webhookName(gvr), err.Error()),
+	}}
+}
+
+// webhookName returns the name of the validating webhook of the resource, eg:
+// validate.helmrequests.app.alauda.io
+func webhookName(gvr schema.GroupVersionResource) string {
+	return fmt.Sprintf("validate.%s.%s", gvr.Resource, gvr.Group)
+}
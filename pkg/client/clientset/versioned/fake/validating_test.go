@@ -0,0 +1,207 @@
+package fake
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newHelmRequest(chart string) *v1beta1.HelmRequest {
+	return &v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "nginx-"},
+		Spec:       v1beta1.HelmRequestSpec{Chart: chart},
+		Status:     v1beta1.HelmRequestStatus{Phase: v1beta1.HelmRequestSynced},
+	}
+}
+
+func TestValidatingCreate(t *testing.T) {
+	hrs := NewValidatingClientset().AppV1beta1().HelmRequests("default")
+	hr, err := hrs.Create(context.Background(), newHelmRequest("stable/nginx"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hr.Name, "nginx-") || hr.Namespace != "default" {
+		t.Errorf("expect name generated in default, got %s/%s", hr.Namespace, hr.Name)
+	}
+	if hr.Generation != 1 || hr.ResourceVersion == "" {
+		t.Errorf("expect generation 1 and a resourceVersion, got %d and %q", hr.Generation, hr.ResourceVersion)
+	}
+	if hr.Status.Phase != "" {
+		t.Errorf("expect status ignored, got phase %s", hr.Status.Phase)
+	}
+	if !hr.HasFinalizer(v1beta1.FinalizerName) || hr.Spec.ReleaseName != hr.Name {
+		t.Errorf("expect defaulted, got finalizers %v and release %q", hr.Finalizers, hr.Spec.ReleaseName)
+	}
+}
+
+func TestValidatingDenied(t *testing.T) {
+	ctx := context.Background()
+	hrs := NewValidatingClientset().AppV1beta1().HelmRequests("default")
+	prefix := `admission webhook "validate.helmrequests.app.alauda.io" denied the request: `
+
+	_, err := hrs.Create(ctx, newHelmRequest("stable/"), metav1.CreateOptions{})
+	if !errors.IsForbidden(err) || !strings.HasPrefix(err.Error(), prefix) {
+		t.Errorf("expect create denied by the webhook, got %v", err)
+	}
+
+	hr, err := hrs.Create(ctx, newHelmRequest("stable/nginx"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hr.Spec.Chart = "stable/redis"
+	_, err = hrs.Update(ctx, hr, metav1.UpdateOptions{})
+	if !errors.IsForbidden(err) || err.Error() != prefix+"chart name cannot be updated after create" {
+		t.Errorf("expect update denied by the webhook, got %v", err)
+	}
+}
+
+func TestValidatingUpdate(t *testing.T) {
+	ctx := context.Background()
+	hrs := NewValidatingClientset().AppV1beta1().HelmRequests("default")
+	created, err := hrs.Create(ctx, newHelmRequest("stable/nginx"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// metadata changes keep the generation
+	hr := created.DeepCopy()
+	hr.Labels = map[string]string{"app": "nginx"}
+	hr.Generation = 10
+	hr, err = hrs.Update(ctx, hr, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Generation != 1 || hr.ResourceVersion == created.ResourceVersion {
+		t.Errorf("expect generation 1 and a new resourceVersion, got %d and %q", hr.Generation, hr.ResourceVersion)
+	}
+
+	hr.Spec.Version = "1.0.0"
+	hr.Status.Phase = v1beta1.HelmRequestFailed
+	hr, err = hrs.Update(ctx, hr, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Generation != 2 || hr.Status.Phase != "" {
+		t.Errorf("expect generation 2 and status ignored, got %d and phase %q", hr.Generation, hr.Status.Phase)
+	}
+
+	// the object read before the updates is stale
+	created.Spec.Version = "2.0.0"
+	if _, err := hrs.Update(ctx, created, metav1.UpdateOptions{}); !errors.IsConflict(err) {
+		t.Errorf("expect conflict with a stale resourceVersion, got %v", err)
+	}
+	stale := `{"metadata":{"resourceVersion":"` + created.ResourceVersion + `"},"spec":{"version":"2.0.0"}}`
+	if _, err := hrs.Patch(ctx, hr.Name, types.MergePatchType, []byte(stale), metav1.PatchOptions{}); !errors.IsConflict(err) {
+		t.Errorf("expect conflict with a stale resourceVersion in the patch, got %v", err)
+	}
+
+	// a patch without the resourceVersion is unconditional
+	hr, err = hrs.Patch(ctx, hr.Name, types.MergePatchType, []byte(`{"spec":{"version":"2.0.0"}}`), metav1.PatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Spec.Version != "2.0.0" || hr.Generation != 3 {
+		t.Errorf("expect version 2.0.0 in generation 3, got %s in %d", hr.Spec.Version, hr.Generation)
+	}
+
+	_, err = hrs.Patch(ctx, hr.Name, types.StrategicMergePatchType, []byte(`{}`), metav1.PatchOptions{})
+	if status, ok := err.(*errors.StatusError); !ok || status.ErrStatus.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expect strategic merge patch unsupported, got %v", err)
+	}
+}
+
+func TestValidatingStatus(t *testing.T) {
+	ctx := context.Background()
+	hrs := NewValidatingClientset().AppV1beta1().HelmRequests("default")
+	hr, err := hrs.Create(ctx, newHelmRequest("stable/nginx"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hr.Spec.Version = "1.0.0"
+	hr.Status.Phase = v1beta1.HelmRequestSynced
+	hr, err = hrs.UpdateStatus(ctx, hr, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Status.Phase != v1beta1.HelmRequestSynced {
+		t.Errorf("expect phase %s, got %s", v1beta1.HelmRequestSynced, hr.Status.Phase)
+	}
+	if hr.Spec.Version != "" || hr.Generation != 1 {
+		t.Errorf("expect spec ignored in generation 1, got version %q in %d", hr.Spec.Version, hr.Generation)
+	}
+
+	patch := `{"metadata":{"resourceVersion":"` + hr.ResourceVersion + `"},"status":{"phase":"Failed"}}`
+	hr, err = hrs.Patch(ctx, hr.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Status.Phase != v1beta1.HelmRequestFailed {
+		t.Errorf("expect phase %s, got %s", v1beta1.HelmRequestFailed, hr.Status.Phase)
+	}
+	if _, err := hrs.Patch(ctx, hr.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status"); !errors.IsConflict(err) {
+		t.Errorf("expect conflict with a stale resourceVersion, got %v", err)
+	}
+}
+
+func TestValidatingDelete(t *testing.T) {
+	ctx := context.Background()
+	client := NewValidatingClientset()
+	hrs := client.AppV1beta1().HelmRequests("default")
+	hr, err := hrs.Create(ctx, newHelmRequest("stable/nginx"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hrs.Delete(ctx, hr.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	hr, err = hrs.Get(ctx, hr.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expect kept until the finalizer is removed, got %v", err)
+	}
+	if hr.DeletionTimestamp == nil {
+		t.Fatal("expect deletionTimestamp set")
+	}
+
+	// deletionTimestamp can't be removed by an update
+	hr.DeletionTimestamp = nil
+	hr.Labels = map[string]string{"app": "nginx"}
+	hr, err = hrs.Update(ctx, hr, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.DeletionTimestamp == nil {
+		t.Error("expect deletionTimestamp kept")
+	}
+
+	hr.RemoveFinalizer(v1beta1.FinalizerName)
+	if _, err := hrs.Update(ctx, hr, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hrs.Get(ctx, hr.Name, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expect removed with the last finalizer, got %v", err)
+	}
+
+	// ChartRepos have no finalizers by default
+	repos := client.AppV1beta1().ChartRepos("default")
+	repo, err := repos.Create(ctx, &v1beta1.ChartRepo{
+		ObjectMeta: metav1.ObjectMeta{Name: "stable"},
+		Spec:       v1beta1.ChartRepoSpec{URL: "https://charts.example.com"},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Delete(ctx, repo.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Get(ctx, repo.Name, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expect removed without finalizers, got %v", err)
+	}
+}
@@ -19,6 +19,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"time"
 
 	v1alpha1 "github.com/alauda/helm-crds/pkg/apis/app/v1alpha1"
@@ -37,15 +38,15 @@ type ChartReposGetter interface {
 
 // ChartRepoInterface has methods to work with ChartRepo resources.
 type ChartRepoInterface interface {
-	Create(*v1alpha1.ChartRepo) (*v1alpha1.ChartRepo, error)
-	Update(*v1alpha1.ChartRepo) (*v1alpha1.ChartRepo, error)
-	UpdateStatus(*v1alpha1.ChartRepo) (*v1alpha1.ChartRepo, error)
-	Delete(name string, options *v1.DeleteOptions) error
-	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
-	Get(name string, options v1.GetOptions) (*v1alpha1.ChartRepo, error)
-	List(opts v1.ListOptions) (*v1alpha1.ChartRepoList, error)
-	Watch(opts v1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ChartRepo, err error)
+	Create(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.CreateOptions) (*v1alpha1.ChartRepo, error)
+	Update(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.UpdateOptions) (*v1alpha1.ChartRepo, error)
+	UpdateStatus(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.UpdateOptions) (*v1alpha1.ChartRepo, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ChartRepo, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ChartRepoList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ChartRepo, err error)
 	ChartRepoExpansion
 }
 
@@ -64,20 +65,21 @@ func newChartRepos(c *AppV1alpha1Client, namespace string) *chartRepos {
 }
 
 // Get takes name of the chartRepo, and returns the corresponding chartRepo object, and an error if there is any.
-func (c *chartRepos) Get(name string, options v1.GetOptions) (result *v1alpha1.ChartRepo, err error) {
+func (c *chartRepos) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ChartRepo, err error) {
 	result = &v1alpha1.ChartRepo{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("chartrepos").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of ChartRepos that match those selectors.
-func (c *chartRepos) List(opts v1.ListOptions) (result *v1alpha1.ChartRepoList, err error) {
+func (c *chartRepos) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ChartRepoList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -88,13 +90,14 @@ func (c *chartRepos) List(opts v1.ListOptions) (result *v1alpha1.ChartRepoList,
 		Resource("chartrepos").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested chartRepos.
-func (c *chartRepos) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *chartRepos) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -105,29 +108,34 @@ func (c *chartRepos) Watch(opts v1.ListOptions) (watch.Interface, error) {
 		Resource("chartrepos").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Watch()
 }
 
 // Create takes the representation of a chartRepo and creates it.  Returns the server's representation of the chartRepo, and an error, if there is any.
-func (c *chartRepos) Create(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1.ChartRepo, err error) {
+func (c *chartRepos) Create(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.CreateOptions) (result *v1alpha1.ChartRepo, err error) {
 	result = &v1alpha1.ChartRepo{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("chartrepos").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(chartRepo).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Update takes the representation of a chartRepo and updates it. Returns the server's representation of the chartRepo, and an error, if there is any.
-func (c *chartRepos) Update(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1.ChartRepo, err error) {
+func (c *chartRepos) Update(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.UpdateOptions) (result *v1alpha1.ChartRepo, err error) {
 	result = &v1alpha1.ChartRepo{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("chartrepos").
 		Name(chartRepo.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(chartRepo).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
@@ -135,56 +143,61 @@ func (c *chartRepos) Update(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1.Cha
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-
-func (c *chartRepos) UpdateStatus(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1.ChartRepo, err error) {
+func (c *chartRepos) UpdateStatus(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.UpdateOptions) (result *v1alpha1.ChartRepo, err error) {
 	result = &v1alpha1.ChartRepo{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("chartrepos").
 		Name(chartRepo.Name).
 		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(chartRepo).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Delete takes name of the chartRepo and deletes it. Returns an error if one occurs.
-func (c *chartRepos) Delete(name string, options *v1.DeleteOptions) error {
+func (c *chartRepos) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("chartrepos").
 		Name(name).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *chartRepos) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+func (c *chartRepos) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("chartrepos").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // Patch applies the patch and returns the patched chartRepo.
-func (c *chartRepos) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ChartRepo, err error) {
+func (c *chartRepos) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ChartRepo, err error) {
 	result = &v1alpha1.ChartRepo{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("chartrepos").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
@@ -19,6 +19,8 @@ limitations under the License.
 package fake
 
 import (
+	"context"
+
 	v1alpha1 "github.com/alauda/helm-crds/pkg/apis/app/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -39,7 +41,7 @@ var chartsResource = schema.GroupVersionResource{Group: "app.alauda.io", Version
 var chartsKind = schema.GroupVersionKind{Group: "app.alauda.io", Version: "v1alpha1", Kind: "Chart"}
 
 // Get takes name of the chart, and returns the corresponding chart object, and an error if there is any.
-func (c *FakeCharts) Get(name string, options v1.GetOptions) (result *v1alpha1.Chart, err error) {
+func (c *FakeCharts) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(chartsResource, c.ns, name), &v1alpha1.Chart{})
 
@@ -50,7 +52,7 @@ func (c *FakeCharts) Get(name string, options v1.GetOptions) (result *v1alpha1.C
 }
 
 // List takes label and field selectors, and returns the list of Charts that match those selectors.
-func (c *FakeCharts) List(opts v1.ListOptions) (result *v1alpha1.ChartList, err error) {
+func (c *FakeCharts) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ChartList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(chartsResource, chartsKind, c.ns, opts), &v1alpha1.ChartList{})
 
@@ -72,14 +74,14 @@ func (c *FakeCharts) List(opts v1.ListOptions) (result *v1alpha1.ChartList, err
 }
 
 // Watch returns a watch.Interface that watches the requested charts.
-func (c *FakeCharts) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeCharts) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(chartsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a chart and creates it.  Returns the server's representation of the chart, and an error, if there is any.
-func (c *FakeCharts) Create(chart *v1alpha1.Chart) (result *v1alpha1.Chart, err error) {
+func (c *FakeCharts) Create(ctx context.Context, chart *v1alpha1.Chart, opts v1.CreateOptions) (result *v1alpha1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(chartsResource, c.ns, chart), &v1alpha1.Chart{})
 
@@ -90,7 +92,7 @@ func (c *FakeCharts) Create(chart *v1alpha1.Chart) (result *v1alpha1.Chart, err
 }
 
 // Update takes the representation of a chart and updates it. Returns the server's representation of the chart, and an error, if there is any.
-func (c *FakeCharts) Update(chart *v1alpha1.Chart) (result *v1alpha1.Chart, err error) {
+func (c *FakeCharts) Update(ctx context.Context, chart *v1alpha1.Chart, opts v1.UpdateOptions) (result *v1alpha1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(chartsResource, c.ns, chart), &v1alpha1.Chart{})
 
@@ -101,7 +103,7 @@ func (c *FakeCharts) Update(chart *v1alpha1.Chart) (result *v1alpha1.Chart, err
 }
 
 // Delete takes name of the chart and deletes it. Returns an error if one occurs.
-func (c *FakeCharts) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeCharts) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(chartsResource, c.ns, name), &v1alpha1.Chart{})
 
@@ -109,15 +111,15 @@ func (c *FakeCharts) Delete(name string, options *v1.DeleteOptions) error {
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeCharts) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(chartsResource, c.ns, listOptions)
+func (c *FakeCharts) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(chartsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &v1alpha1.ChartList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched chart.
-func (c *FakeCharts) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.Chart, err error) {
+func (c *FakeCharts) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(chartsResource, c.ns, name, pt, data, subresources...), &v1alpha1.Chart{})
 
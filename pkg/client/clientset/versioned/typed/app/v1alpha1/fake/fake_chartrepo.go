@@ -19,6 +19,8 @@ limitations under the License.
 package fake
 
 import (
+	"context"
+
 	v1alpha1 "github.com/alauda/helm-crds/pkg/apis/app/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -39,7 +41,7 @@ var chartreposResource = schema.GroupVersionResource{Group: "app.alauda.io", Ver
 var chartreposKind = schema.GroupVersionKind{Group: "app.alauda.io", Version: "v1alpha1", Kind: "ChartRepo"}
 
 // Get takes name of the chartRepo, and returns the corresponding chartRepo object, and an error if there is any.
-func (c *FakeChartRepos) Get(name string, options v1.GetOptions) (result *v1alpha1.ChartRepo, err error) {
+func (c *FakeChartRepos) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ChartRepo, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(chartreposResource, c.ns, name), &v1alpha1.ChartRepo{})
 
@@ -50,7 +52,7 @@ func (c *FakeChartRepos) Get(name string, options v1.GetOptions) (result *v1alph
 }
 
 // List takes label and field selectors, and returns the list of ChartRepos that match those selectors.
-func (c *FakeChartRepos) List(opts v1.ListOptions) (result *v1alpha1.ChartRepoList, err error) {
+func (c *FakeChartRepos) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ChartRepoList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(chartreposResource, chartreposKind, c.ns, opts), &v1alpha1.ChartRepoList{})
 
@@ -72,14 +74,14 @@ func (c *FakeChartRepos) List(opts v1.ListOptions) (result *v1alpha1.ChartRepoLi
 }
 
 // Watch returns a watch.Interface that watches the requested chartRepos.
-func (c *FakeChartRepos) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeChartRepos) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(chartreposResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a chartRepo and creates it.  Returns the server's representation of the chartRepo, and an error, if there is any.
-func (c *FakeChartRepos) Create(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1.ChartRepo, err error) {
+func (c *FakeChartRepos) Create(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.CreateOptions) (result *v1alpha1.ChartRepo, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(chartreposResource, c.ns, chartRepo), &v1alpha1.ChartRepo{})
 
@@ -90,7 +92,7 @@ func (c *FakeChartRepos) Create(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1
 }
 
 // Update takes the representation of a chartRepo and updates it. Returns the server's representation of the chartRepo, and an error, if there is any.
-func (c *FakeChartRepos) Update(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1.ChartRepo, err error) {
+func (c *FakeChartRepos) Update(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.UpdateOptions) (result *v1alpha1.ChartRepo, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(chartreposResource, c.ns, chartRepo), &v1alpha1.ChartRepo{})
 
@@ -102,7 +104,7 @@ func (c *FakeChartRepos) Update(chartRepo *v1alpha1.ChartRepo) (result *v1alpha1
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-func (c *FakeChartRepos) UpdateStatus(chartRepo *v1alpha1.ChartRepo) (*v1alpha1.ChartRepo, error) {
+func (c *FakeChartRepos) UpdateStatus(ctx context.Context, chartRepo *v1alpha1.ChartRepo, opts v1.UpdateOptions) (*v1alpha1.ChartRepo, error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateSubresourceAction(chartreposResource, "status", c.ns, chartRepo), &v1alpha1.ChartRepo{})
 
@@ -113,7 +115,7 @@ func (c *FakeChartRepos) UpdateStatus(chartRepo *v1alpha1.ChartRepo) (*v1alpha1.
 }
 
 // Delete takes name of the chartRepo and deletes it. Returns an error if one occurs.
-func (c *FakeChartRepos) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeChartRepos) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(chartreposResource, c.ns, name), &v1alpha1.ChartRepo{})
 
@@ -121,15 +123,15 @@ func (c *FakeChartRepos) Delete(name string, options *v1.DeleteOptions) error {
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeChartRepos) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(chartreposResource, c.ns, listOptions)
+func (c *FakeChartRepos) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(chartreposResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &v1alpha1.ChartRepoList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched chartRepo.
-func (c *FakeChartRepos) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ChartRepo, err error) {
+func (c *FakeChartRepos) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ChartRepo, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(chartreposResource, c.ns, name, pt, data, subresources...), &v1alpha1.ChartRepo{})
 
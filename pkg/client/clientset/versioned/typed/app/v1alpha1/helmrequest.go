@@ -19,6 +19,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"time"
 
 	v1alpha1 "github.com/alauda/helm-crds/pkg/apis/app/v1alpha1"
@@ -37,15 +38,15 @@ type HelmRequestsGetter interface {
 
 // HelmRequestInterface has methods to work with HelmRequest resources.
 type HelmRequestInterface interface {
-	Create(*v1alpha1.HelmRequest) (*v1alpha1.HelmRequest, error)
-	Update(*v1alpha1.HelmRequest) (*v1alpha1.HelmRequest, error)
-	UpdateStatus(*v1alpha1.HelmRequest) (*v1alpha1.HelmRequest, error)
-	Delete(name string, options *v1.DeleteOptions) error
-	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
-	Get(name string, options v1.GetOptions) (*v1alpha1.HelmRequest, error)
-	List(opts v1.ListOptions) (*v1alpha1.HelmRequestList, error)
-	Watch(opts v1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.HelmRequest, err error)
+	Create(ctx context.Context, helmRequest *v1alpha1.HelmRequest, opts v1.CreateOptions) (*v1alpha1.HelmRequest, error)
+	Update(ctx context.Context, helmRequest *v1alpha1.HelmRequest, opts v1.UpdateOptions) (*v1alpha1.HelmRequest, error)
+	UpdateStatus(ctx context.Context, helmRequest *v1alpha1.HelmRequest, opts v1.UpdateOptions) (*v1alpha1.HelmRequest, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.HelmRequest, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.HelmRequestList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.HelmRequest, err error)
 	HelmRequestExpansion
 }
 
@@ -64,20 +65,21 @@ func newHelmRequests(c *AppV1alpha1Client, namespace string) *helmRequests {
 }
 
 // Get takes name of the helmRequest, and returns the corresponding helmRequest object, and an error if there is any.
-func (c *helmRequests) Get(name string, options v1.GetOptions) (result *v1alpha1.HelmRequest, err error) {
+func (c *helmRequests) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.HelmRequest, err error) {
 	result = &v1alpha1.HelmRequest{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("helmrequests").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of HelmRequests that match those selectors.
-func (c *helmRequests) List(opts v1.ListOptions) (result *v1alpha1.HelmRequestList, err error) {
+func (c *helmRequests) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.HelmRequestList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -88,13 +90,14 @@ func (c *helmRequests) List(opts v1.ListOptions) (result *v1alpha1.HelmRequestLi
 		Resource("helmrequests").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested helmRequests.
-func (c *helmRequests) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *helmRequests) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -105,29 +108,34 @@ func (c *helmRequests) Watch(opts v1.ListOptions) (watch.Interface, error) {
 		Resource("helmrequests").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Watch()
 }
 
 // Create takes the representation of a helmRequest and creates it.  Returns the server's representation of the helmRequest, and an error, if there is any.
-func (c *helmRequests) Create(helmRequest *v1alpha1.HelmRequest) (result *v1alpha1.HelmRequest, err error) {
+func (c *helmRequests) Create(ctx context.Context, helmRequest *v1alpha1.HelmRequest, opts v1.CreateOptions) (result *v1alpha1.HelmRequest, err error) {
 	result = &v1alpha1.HelmRequest{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("helmrequests").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(helmRequest).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Update takes the representation of a helmRequest and updates it. Returns the server's representation of the helmRequest, and an error, if there is any.
-func (c *helmRequests) Update(helmRequest *v1alpha1.HelmRequest) (result *v1alpha1.HelmRequest, err error) {
+func (c *helmRequests) Update(ctx context.Context, helmRequest *v1alpha1.HelmRequest, opts v1.UpdateOptions) (result *v1alpha1.HelmRequest, err error) {
 	result = &v1alpha1.HelmRequest{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("helmrequests").
 		Name(helmRequest.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(helmRequest).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
@@ -135,56 +143,61 @@ func (c *helmRequests) Update(helmRequest *v1alpha1.HelmRequest) (result *v1alph
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-
-func (c *helmRequests) UpdateStatus(helmRequest *v1alpha1.HelmRequest) (result *v1alpha1.HelmRequest, err error) {
+func (c *helmRequests) UpdateStatus(ctx context.Context, helmRequest *v1alpha1.HelmRequest, opts v1.UpdateOptions) (result *v1alpha1.HelmRequest, err error) {
 	result = &v1alpha1.HelmRequest{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("helmrequests").
 		Name(helmRequest.Name).
 		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(helmRequest).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Delete takes name of the helmRequest and deletes it. Returns an error if one occurs.
-func (c *helmRequests) Delete(name string, options *v1.DeleteOptions) error {
+func (c *helmRequests) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("helmrequests").
 		Name(name).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *helmRequests) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+func (c *helmRequests) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("helmrequests").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // Patch applies the patch and returns the patched helmRequest.
-func (c *helmRequests) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.HelmRequest, err error) {
+func (c *helmRequests) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.HelmRequest, err error) {
 	result = &v1alpha1.HelmRequest{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("helmrequests").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
@@ -28,6 +28,8 @@ type AppV1beta1Interface interface {
 	RESTClient() rest.Interface
 	ChartsGetter
 	ChartReposGetter
+	ClusterChartsGetter
+	ClusterChartReposGetter
 	HelmRequestsGetter
 	ReleasesGetter
 }
@@ -45,6 +47,14 @@ func (c *AppV1beta1Client) ChartRepos(namespace string) ChartRepoInterface {
 	return newChartRepos(c, namespace)
 }
 
+func (c *AppV1beta1Client) ClusterCharts() ClusterChartInterface {
+	return newClusterCharts(c)
+}
+
+func (c *AppV1beta1Client) ClusterChartRepos() ClusterChartRepoInterface {
+	return newClusterChartRepos(c)
+}
+
 func (c *AppV1beta1Client) HelmRequests(namespace string) HelmRequestInterface {
 	return newHelmRequests(c, namespace)
 }
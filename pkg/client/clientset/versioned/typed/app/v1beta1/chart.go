@@ -19,6 +19,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"time"
 
 	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
@@ -37,14 +38,15 @@ type ChartsGetter interface {
 
 // ChartInterface has methods to work with Chart resources.
 type ChartInterface interface {
-	Create(*v1beta1.Chart) (*v1beta1.Chart, error)
-	Update(*v1beta1.Chart) (*v1beta1.Chart, error)
-	Delete(name string, options *v1.DeleteOptions) error
-	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
-	Get(name string, options v1.GetOptions) (*v1beta1.Chart, error)
-	List(opts v1.ListOptions) (*v1beta1.ChartList, error)
-	Watch(opts v1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.Chart, err error)
+	Create(ctx context.Context, chart *v1beta1.Chart, opts v1.CreateOptions) (*v1beta1.Chart, error)
+	Update(ctx context.Context, chart *v1beta1.Chart, opts v1.UpdateOptions) (*v1beta1.Chart, error)
+	UpdateStatus(ctx context.Context, chart *v1beta1.Chart, opts v1.UpdateOptions) (*v1beta1.Chart, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.Chart, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.ChartList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Chart, err error)
 	ChartExpansion
 }
 
@@ -63,20 +65,21 @@ func newCharts(c *AppV1beta1Client, namespace string) *charts {
 }
 
 // Get takes name of the chart, and returns the corresponding chart object, and an error if there is any.
-func (c *charts) Get(name string, options v1.GetOptions) (result *v1beta1.Chart, err error) {
+func (c *charts) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.Chart, err error) {
 	result = &v1beta1.Chart{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("charts").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of Charts that match those selectors.
-func (c *charts) List(opts v1.ListOptions) (result *v1beta1.ChartList, err error) {
+func (c *charts) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.ChartList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -87,13 +90,14 @@ func (c *charts) List(opts v1.ListOptions) (result *v1beta1.ChartList, err error
 		Resource("charts").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested charts.
-func (c *charts) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *charts) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -104,70 +108,96 @@ func (c *charts) Watch(opts v1.ListOptions) (watch.Interface, error) {
 		Resource("charts").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Watch()
 }
 
 // Create takes the representation of a chart and creates it.  Returns the server's representation of the chart, and an error, if there is any.
-func (c *charts) Create(chart *v1beta1.Chart) (result *v1beta1.Chart, err error) {
+func (c *charts) Create(ctx context.Context, chart *v1beta1.Chart, opts v1.CreateOptions) (result *v1beta1.Chart, err error) {
 	result = &v1beta1.Chart{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("charts").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(chart).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Update takes the representation of a chart and updates it. Returns the server's representation of the chart, and an error, if there is any.
-func (c *charts) Update(chart *v1beta1.Chart) (result *v1beta1.Chart, err error) {
+func (c *charts) Update(ctx context.Context, chart *v1beta1.Chart, opts v1.UpdateOptions) (result *v1beta1.Chart, err error) {
 	result = &v1beta1.Chart{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("charts").
 		Name(chart.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(chart).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *charts) UpdateStatus(ctx context.Context, chart *v1beta1.Chart, opts v1.UpdateOptions) (result *v1beta1.Chart, err error) {
+	result = &v1beta1.Chart{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("charts").
+		Name(chart.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(chart).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Delete takes name of the chart and deletes it. Returns an error if one occurs.
-func (c *charts) Delete(name string, options *v1.DeleteOptions) error {
+func (c *charts) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("charts").
 		Name(name).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *charts) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+func (c *charts) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("charts").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // Patch applies the patch and returns the patched chart.
-func (c *charts) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.Chart, err error) {
+func (c *charts) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Chart, err error) {
 	result = &v1beta1.Chart{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("charts").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
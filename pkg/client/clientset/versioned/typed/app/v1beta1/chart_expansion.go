@@ -0,0 +1,45 @@
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	scheme "github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// ChartExpansion has the methods of ChartInterface not generated by client-gen
+type ChartExpansion interface {
+	Apply(ctx context.Context, chart *appv1beta1.ChartApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.Chart, err error)
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied chart.
+// opts.FieldManager is required by server-side apply.
+func (c *charts) Apply(ctx context.Context, chart *appv1beta1.ChartApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.Chart, err error) {
+	if chart == nil {
+		return nil, fmt.Errorf("chart provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(chart)
+	if err != nil {
+		return nil, err
+	}
+	if chart.ObjectMetaApplyConfiguration == nil || chart.Name == nil {
+		return nil, fmt.Errorf("chart.Name must be provided to Apply")
+	}
+	name := chart.Name
+	result = &v1beta1.Chart{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("charts").
+		Name(*name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
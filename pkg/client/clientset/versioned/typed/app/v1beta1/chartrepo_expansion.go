@@ -0,0 +1,45 @@
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	scheme "github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// ChartRepoExpansion has the methods of ChartRepoInterface not generated by client-gen
+type ChartRepoExpansion interface {
+	Apply(ctx context.Context, chartRepo *appv1beta1.ChartRepoApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ChartRepo, err error)
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied chartRepo.
+// opts.FieldManager is required by server-side apply.
+func (c *chartRepos) Apply(ctx context.Context, chartRepo *appv1beta1.ChartRepoApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ChartRepo, err error) {
+	if chartRepo == nil {
+		return nil, fmt.Errorf("chartRepo provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(chartRepo)
+	if err != nil {
+		return nil, err
+	}
+	if chartRepo.ObjectMetaApplyConfiguration == nil || chartRepo.Name == nil {
+		return nil, fmt.Errorf("chartRepo.Name must be provided to Apply")
+	}
+	name := chartRepo.Name
+	result = &v1beta1.ChartRepo{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("chartrepos").
+		Name(*name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,44 @@
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	scheme "github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// ClusterChartExpansion has the methods of ClusterChartInterface not generated by client-gen
+type ClusterChartExpansion interface {
+	Apply(ctx context.Context, clusterChart *appv1beta1.ClusterChartApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ClusterChart, err error)
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterChart.
+// opts.FieldManager is required by server-side apply.
+func (c *clusterCharts) Apply(ctx context.Context, clusterChart *appv1beta1.ClusterChartApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ClusterChart, err error) {
+	if clusterChart == nil {
+		return nil, fmt.Errorf("clusterChart provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterChart)
+	if err != nil {
+		return nil, err
+	}
+	if clusterChart.ObjectMetaApplyConfiguration == nil || clusterChart.Name == nil {
+		return nil, fmt.Errorf("clusterChart.Name must be provided to Apply")
+	}
+	name := clusterChart.Name
+	result = &v1beta1.ClusterChart{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Resource("clustercharts").
+		Name(*name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,44 @@
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	scheme "github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// ClusterChartRepoExpansion has the methods of ClusterChartRepoInterface not generated by client-gen
+type ClusterChartRepoExpansion interface {
+	Apply(ctx context.Context, clusterChartRepo *appv1beta1.ClusterChartRepoApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ClusterChartRepo, err error)
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterChartRepo.
+// opts.FieldManager is required by server-side apply.
+func (c *clusterChartRepos) Apply(ctx context.Context, clusterChartRepo *appv1beta1.ClusterChartRepoApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ClusterChartRepo, err error) {
+	if clusterChartRepo == nil {
+		return nil, fmt.Errorf("clusterChartRepo provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterChartRepo)
+	if err != nil {
+		return nil, err
+	}
+	if clusterChartRepo.ObjectMetaApplyConfiguration == nil || clusterChartRepo.Name == nil {
+		return nil, fmt.Errorf("clusterChartRepo.Name must be provided to Apply")
+	}
+	name := clusterChartRepo.Name
+	result = &v1beta1.ClusterChartRepo{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Resource("clusterchartrepos").
+		Name(*name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
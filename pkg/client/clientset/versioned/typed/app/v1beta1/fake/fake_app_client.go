@@ -36,6 +36,14 @@ func (c *FakeAppV1beta1) ChartRepos(namespace string) v1beta1.ChartRepoInterface
 	return &FakeChartRepos{c, namespace}
 }
 
+func (c *FakeAppV1beta1) ClusterCharts() v1beta1.ClusterChartInterface {
+	return &FakeClusterCharts{c}
+}
+
+func (c *FakeAppV1beta1) ClusterChartRepos() v1beta1.ClusterChartRepoInterface {
+	return &FakeClusterChartRepos{c}
+}
+
 func (c *FakeAppV1beta1) HelmRequests(namespace string) v1beta1.HelmRequestInterface {
 	return &FakeHelmRequests{c, namespace}
 }
@@ -0,0 +1,36 @@
+package fake
+
+import (
+	json "encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	testing "k8s.io/client-go/testing"
+)
+
+// invokesApply invokes the apply configuration in data as a merge patch, and a create if the object is
+// not found, since the object tracker of client-go doesn't support apply patches. So the fields are
+// merged as a merge patch does, lists are replaced as a whole, and field managers and conflicts are not
+// checked. Namespace is empty for cluster scoped resources.
+func invokesApply(fake *testing.Fake, resource schema.GroupVersionResource, namespace, name string, data []byte, empty runtime.Object) (runtime.Object, error) {
+	var patch testing.Action = testing.NewPatchAction(resource, namespace, name, types.MergePatchType, data)
+	if namespace == "" {
+		patch = testing.NewRootPatchAction(resource, name, types.MergePatchType, data)
+	}
+	obj, err := fake.Invokes(patch, empty.DeepCopyObject())
+	if !errors.IsNotFound(err) {
+		return obj, err
+	}
+
+	created := empty.DeepCopyObject()
+	if err := json.Unmarshal(data, created); err != nil {
+		return nil, err
+	}
+	var create testing.Action = testing.NewCreateAction(resource, namespace, created)
+	if namespace == "" {
+		create = testing.NewRootCreateAction(resource, created)
+	}
+	return fake.Invokes(create, empty.DeepCopyObject())
+}
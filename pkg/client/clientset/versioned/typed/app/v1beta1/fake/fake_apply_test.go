@@ -0,0 +1,76 @@
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApply(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default", Labels: map[string]string{"a": "1"}},
+		Spec:       v1beta1.HelmRequestSpec{Chart: "stable/nginx", Version: "1.0.0", ClusterName: "global"},
+	})
+	helmRequests := client.AppV1beta1().HelmRequests("default")
+	ctx := context.Background()
+	opts := metav1.PatchOptions{FieldManager: "test"}
+
+	// create
+	created, err := helmRequests.Apply(ctx, appv1beta1.HelmRequest("new", "default").
+		WithSpec(appv1beta1.HelmRequestSpec().WithChart("stable/redis")), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "new" || created.Spec.Chart != "stable/redis" {
+		t.Errorf("unexpected created HelmRequest: %+v", created)
+	}
+
+	// merge the fields set into the existing one
+	applied, err := helmRequests.Apply(ctx, appv1beta1.HelmRequest("existing", "default").
+		WithLabels(map[string]string{"b": "2"}).
+		WithSpec(appv1beta1.HelmRequestSpec().WithVersion("1.1.0")), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := helmRequests.Get(ctx, "existing", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hr := range []*v1beta1.HelmRequest{applied, got} {
+		if hr.Spec.Version != "1.1.0" || hr.Spec.Chart != "stable/nginx" || hr.Spec.ClusterName != "global" {
+			t.Errorf("unexpected spec: %+v", hr.Spec)
+		}
+		if hr.Labels["a"] != "1" || hr.Labels["b"] != "2" {
+			t.Errorf("unexpected labels: %v", hr.Labels)
+		}
+	}
+
+	if _, err := helmRequests.Apply(ctx, &appv1beta1.HelmRequestApplyConfiguration{}, opts); err == nil {
+		t.Error("expect error without name")
+	}
+}
+
+func TestApplyClusterScoped(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	repos := client.AppV1beta1().ClusterChartRepos()
+	ctx := context.Background()
+	opts := metav1.PatchOptions{FieldManager: "test"}
+
+	for _, url := range []string{"https://charts.example.com", "https://mirror.example.com"} {
+		if _, err := repos.Apply(ctx, appv1beta1.ClusterChartRepo("stable").
+			WithSpec(appv1beta1.ChartRepoSpec().WithURL(url).WithType("Chart")), opts); err != nil {
+			t.Fatal(err)
+		}
+		got, err := repos.Get(ctx, "stable", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Spec.URL != url || got.Spec.Type != "Chart" {
+			t.Errorf("unexpected spec: %+v", got.Spec)
+		}
+	}
+}
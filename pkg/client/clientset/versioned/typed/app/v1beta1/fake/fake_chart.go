@@ -19,6 +19,8 @@ limitations under the License.
 package fake
 
 import (
+	"context"
+
 	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -39,7 +41,7 @@ var chartsResource = schema.GroupVersionResource{Group: "app.alauda.io", Version
 var chartsKind = schema.GroupVersionKind{Group: "app.alauda.io", Version: "v1beta1", Kind: "Chart"}
 
 // Get takes name of the chart, and returns the corresponding chart object, and an error if there is any.
-func (c *FakeCharts) Get(name string, options v1.GetOptions) (result *v1beta1.Chart, err error) {
+func (c *FakeCharts) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(chartsResource, c.ns, name), &v1beta1.Chart{})
 
@@ -50,7 +52,7 @@ func (c *FakeCharts) Get(name string, options v1.GetOptions) (result *v1beta1.Ch
 }
 
 // List takes label and field selectors, and returns the list of Charts that match those selectors.
-func (c *FakeCharts) List(opts v1.ListOptions) (result *v1beta1.ChartList, err error) {
+func (c *FakeCharts) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.ChartList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(chartsResource, chartsKind, c.ns, opts), &v1beta1.ChartList{})
 
@@ -72,14 +74,14 @@ func (c *FakeCharts) List(opts v1.ListOptions) (result *v1beta1.ChartList, err e
 }
 
 // Watch returns a watch.Interface that watches the requested charts.
-func (c *FakeCharts) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeCharts) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(chartsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a chart and creates it.  Returns the server's representation of the chart, and an error, if there is any.
-func (c *FakeCharts) Create(chart *v1beta1.Chart) (result *v1beta1.Chart, err error) {
+func (c *FakeCharts) Create(ctx context.Context, chart *v1beta1.Chart, opts v1.CreateOptions) (result *v1beta1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(chartsResource, c.ns, chart), &v1beta1.Chart{})
 
@@ -90,7 +92,7 @@ func (c *FakeCharts) Create(chart *v1beta1.Chart) (result *v1beta1.Chart, err er
 }
 
 // Update takes the representation of a chart and updates it. Returns the server's representation of the chart, and an error, if there is any.
-func (c *FakeCharts) Update(chart *v1beta1.Chart) (result *v1beta1.Chart, err error) {
+func (c *FakeCharts) Update(ctx context.Context, chart *v1beta1.Chart, opts v1.UpdateOptions) (result *v1beta1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(chartsResource, c.ns, chart), &v1beta1.Chart{})
 
@@ -100,8 +102,20 @@ func (c *FakeCharts) Update(chart *v1beta1.Chart) (result *v1beta1.Chart, err er
 	return obj.(*v1beta1.Chart), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCharts) UpdateStatus(ctx context.Context, chart *v1beta1.Chart, opts v1.UpdateOptions) (*v1beta1.Chart, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(chartsResource, "status", c.ns, chart), &v1beta1.Chart{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.Chart), err
+}
+
 // Delete takes name of the chart and deletes it. Returns an error if one occurs.
-func (c *FakeCharts) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeCharts) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(chartsResource, c.ns, name), &v1beta1.Chart{})
 
@@ -109,15 +123,15 @@ func (c *FakeCharts) Delete(name string, options *v1.DeleteOptions) error {
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeCharts) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(chartsResource, c.ns, listOptions)
+func (c *FakeCharts) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(chartsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &v1beta1.ChartList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched chart.
-func (c *FakeCharts) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.Chart, err error) {
+func (c *FakeCharts) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Chart, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(chartsResource, c.ns, name, pt, data, subresources...), &v1beta1.Chart{})
 
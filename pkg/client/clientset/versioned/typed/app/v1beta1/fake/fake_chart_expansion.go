@@ -0,0 +1,31 @@
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied chart.
+// It's applied as a merge patch, or created if the chart doesn't exist, see invokesApply.
+func (c *FakeCharts) Apply(ctx context.Context, chart *appv1beta1.ChartApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.Chart, err error) {
+	if chart == nil {
+		return nil, fmt.Errorf("chart provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(chart)
+	if err != nil {
+		return nil, err
+	}
+	if chart.ObjectMetaApplyConfiguration == nil || chart.Name == nil {
+		return nil, fmt.Errorf("chart.Name must be provided to Apply")
+	}
+	obj, err := invokesApply(c.Fake.Fake, chartsResource, c.ns, *chart.Name, data, &v1beta1.Chart{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.Chart), err
+}
@@ -0,0 +1,31 @@
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied chartRepo.
+// It's applied as a merge patch, or created if the chartRepo doesn't exist, see invokesApply.
+func (c *FakeChartRepos) Apply(ctx context.Context, chartRepo *appv1beta1.ChartRepoApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ChartRepo, err error) {
+	if chartRepo == nil {
+		return nil, fmt.Errorf("chartRepo provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(chartRepo)
+	if err != nil {
+		return nil, err
+	}
+	if chartRepo.ObjectMetaApplyConfiguration == nil || chartRepo.Name == nil {
+		return nil, fmt.Errorf("chartRepo.Name must be provided to Apply")
+	}
+	obj, err := invokesApply(c.Fake.Fake, chartreposResource, c.ns, *chartRepo.Name, data, &v1beta1.ChartRepo{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ChartRepo), err
+}
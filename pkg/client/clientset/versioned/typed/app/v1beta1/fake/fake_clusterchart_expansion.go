@@ -0,0 +1,31 @@
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterChart.
+// It's applied as a merge patch, or created if the clusterChart doesn't exist, see invokesApply.
+func (c *FakeClusterCharts) Apply(ctx context.Context, clusterChart *appv1beta1.ClusterChartApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ClusterChart, err error) {
+	if clusterChart == nil {
+		return nil, fmt.Errorf("clusterChart provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterChart)
+	if err != nil {
+		return nil, err
+	}
+	if clusterChart.ObjectMetaApplyConfiguration == nil || clusterChart.Name == nil {
+		return nil, fmt.Errorf("clusterChart.Name must be provided to Apply")
+	}
+	obj, err := invokesApply(c.Fake.Fake, clusterchartsResource, "", *clusterChart.Name, data, &v1beta1.ClusterChart{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterChart), err
+}
@@ -0,0 +1,31 @@
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied clusterChartRepo.
+// It's applied as a merge patch, or created if the clusterChartRepo doesn't exist, see invokesApply.
+func (c *FakeClusterChartRepos) Apply(ctx context.Context, clusterChartRepo *appv1beta1.ClusterChartRepoApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.ClusterChartRepo, err error) {
+	if clusterChartRepo == nil {
+		return nil, fmt.Errorf("clusterChartRepo provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(clusterChartRepo)
+	if err != nil {
+		return nil, err
+	}
+	if clusterChartRepo.ObjectMetaApplyConfiguration == nil || clusterChartRepo.Name == nil {
+		return nil, fmt.Errorf("clusterChartRepo.Name must be provided to Apply")
+	}
+	obj, err := invokesApply(c.Fake.Fake, clusterchartreposResource, "", *clusterChartRepo.Name, data, &v1beta1.ClusterChartRepo{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterChartRepo), err
+}
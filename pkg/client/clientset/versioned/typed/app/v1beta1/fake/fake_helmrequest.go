@@ -19,6 +19,8 @@ limitations under the License.
 package fake
 
 import (
+	"context"
+
 	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -39,7 +41,7 @@ var helmrequestsResource = schema.GroupVersionResource{Group: "app.alauda.io", V
 var helmrequestsKind = schema.GroupVersionKind{Group: "app.alauda.io", Version: "v1beta1", Kind: "HelmRequest"}
 
 // Get takes name of the helmRequest, and returns the corresponding helmRequest object, and an error if there is any.
-func (c *FakeHelmRequests) Get(name string, options v1.GetOptions) (result *v1beta1.HelmRequest, err error) {
+func (c *FakeHelmRequests) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.HelmRequest, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(helmrequestsResource, c.ns, name), &v1beta1.HelmRequest{})
 
@@ -50,7 +52,7 @@ func (c *FakeHelmRequests) Get(name string, options v1.GetOptions) (result *v1be
 }
 
 // List takes label and field selectors, and returns the list of HelmRequests that match those selectors.
-func (c *FakeHelmRequests) List(opts v1.ListOptions) (result *v1beta1.HelmRequestList, err error) {
+func (c *FakeHelmRequests) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.HelmRequestList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(helmrequestsResource, helmrequestsKind, c.ns, opts), &v1beta1.HelmRequestList{})
 
@@ -72,14 +74,14 @@ func (c *FakeHelmRequests) List(opts v1.ListOptions) (result *v1beta1.HelmReques
 }
 
 // Watch returns a watch.Interface that watches the requested helmRequests.
-func (c *FakeHelmRequests) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeHelmRequests) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(helmrequestsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a helmRequest and creates it.  Returns the server's representation of the helmRequest, and an error, if there is any.
-func (c *FakeHelmRequests) Create(helmRequest *v1beta1.HelmRequest) (result *v1beta1.HelmRequest, err error) {
+func (c *FakeHelmRequests) Create(ctx context.Context, helmRequest *v1beta1.HelmRequest, opts v1.CreateOptions) (result *v1beta1.HelmRequest, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(helmrequestsResource, c.ns, helmRequest), &v1beta1.HelmRequest{})
 
@@ -90,7 +92,7 @@ func (c *FakeHelmRequests) Create(helmRequest *v1beta1.HelmRequest) (result *v1b
 }
 
 // Update takes the representation of a helmRequest and updates it. Returns the server's representation of the helmRequest, and an error, if there is any.
-func (c *FakeHelmRequests) Update(helmRequest *v1beta1.HelmRequest) (result *v1beta1.HelmRequest, err error) {
+func (c *FakeHelmRequests) Update(ctx context.Context, helmRequest *v1beta1.HelmRequest, opts v1.UpdateOptions) (result *v1beta1.HelmRequest, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(helmrequestsResource, c.ns, helmRequest), &v1beta1.HelmRequest{})
 
@@ -102,7 +104,7 @@ func (c *FakeHelmRequests) Update(helmRequest *v1beta1.HelmRequest) (result *v1b
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-func (c *FakeHelmRequests) UpdateStatus(helmRequest *v1beta1.HelmRequest) (*v1beta1.HelmRequest, error) {
+func (c *FakeHelmRequests) UpdateStatus(ctx context.Context, helmRequest *v1beta1.HelmRequest, opts v1.UpdateOptions) (*v1beta1.HelmRequest, error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateSubresourceAction(helmrequestsResource, "status", c.ns, helmRequest), &v1beta1.HelmRequest{})
 
@@ -113,7 +115,7 @@ func (c *FakeHelmRequests) UpdateStatus(helmRequest *v1beta1.HelmRequest) (*v1be
 }
 
 // Delete takes name of the helmRequest and deletes it. Returns an error if one occurs.
-func (c *FakeHelmRequests) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeHelmRequests) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(helmrequestsResource, c.ns, name), &v1beta1.HelmRequest{})
 
@@ -121,15 +123,15 @@ func (c *FakeHelmRequests) Delete(name string, options *v1.DeleteOptions) error
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeHelmRequests) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(helmrequestsResource, c.ns, listOptions)
+func (c *FakeHelmRequests) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(helmrequestsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &v1beta1.HelmRequestList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched helmRequest.
-func (c *FakeHelmRequests) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.HelmRequest, err error) {
+func (c *FakeHelmRequests) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.HelmRequest, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(helmrequestsResource, c.ns, name, pt, data, subresources...), &v1beta1.HelmRequest{})
 
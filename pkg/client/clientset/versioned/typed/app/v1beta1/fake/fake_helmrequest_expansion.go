@@ -0,0 +1,31 @@
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied helmRequest.
+// It's applied as a merge patch, or created if the helmRequest doesn't exist, see invokesApply.
+func (c *FakeHelmRequests) Apply(ctx context.Context, helmRequest *appv1beta1.HelmRequestApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.HelmRequest, err error) {
+	if helmRequest == nil {
+		return nil, fmt.Errorf("helmRequest provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(helmRequest)
+	if err != nil {
+		return nil, err
+	}
+	if helmRequest.ObjectMetaApplyConfiguration == nil || helmRequest.Name == nil {
+		return nil, fmt.Errorf("helmRequest.Name must be provided to Apply")
+	}
+	obj, err := invokesApply(c.Fake.Fake, helmrequestsResource, c.ns, *helmRequest.Name, data, &v1beta1.HelmRequest{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.HelmRequest), err
+}
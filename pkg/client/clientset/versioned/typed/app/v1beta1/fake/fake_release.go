@@ -19,6 +19,8 @@ limitations under the License.
 package fake
 
 import (
+	"context"
+
 	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -39,7 +41,7 @@ var releasesResource = schema.GroupVersionResource{Group: "app.alauda.io", Versi
 var releasesKind = schema.GroupVersionKind{Group: "app.alauda.io", Version: "v1beta1", Kind: "Release"}
 
 // Get takes name of the release, and returns the corresponding release object, and an error if there is any.
-func (c *FakeReleases) Get(name string, options v1.GetOptions) (result *v1beta1.Release, err error) {
+func (c *FakeReleases) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.Release, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(releasesResource, c.ns, name), &v1beta1.Release{})
 
@@ -50,7 +52,7 @@ func (c *FakeReleases) Get(name string, options v1.GetOptions) (result *v1beta1.
 }
 
 // List takes label and field selectors, and returns the list of Releases that match those selectors.
-func (c *FakeReleases) List(opts v1.ListOptions) (result *v1beta1.ReleaseList, err error) {
+func (c *FakeReleases) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.ReleaseList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(releasesResource, releasesKind, c.ns, opts), &v1beta1.ReleaseList{})
 
@@ -72,14 +74,14 @@ func (c *FakeReleases) List(opts v1.ListOptions) (result *v1beta1.ReleaseList, e
 }
 
 // Watch returns a watch.Interface that watches the requested releases.
-func (c *FakeReleases) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeReleases) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(releasesResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a release and creates it.  Returns the server's representation of the release, and an error, if there is any.
-func (c *FakeReleases) Create(release *v1beta1.Release) (result *v1beta1.Release, err error) {
+func (c *FakeReleases) Create(ctx context.Context, release *v1beta1.Release, opts v1.CreateOptions) (result *v1beta1.Release, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(releasesResource, c.ns, release), &v1beta1.Release{})
 
@@ -90,7 +92,7 @@ func (c *FakeReleases) Create(release *v1beta1.Release) (result *v1beta1.Release
 }
 
 // Update takes the representation of a release and updates it. Returns the server's representation of the release, and an error, if there is any.
-func (c *FakeReleases) Update(release *v1beta1.Release) (result *v1beta1.Release, err error) {
+func (c *FakeReleases) Update(ctx context.Context, release *v1beta1.Release, opts v1.UpdateOptions) (result *v1beta1.Release, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(releasesResource, c.ns, release), &v1beta1.Release{})
 
@@ -102,7 +104,7 @@ func (c *FakeReleases) Update(release *v1beta1.Release) (result *v1beta1.Release
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-func (c *FakeReleases) UpdateStatus(release *v1beta1.Release) (*v1beta1.Release, error) {
+func (c *FakeReleases) UpdateStatus(ctx context.Context, release *v1beta1.Release, opts v1.UpdateOptions) (*v1beta1.Release, error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateSubresourceAction(releasesResource, "status", c.ns, release), &v1beta1.Release{})
 
@@ -113,7 +115,7 @@ func (c *FakeReleases) UpdateStatus(release *v1beta1.Release) (*v1beta1.Release,
 }
 
 // Delete takes name of the release and deletes it. Returns an error if one occurs.
-func (c *FakeReleases) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeReleases) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(releasesResource, c.ns, name), &v1beta1.Release{})
 
@@ -121,15 +123,15 @@ func (c *FakeReleases) Delete(name string, options *v1.DeleteOptions) error {
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeReleases) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(releasesResource, c.ns, listOptions)
+func (c *FakeReleases) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(releasesResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &v1beta1.ReleaseList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched release.
-func (c *FakeReleases) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.Release, err error) {
+func (c *FakeReleases) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Release, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(releasesResource, c.ns, name, pt, data, subresources...), &v1beta1.Release{})
 
@@ -0,0 +1,31 @@
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied release.
+// It's applied as a merge patch, or created if the release doesn't exist, see invokesApply.
+func (c *FakeReleases) Apply(ctx context.Context, release *appv1beta1.ReleaseApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.Release, err error) {
+	if release == nil {
+		return nil, fmt.Errorf("release provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(release)
+	if err != nil {
+		return nil, err
+	}
+	if release.ObjectMetaApplyConfiguration == nil || release.Name == nil {
+		return nil, fmt.Errorf("release.Name must be provided to Apply")
+	}
+	obj, err := invokesApply(c.Fake.Fake, releasesResource, c.ns, *release.Name, data, &v1beta1.Release{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.Release), err
+}
@@ -17,11 +17,3 @@ limitations under the License.
 // Code generated by client-gen. DO NOT EDIT.
 
 package v1beta1
-
-type ChartExpansion interface{}
-
-type ChartRepoExpansion interface{}
-
-type HelmRequestExpansion interface{}
-
-type ReleaseExpansion interface{}
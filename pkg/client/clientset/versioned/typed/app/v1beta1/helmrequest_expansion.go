@@ -0,0 +1,45 @@
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	scheme "github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// HelmRequestExpansion has the methods of HelmRequestInterface not generated by client-gen
+type HelmRequestExpansion interface {
+	Apply(ctx context.Context, helmRequest *appv1beta1.HelmRequestApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.HelmRequest, err error)
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied helmRequest.
+// opts.FieldManager is required by server-side apply.
+func (c *helmRequests) Apply(ctx context.Context, helmRequest *appv1beta1.HelmRequestApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.HelmRequest, err error) {
+	if helmRequest == nil {
+		return nil, fmt.Errorf("helmRequest provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(helmRequest)
+	if err != nil {
+		return nil, err
+	}
+	if helmRequest.ObjectMetaApplyConfiguration == nil || helmRequest.Name == nil {
+		return nil, fmt.Errorf("helmRequest.Name must be provided to Apply")
+	}
+	name := helmRequest.Name
+	result = &v1beta1.HelmRequest{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("helmrequests").
+		Name(*name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
@@ -19,6 +19,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	"time"
 
 	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
@@ -37,15 +38,15 @@ type ReleasesGetter interface {
 
 // ReleaseInterface has methods to work with Release resources.
 type ReleaseInterface interface {
-	Create(*v1beta1.Release) (*v1beta1.Release, error)
-	Update(*v1beta1.Release) (*v1beta1.Release, error)
-	UpdateStatus(*v1beta1.Release) (*v1beta1.Release, error)
-	Delete(name string, options *v1.DeleteOptions) error
-	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
-	Get(name string, options v1.GetOptions) (*v1beta1.Release, error)
-	List(opts v1.ListOptions) (*v1beta1.ReleaseList, error)
-	Watch(opts v1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.Release, err error)
+	Create(ctx context.Context, release *v1beta1.Release, opts v1.CreateOptions) (*v1beta1.Release, error)
+	Update(ctx context.Context, release *v1beta1.Release, opts v1.UpdateOptions) (*v1beta1.Release, error)
+	UpdateStatus(ctx context.Context, release *v1beta1.Release, opts v1.UpdateOptions) (*v1beta1.Release, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.Release, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.ReleaseList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Release, err error)
 	ReleaseExpansion
 }
 
@@ -64,20 +65,21 @@ func newReleases(c *AppV1beta1Client, namespace string) *releases {
 }
 
 // Get takes name of the release, and returns the corresponding release object, and an error if there is any.
-func (c *releases) Get(name string, options v1.GetOptions) (result *v1beta1.Release, err error) {
+func (c *releases) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.Release, err error) {
 	result = &v1beta1.Release{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("releases").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of Releases that match those selectors.
-func (c *releases) List(opts v1.ListOptions) (result *v1beta1.ReleaseList, err error) {
+func (c *releases) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.ReleaseList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -88,13 +90,14 @@ func (c *releases) List(opts v1.ListOptions) (result *v1beta1.ReleaseList, err e
 		Resource("releases").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested releases.
-func (c *releases) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *releases) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -105,29 +108,34 @@ func (c *releases) Watch(opts v1.ListOptions) (watch.Interface, error) {
 		Resource("releases").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
+		Context(ctx).
 		Watch()
 }
 
 // Create takes the representation of a release and creates it.  Returns the server's representation of the release, and an error, if there is any.
-func (c *releases) Create(release *v1beta1.Release) (result *v1beta1.Release, err error) {
+func (c *releases) Create(ctx context.Context, release *v1beta1.Release, opts v1.CreateOptions) (result *v1beta1.Release, err error) {
 	result = &v1beta1.Release{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("releases").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(release).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Update takes the representation of a release and updates it. Returns the server's representation of the release, and an error, if there is any.
-func (c *releases) Update(release *v1beta1.Release) (result *v1beta1.Release, err error) {
+func (c *releases) Update(ctx context.Context, release *v1beta1.Release, opts v1.UpdateOptions) (result *v1beta1.Release, err error) {
 	result = &v1beta1.Release{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("releases").
 		Name(release.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(release).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
@@ -135,56 +143,61 @@ func (c *releases) Update(release *v1beta1.Release) (result *v1beta1.Release, er
 
 // UpdateStatus was generated because the type contains a Status member.
 // Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
-
-func (c *releases) UpdateStatus(release *v1beta1.Release) (result *v1beta1.Release, err error) {
+func (c *releases) UpdateStatus(ctx context.Context, release *v1beta1.Release, opts v1.UpdateOptions) (result *v1beta1.Release, err error) {
 	result = &v1beta1.Release{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("releases").
 		Name(release.Name).
 		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(release).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
 }
 
 // Delete takes name of the release and deletes it. Returns an error if one occurs.
-func (c *releases) Delete(name string, options *v1.DeleteOptions) error {
+func (c *releases) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("releases").
 		Name(name).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *releases) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+func (c *releases) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("releases").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
+		Body(&opts).
+		Context(ctx).
 		Do().
 		Error()
 }
 
 // Patch applies the patch and returns the patched release.
-func (c *releases) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.Release, err error) {
+func (c *releases) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.Release, err error) {
 	result = &v1beta1.Release{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("releases").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
+		Context(ctx).
 		Do().
 		Into(result)
 	return
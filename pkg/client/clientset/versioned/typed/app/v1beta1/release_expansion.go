@@ -0,0 +1,45 @@
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	appv1beta1 "github.com/alauda/helm-crds/pkg/applyconfigurations/app/v1beta1"
+	scheme "github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// ReleaseExpansion has the methods of ReleaseInterface not generated by client-gen
+type ReleaseExpansion interface {
+	Apply(ctx context.Context, release *appv1beta1.ReleaseApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.Release, err error)
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied release.
+// opts.FieldManager is required by server-side apply.
+func (c *releases) Apply(ctx context.Context, release *appv1beta1.ReleaseApplyConfiguration, opts v1.PatchOptions) (result *v1beta1.Release, err error) {
+	if release == nil {
+		return nil, fmt.Errorf("release provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(release)
+	if err != nil {
+		return nil, err
+	}
+	if release.ObjectMetaApplyConfiguration == nil || release.Name == nil {
+		return nil, fmt.Errorf("release.Name must be provided to Apply")
+	}
+	name := release.Name
+	result = &v1beta1.Release{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("releases").
+		Name(*name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Context(ctx).
+		Do().
+		Into(result)
+	return
+}
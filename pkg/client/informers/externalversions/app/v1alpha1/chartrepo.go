@@ -19,6 +19,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	time "time"
 
 	appv1alpha1 "github.com/alauda/helm-crds/pkg/apis/app/v1alpha1"
@@ -61,13 +62,13 @@ func NewFilteredChartRepoInformer(client versioned.Interface, namespace string,
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.AppV1alpha1().ChartRepos(namespace).List(options)
+				return client.AppV1alpha1().ChartRepos(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.AppV1alpha1().ChartRepos(namespace).Watch(options)
+				return client.AppV1alpha1().ChartRepos(namespace).Watch(context.TODO(), options)
 			},
 		},
 		&appv1alpha1.ChartRepo{},
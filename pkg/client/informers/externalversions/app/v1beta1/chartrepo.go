@@ -19,6 +19,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
 	time "time"
 
 	appv1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
@@ -61,13 +62,13 @@ func NewFilteredChartRepoInformer(client versioned.Interface, namespace string,
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.AppV1beta1().ChartRepos(namespace).List(options)
+				return client.AppV1beta1().ChartRepos(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.AppV1beta1().ChartRepos(namespace).Watch(options)
+				return client.AppV1beta1().ChartRepos(namespace).Watch(context.TODO(), options)
 			},
 		},
 		&appv1beta1.ChartRepo{},
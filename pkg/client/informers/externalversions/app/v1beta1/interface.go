@@ -28,6 +28,10 @@ type Interface interface {
 	Charts() ChartInformer
 	// ChartRepos returns a ChartRepoInformer.
 	ChartRepos() ChartRepoInformer
+	// ClusterCharts returns a ClusterChartInformer.
+	ClusterCharts() ClusterChartInformer
+	// ClusterChartRepos returns a ClusterChartRepoInformer.
+	ClusterChartRepos() ClusterChartRepoInformer
 	// HelmRequests returns a HelmRequestInformer.
 	HelmRequests() HelmRequestInformer
 	// Releases returns a ReleaseInformer.
@@ -55,6 +59,16 @@ func (v *version) ChartRepos() ChartRepoInformer {
 	return &chartRepoInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterCharts returns a ClusterChartInformer.
+func (v *version) ClusterCharts() ClusterChartInformer {
+	return &clusterChartInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterChartRepos returns a ClusterChartRepoInformer.
+func (v *version) ClusterChartRepos() ClusterChartRepoInformer {
+	return &clusterChartRepoInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // HelmRequests returns a HelmRequestInformer.
 func (v *version) HelmRequests() HelmRequestInformer {
 	return &helmRequestInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -68,6 +68,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.App().V1beta1().Charts().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("chartrepos"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.App().V1beta1().ChartRepos().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("clustercharts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.App().V1beta1().ClusterCharts().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("clusterchartrepos"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.App().V1beta1().ClusterChartRepos().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("helmrequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.App().V1beta1().HelmRequests().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("releases"):
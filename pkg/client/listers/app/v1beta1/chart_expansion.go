@@ -0,0 +1,142 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ChartListerExpansion allows custom methods to be added to
+// ChartLister.
+type ChartListerExpansion interface {
+	// FindChart finds a chart in namespace by it's name in HelmRequest, eg: stable/nginx. If the name
+	// contains a namespace(eg: default/stable/nginx), it overrides namespace. If the repo is omitted, the
+	// chart must be unique in the namespace.
+	FindChart(namespace, name string) (*v1beta1.Chart, error)
+}
+
+// ClusterChartListerExpansion allows custom methods to be added to
+// ClusterChartLister.
+type ClusterChartListerExpansion interface {
+	// FindChart finds a ClusterChart by it's name in HelmRequest, eg: stable/nginx. If the repo is omitted,
+	// the chart must be unique in all ClusterChartRepos.
+	FindChart(name string) (*v1beta1.ClusterChart, error)
+}
+
+// FindChart implements ChartListerExpansion
+func (s *chartLister) FindChart(namespace, name string) (*v1beta1.Chart, error) {
+	ref, err := parseRepoChartReference(name)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	repo, chart := ref.Repo, ref.Chart
+	if repo != "" {
+		return s.Charts(namespace).Get(v1beta1.ChartObjectName(repo, chart))
+	}
+
+	charts, err := s.Charts(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var found []*v1beta1.Chart
+	for _, item := range charts {
+		if isChart(&item.Spec, chart) {
+			found = append(found, item)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, errors.NewNotFound(v1beta1.Resource("chart"), name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("chart %s found in %d repos, please specify the repo", chart, len(found))
+	}
+}
+
+// FindChart implements ClusterChartListerExpansion
+func (s *clusterChartLister) FindChart(name string) (*v1beta1.ClusterChart, error) {
+	ref, err := parseRepoChartReference(name)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Namespace != "" {
+		return nil, fmt.Errorf("chart %s refers to a namespace, it's not a cluster chart", name)
+	}
+	repo, chart := ref.Repo, ref.Chart
+	if repo != "" {
+		return s.Get(v1beta1.ChartObjectName(repo, chart))
+	}
+
+	charts, err := s.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var found []*v1beta1.ClusterChart
+	for _, item := range charts {
+		if isChart(&item.Spec, chart) {
+			found = append(found, item)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, errors.NewNotFound(v1beta1.Resource("clusterchart"), name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("chart %s found in %d cluster repos, please specify the repo", chart, len(found))
+	}
+}
+
+// ResolveChart finds the chart a HelmRequest refers to, and returns it with the kind it's stored as.
+// If .spec.chartKind is set, only that kind is looked up. Otherwise the Chart in the namespace of the
+// HelmRequest is preferred, and the ClusterChart is used if it's not found. ClusterCharts are not looked
+// up if clusterCharts is nil or the chart refers to a namespace, the NotFound error of the Chart is returned. A ClusterChart is returned as a copy in
+// the form of Chart.
+func ResolveChart(charts ChartLister, clusterCharts ClusterChartLister, hr *v1beta1.HelmRequest) (*v1beta1.Chart, v1beta1.ChartKind, error) {
+	name := hr.Spec.Chart
+	kind := hr.Spec.ChartKind
+
+	if kind != v1beta1.ChartKindClusterChart {
+		chart, err := charts.FindChart(hr.Namespace, name)
+		if err == nil || kind == v1beta1.ChartKindChart || !errors.IsNotFound(err) {
+			return chart, v1beta1.ChartKindChart, err
+		}
+		if clusterCharts == nil {
+			return nil, v1beta1.ChartKindChart, err
+		}
+		if ref, _ := v1beta1.ParseChartReference(name); ref != nil && ref.Namespace != "" {
+			return nil, v1beta1.ChartKindChart, err
+		}
+	}
+
+	if clusterCharts == nil {
+		return nil, v1beta1.ChartKindClusterChart, fmt.Errorf("cluster charts are not available to find chart %s", name)
+	}
+	chart, err := clusterCharts.FindChart(name)
+	if err != nil {
+		return nil, v1beta1.ChartKindClusterChart, err
+	}
+	return chart.AsChart(), v1beta1.ChartKindClusterChart, nil
+}
+
+func parseRepoChartReference(name string) (*v1beta1.ChartReference, error) {
+	ref, err := v1beta1.ParseChartReference(name)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Kind != v1beta1.ChartReferenceRepo {
+		return nil, fmt.Errorf("chart %s is not in a chart repo", name)
+	}
+	return ref, nil
+}
+
+// isChart checks whether the chart resource stores the chart by the name in it's versions
+func isChart(spec *v1beta1.ChartSpec, chart string) bool {
+	return len(spec.Versions) > 0 && spec.Versions[0] != nil && spec.Versions[0].Name == chart
+}
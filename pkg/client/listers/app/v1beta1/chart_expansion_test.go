@@ -0,0 +1,76 @@
+package v1beta1
+
+import (
+	"testing"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newChart(namespace, repo, name string) *v1beta1.Chart {
+	return &v1beta1.Chart{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: v1beta1.ChartObjectName(repo, name)},
+		Spec: v1beta1.ChartSpec{
+			Versions: []*v1beta1.ChartVersion{{ChartMetadata: v1beta1.ChartMetadata{Name: name, Version: "1.0.0"}}},
+		},
+	}
+}
+
+func TestResolveChart(t *testing.T) {
+	charts := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	charts.Add(newChart("default", "stable", "nginx"))
+	clusterCharts := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	clusterChart := newChart("", "stable", "redis")
+	clusterCharts.Add(&v1beta1.ClusterChart{ObjectMeta: clusterChart.ObjectMeta, Spec: clusterChart.Spec})
+
+	tests := []struct {
+		name       string
+		chart      string
+		chartKind  v1beta1.ChartKind
+		noCluster  bool
+		expect     string
+		expectKind v1beta1.ChartKind
+		notFound   bool
+		otherErr   bool
+	}{
+		{name: "chart", chart: "stable/nginx", expect: "nginx.stable", expectKind: v1beta1.ChartKindChart},
+		{name: "cluster chart", chart: "stable/redis", expect: "redis.stable", expectKind: v1beta1.ChartKindClusterChart},
+		{name: "chart kind", chart: "stable/redis", chartKind: v1beta1.ChartKindChart, expectKind: v1beta1.ChartKindChart, notFound: true},
+		{name: "cluster chart kind", chart: "stable/nginx", chartKind: v1beta1.ChartKindClusterChart, expectKind: v1beta1.ChartKindClusterChart, notFound: true},
+		{name: "namespaced", chart: "default/stable/redis", expectKind: v1beta1.ChartKindChart, notFound: true},
+		{name: "no cluster charts", chart: "stable/redis", noCluster: true, expectKind: v1beta1.ChartKindChart, notFound: true},
+		{name: "no cluster charts with kind", chart: "stable/redis", chartKind: v1beta1.ChartKindClusterChart, noCluster: true, expectKind: v1beta1.ChartKindClusterChart, otherErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := &v1beta1.HelmRequest{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+				Spec:       v1beta1.HelmRequestSpec{Chart: tt.chart, ChartKind: tt.chartKind},
+			}
+			var cl ClusterChartLister
+			if !tt.noCluster {
+				cl = NewClusterChartLister(clusterCharts)
+			}
+			chart, kind, err := ResolveChart(NewChartLister(charts), cl, hr)
+			if kind != tt.expectKind {
+				t.Errorf("expect kind %s, got %s", tt.expectKind, kind)
+			}
+			switch {
+			case tt.notFound:
+				if !errors.IsNotFound(err) {
+					t.Errorf("expect not found, got %v", err)
+				}
+			case tt.otherErr:
+				if err == nil || errors.IsNotFound(err) {
+					t.Errorf("expect an error other than not found, got %v", err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case chart.Name != tt.expect:
+				t.Errorf("expect chart %s, got %s", tt.expect, chart.Name)
+			}
+		})
+	}
+}
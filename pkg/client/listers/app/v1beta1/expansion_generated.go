@@ -18,10 +18,6 @@ limitations under the License.
 
 package v1beta1
 
-// ChartListerExpansion allows custom methods to be added to
-// ChartLister.
-type ChartListerExpansion interface{}
-
 // ChartNamespaceListerExpansion allows custom methods to be added to
 // ChartNamespaceLister.
 type ChartNamespaceListerExpansion interface{}
@@ -34,17 +30,9 @@ type ChartRepoListerExpansion interface{}
 // ChartRepoNamespaceLister.
 type ChartRepoNamespaceListerExpansion interface{}
 
-// HelmRequestListerExpansion allows custom methods to be added to
-// HelmRequestLister.
-type HelmRequestListerExpansion interface{}
-
-// HelmRequestNamespaceListerExpansion allows custom methods to be added to
-// HelmRequestNamespaceLister.
-type HelmRequestNamespaceListerExpansion interface{}
-
-// ReleaseListerExpansion allows custom methods to be added to
-// ReleaseLister.
-type ReleaseListerExpansion interface{}
+// ClusterChartRepoListerExpansion allows custom methods to be added to
+// ClusterChartRepoLister.
+type ClusterChartRepoListerExpansion interface{}
 
 // ReleaseNamespaceListerExpansion allows custom methods to be added to
 // ReleaseNamespaceLister.
@@ -0,0 +1,88 @@
+package v1beta1
+
+import (
+	"sort"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+)
+
+// HelmRequestListerExpansion allows custom methods to be added to
+// HelmRequestLister.
+type HelmRequestListerExpansion interface {
+	// ByChart lists the HelmRequests in all namespaces refer to the chart in the repo of the namespace,
+	// the namespace in .spec.chart or the namespace of the HelmRequest if omitted. An empty namespace
+	// matches the ones may use a ClusterChart, ie: without a namespace in .spec.chart. The repo is matched
+	// as it's written in .spec.chart, so an empty repo matches the ones without a repo.
+	ByChart(namespace, repo, chart string) ([]*v1beta1.HelmRequest, error)
+	// ByTargetCluster lists the HelmRequests will be installed to the cluster, including the ones
+	// installed to all clusters
+	ByTargetCluster(name string) ([]*v1beta1.HelmRequest, error)
+	// ByPhase lists the HelmRequests in the phase
+	ByPhase(phase v1beta1.HelmRequestPhase) ([]*v1beta1.HelmRequest, error)
+}
+
+// HelmRequestNamespaceListerExpansion allows custom methods to be added to
+// HelmRequestNamespaceLister.
+type HelmRequestNamespaceListerExpansion interface {
+	// Dependents lists the HelmRequests depend on the HelmRequest of the name
+	Dependents(name string) ([]*v1beta1.HelmRequest, error)
+}
+
+// ByChart implements HelmRequestListerExpansion
+func (s *helmRequestLister) ByChart(namespace, repo, chart string) ([]*v1beta1.HelmRequest, error) {
+	objs, err := byIndex(s.indexer, HelmRequestChartIndex, helmRequestChartIndexFunc, chartIndexValue(namespace, repo, chart))
+	if err != nil {
+		return nil, err
+	}
+	return toHelmRequests(objs), nil
+}
+
+// ByTargetCluster implements HelmRequestListerExpansion
+func (s *helmRequestLister) ByTargetCluster(name string) ([]*v1beta1.HelmRequest, error) {
+	objs, err := byIndex(s.indexer, HelmRequestClusterIndex, helmRequestClusterIndexFunc, name)
+	if err != nil {
+		return nil, err
+	}
+	if name != AllClustersIndexValue {
+		all, err := byIndex(s.indexer, HelmRequestClusterIndex, helmRequestClusterIndexFunc, AllClustersIndexValue)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, all...)
+	}
+	return toHelmRequests(objs), nil
+}
+
+// ByPhase implements HelmRequestListerExpansion
+func (s *helmRequestLister) ByPhase(phase v1beta1.HelmRequestPhase) ([]*v1beta1.HelmRequest, error) {
+	objs, err := byIndex(s.indexer, HelmRequestPhaseIndex, helmRequestPhaseIndexFunc, string(phase))
+	if err != nil {
+		return nil, err
+	}
+	return toHelmRequests(objs), nil
+}
+
+// Dependents implements HelmRequestNamespaceListerExpansion
+func (s helmRequestNamespaceLister) Dependents(name string) ([]*v1beta1.HelmRequest, error) {
+	objs, err := byIndex(s.indexer, HelmRequestDependencyIndex, helmRequestDependencyIndexFunc, s.namespace+"/"+name)
+	if err != nil {
+		return nil, err
+	}
+	return toHelmRequests(objs), nil
+}
+
+// toHelmRequests converts the objects and sorts them by namespace and name, since the order of the
+// index is random
+func toHelmRequests(objs []interface{}) []*v1beta1.HelmRequest {
+	result := make([]*v1beta1.HelmRequest, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.(*v1beta1.HelmRequest))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,155 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// HelmRequestChartIndex indexes HelmRequests by <namespace>/<repo>/<chart> of .spec.chart, the
+	// namespace is the one of the HelmRequest if omitted, and repo is empty if omitted. The ones may use a
+	// ClusterChart are indexed by /<repo>/<chart> too. HelmRequests not referring to a chart in a repo are
+	// not indexed
+	HelmRequestChartIndex = "chart"
+	// HelmRequestClusterIndex indexes HelmRequests by the target cluster, AllClustersIndexValue for the
+	// ones installed to all clusters
+	HelmRequestClusterIndex = "cluster"
+	// HelmRequestDependencyIndex indexes HelmRequests by <namespace>/<name> of their dependencies
+	HelmRequestDependencyIndex = "dependency"
+	// HelmRequestPhaseIndex indexes HelmRequests by .status.phase
+	HelmRequestPhaseIndex = "phase"
+	// ReleaseNameIndex indexes Releases by <namespace>/<release name>
+	ReleaseNameIndex = "release"
+
+	// AllClustersIndexValue is the HelmRequestClusterIndex value of HelmRequests with InstallToAllClusters
+	AllClustersIndexValue = "*"
+)
+
+// HelmRequestIndexers returns the indexers used by the HelmRequestLister expansions
+func HelmRequestIndexers() cache.Indexers {
+	return cache.Indexers{
+		HelmRequestChartIndex:      helmRequestChartIndexFunc,
+		HelmRequestClusterIndex:    helmRequestClusterIndexFunc,
+		HelmRequestDependencyIndex: helmRequestDependencyIndexFunc,
+		HelmRequestPhaseIndex:      helmRequestPhaseIndexFunc,
+	}
+}
+
+// ReleaseIndexers returns the indexers used by the ReleaseLister expansions
+func ReleaseIndexers() cache.Indexers {
+	return cache.Indexers{
+		ReleaseNameIndex: releaseNameIndexFunc,
+	}
+}
+
+func helmRequestChartIndexFunc(obj interface{}) ([]string, error) {
+	hr, ok := obj.(*v1beta1.HelmRequest)
+	if !ok {
+		return nil, nil
+	}
+	ref, err := v1beta1.ParseChartReference(hr.Spec.Chart)
+	if err != nil || ref.Kind != v1beta1.ChartReferenceRepo {
+		return nil, nil
+	}
+
+	var result []string
+	if hr.Spec.ChartKind != v1beta1.ChartKindClusterChart {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = hr.Namespace
+		}
+		result = append(result, chartIndexValue(namespace, ref.Repo, ref.Chart))
+	}
+	if hr.Spec.ChartKind != v1beta1.ChartKindChart && ref.Namespace == "" {
+		result = append(result, chartIndexValue("", ref.Repo, ref.Chart))
+	}
+	return result, nil
+}
+
+func chartIndexValue(namespace, repo, chart string) string {
+	return namespace + "/" + repo + "/" + chart
+}
+
+func helmRequestClusterIndexFunc(obj interface{}) ([]string, error) {
+	hr, ok := obj.(*v1beta1.HelmRequest)
+	if !ok {
+		return nil, nil
+	}
+	if hr.Spec.InstallToAllClusters {
+		return []string{AllClustersIndexValue}, nil
+	}
+	return []string{hr.Spec.ClusterName}, nil
+}
+
+func helmRequestDependencyIndexFunc(obj interface{}) ([]string, error) {
+	hr, ok := obj.(*v1beta1.HelmRequest)
+	if !ok {
+		return nil, nil
+	}
+	result := make([]string, 0, len(hr.Spec.Dependencies))
+	for _, dep := range hr.Spec.Dependencies {
+		result = append(result, hr.Namespace+"/"+dep)
+	}
+	return result, nil
+}
+
+func helmRequestPhaseIndexFunc(obj interface{}) ([]string, error) {
+	hr, ok := obj.(*v1beta1.HelmRequest)
+	if !ok {
+		return nil, nil
+	}
+	return []string{string(hr.Status.Phase)}, nil
+}
+
+func releaseNameIndexFunc(obj interface{}) ([]string, error) {
+	rel, ok := obj.(*v1beta1.Release)
+	if !ok {
+		return nil, nil
+	}
+	return []string{rel.Namespace + "/" + rel.Spec.Name}, nil
+}
+
+// AddHelmRequestIndexers registers HelmRequestIndexers to the HelmRequest informer, so the queries of the
+// lister expansions don't need to scan all objects. It must be called before the informer is started, the
+// informer is usually factory.App().V1beta1().HelmRequests().Informer()
+func AddHelmRequestIndexers(informer cache.SharedIndexInformer) error {
+	if err := informer.AddIndexers(HelmRequestIndexers()); err != nil {
+		return fmt.Errorf("add indexers to HelmRequest informer error: %s", err.Error())
+	}
+	return nil
+}
+
+// AddReleaseIndexers registers ReleaseIndexers to the Release informer, see AddHelmRequestIndexers
+func AddReleaseIndexers(informer cache.SharedIndexInformer) error {
+	if err := informer.AddIndexers(ReleaseIndexers()); err != nil {
+		return fmt.Errorf("add indexers to Release informer error: %s", err.Error())
+	}
+	return nil
+}
+
+// byIndex returns the objects by the index. If the indexer doesn't have the index, eg: the informer
+// is not wired by AddHelmRequestIndexers or AddReleaseIndexers, all objects are filtered by the index
+// func instead.
+func byIndex(indexer cache.Indexer, name string, indexFunc cache.IndexFunc, value string) ([]interface{}, error) {
+	if _, ok := indexer.GetIndexers()[name]; ok {
+		return indexer.ByIndex(name, value)
+	}
+
+	var result []interface{}
+	err := cache.ListAll(indexer, labels.Everything(), func(obj interface{}) {
+		values, err := indexFunc(obj)
+		if err != nil {
+			return
+		}
+		for _, v := range values {
+			if v == value {
+				result = append(result, obj)
+				return
+			}
+		}
+	})
+	return result, err
+}
@@ -0,0 +1,252 @@
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newHelmRequest(namespace, name, chart string, kind v1beta1.ChartKind) *v1beta1.HelmRequest {
+	return &v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1beta1.HelmRequestSpec{Chart: chart, ChartKind: kind},
+	}
+}
+
+func TestByChart(t *testing.T) {
+	objs := []*v1beta1.HelmRequest{
+		newHelmRequest("default", "a", "stable/nginx", ""),
+		newHelmRequest("default", "b", "stable/nginx@1.0.0", v1beta1.ChartKindChart),
+		newHelmRequest("default", "c", "stable/nginx", v1beta1.ChartKindClusterChart),
+		newHelmRequest("other", "d", "stable/nginx", ""),
+		newHelmRequest("other", "e", "default/stable/nginx", ""),
+		newHelmRequest("default", "f", "nginx", ""),
+		newHelmRequest("default", "g", "stable/redis", ""),
+		newHelmRequest("default", "h", "oci://example.com/charts/nginx", ""),
+	}
+
+	tests := []struct {
+		namespace string
+		repo      string
+		chart     string
+		expect    []string
+	}{
+		{namespace: "default", repo: "stable", chart: "nginx", expect: []string{"default/a", "default/b", "other/e"}},
+		{namespace: "other", repo: "stable", chart: "nginx", expect: []string{"other/d"}},
+		{namespace: "", repo: "stable", chart: "nginx", expect: []string{"default/a", "default/c", "other/d"}},
+		{namespace: "default", repo: "", chart: "nginx", expect: []string{"default/f"}},
+		{namespace: "kube-system", repo: "stable", chart: "nginx", expect: nil},
+	}
+	for _, indexed := range []bool{true, false} {
+		indexers := cache.Indexers{}
+		if indexed {
+			indexers = HelmRequestIndexers()
+		}
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, indexers)
+		for _, obj := range objs {
+			indexer.Add(obj)
+		}
+		lister := NewHelmRequestLister(indexer)
+
+		for _, tt := range tests {
+			result, err := lister.ByChart(tt.namespace, tt.repo, tt.chart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, hr := range result {
+				got = append(got, hr.Namespace+"/"+hr.Name)
+			}
+			if len(got) != len(tt.expect) {
+				t.Errorf("indexed %v, ByChart(%q, %q, %q): expect %v, got %v", indexed, tt.namespace, tt.repo, tt.chart, tt.expect, got)
+				continue
+			}
+			for i := range got {
+				if got[i] != tt.expect[i] {
+					t.Errorf("indexed %v, ByChart(%q, %q, %q): expect %v, got %v", indexed, tt.namespace, tt.repo, tt.chart, tt.expect, got)
+					break
+				}
+			}
+		}
+	}
+}
+
+// newTestIndexer adds the objects to an indexer with the indexers, or without any index if not indexed
+func newTestIndexer(indexed bool, indexers cache.Indexers, objs ...interface{}) cache.Indexer {
+	if !indexed {
+		indexers = cache.Indexers{}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, indexers)
+	for _, obj := range objs {
+		indexer.Add(obj)
+	}
+	return indexer
+}
+
+func helmRequestNames(result []*v1beta1.HelmRequest) []string {
+	var names []string
+	for _, hr := range result {
+		names = append(names, hr.Namespace+"/"+hr.Name)
+	}
+	return names
+}
+
+func TestByTargetCluster(t *testing.T) {
+	newClusterHelmRequest := func(namespace, name, cluster string, all bool) *v1beta1.HelmRequest {
+		hr := newHelmRequest(namespace, name, "stable/nginx", "")
+		hr.Spec.ClusterName, hr.Spec.InstallToAllClusters = cluster, all
+		return hr
+	}
+	objs := []interface{}{
+		newClusterHelmRequest("default", "a", "business", false),
+		newClusterHelmRequest("other", "b", "business", false),
+		newClusterHelmRequest("default", "c", "", false),
+		newClusterHelmRequest("default", "d", "", true),
+		// the cluster name is ignored when installed to all clusters
+		newClusterHelmRequest("other", "e", "global", true),
+		newClusterHelmRequest("default", "f", "global", false),
+	}
+
+	tests := []struct {
+		cluster string
+		expect  []string
+	}{
+		{cluster: "business", expect: []string{"default/a", "default/d", "other/b", "other/e"}},
+		{cluster: "", expect: []string{"default/c", "default/d", "other/e"}},
+		{cluster: "global", expect: []string{"default/d", "default/f", "other/e"}},
+		{cluster: "unknown", expect: []string{"default/d", "other/e"}},
+		{cluster: AllClustersIndexValue, expect: []string{"default/d", "other/e"}},
+	}
+	for _, indexed := range []bool{true, false} {
+		lister := NewHelmRequestLister(newTestIndexer(indexed, HelmRequestIndexers(), objs...))
+		for _, tt := range tests {
+			result, err := lister.ByTargetCluster(tt.cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := helmRequestNames(result); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("indexed %v, ByTargetCluster(%q): expect %v, got %v", indexed, tt.cluster, tt.expect, got)
+			}
+		}
+	}
+}
+
+func TestDependents(t *testing.T) {
+	newDependent := func(namespace, name string, deps ...string) *v1beta1.HelmRequest {
+		hr := newHelmRequest(namespace, name, "stable/nginx", "")
+		hr.Spec.Dependencies = deps
+		return hr
+	}
+	objs := []interface{}{
+		newDependent("default", "web", "mysql", "redis"),
+		newDependent("default", "api", "mysql"),
+		newDependent("default", "mysql"),
+		// the dependencies are in the same namespace
+		newDependent("other", "web", "mysql"),
+	}
+
+	tests := []struct {
+		namespace string
+		name      string
+		expect    []string
+	}{
+		{namespace: "default", name: "mysql", expect: []string{"default/api", "default/web"}},
+		{namespace: "default", name: "redis", expect: []string{"default/web"}},
+		{namespace: "other", name: "mysql", expect: []string{"other/web"}},
+		{namespace: "default", name: "web"},
+	}
+	for _, indexed := range []bool{true, false} {
+		lister := NewHelmRequestLister(newTestIndexer(indexed, HelmRequestIndexers(), objs...))
+		for _, tt := range tests {
+			result, err := lister.HelmRequests(tt.namespace).Dependents(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := helmRequestNames(result); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("indexed %v, Dependents(%s/%s): expect %v, got %v", indexed, tt.namespace, tt.name, tt.expect, got)
+			}
+		}
+	}
+}
+
+func TestByPhase(t *testing.T) {
+	newPhaseHelmRequest := func(namespace, name string, phase v1beta1.HelmRequestPhase) *v1beta1.HelmRequest {
+		hr := newHelmRequest(namespace, name, "stable/nginx", "")
+		hr.Status.Phase = phase
+		return hr
+	}
+	objs := []interface{}{
+		newPhaseHelmRequest("default", "a", v1beta1.HelmRequestSynced),
+		newPhaseHelmRequest("other", "b", v1beta1.HelmRequestFailed),
+		newPhaseHelmRequest("default", "c", v1beta1.HelmRequestFailed),
+		newPhaseHelmRequest("default", "d", ""),
+	}
+
+	tests := []struct {
+		phase  v1beta1.HelmRequestPhase
+		expect []string
+	}{
+		{phase: v1beta1.HelmRequestSynced, expect: []string{"default/a"}},
+		{phase: v1beta1.HelmRequestFailed, expect: []string{"default/c", "other/b"}},
+		{phase: "", expect: []string{"default/d"}},
+		{phase: v1beta1.HelmRequestPending},
+	}
+	for _, indexed := range []bool{true, false} {
+		lister := NewHelmRequestLister(newTestIndexer(indexed, HelmRequestIndexers(), objs...))
+		for _, tt := range tests {
+			result, err := lister.ByPhase(tt.phase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := helmRequestNames(result); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("indexed %v, ByPhase(%q): expect %v, got %v", indexed, tt.phase, tt.expect, got)
+			}
+		}
+	}
+}
+
+func TestReleasesForHelmRequest(t *testing.T) {
+	newRelease := func(namespace, name string, version int) *v1beta1.Release {
+		rel := &v1beta1.Release{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: v1beta1.ReleaseObjectName(name, version)}}
+		rel.Spec.Name, rel.Spec.Version = name, version
+		return rel
+	}
+	objs := []interface{}{
+		newRelease("web", "nginx", 10),
+		newRelease("web", "nginx", 2),
+		newRelease("web", "nginx", 1),
+		newRelease("web", "redis", 1),
+		newRelease("default", "nginx", 3),
+	}
+
+	releaseNamed := newHelmRequest("default", "frontend", "stable/nginx", "")
+	releaseNamed.Spec.ReleaseName, releaseNamed.Spec.Namespace = "nginx", "web"
+	tests := []struct {
+		name   string
+		hr     *v1beta1.HelmRequest
+		expect []string
+	}{
+		{name: "release name and namespace", hr: releaseNamed, expect: []string{"web/nginx.v1", "web/nginx.v2", "web/nginx.v10"}},
+		{name: "defaults", hr: newHelmRequest("default", "nginx", "stable/nginx", ""), expect: []string{"default/nginx.v3"}},
+		{name: "none", hr: newHelmRequest("default", "redis", "stable/redis", "")},
+	}
+	for _, indexed := range []bool{true, false} {
+		lister := NewReleaseLister(newTestIndexer(indexed, ReleaseIndexers(), objs...))
+		for _, tt := range tests {
+			result, err := lister.ReleasesForHelmRequest(tt.hr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, rel := range result {
+				got = append(got, rel.Namespace+"/"+rel.Name)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("indexed %v, %s: expect %v, got %v", indexed, tt.name, tt.expect, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package v1beta1
+
+import (
+	"sort"
+
+	v1beta1 "github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+)
+
+// ReleaseListerExpansion allows custom methods to be added to
+// ReleaseLister.
+type ReleaseListerExpansion interface {
+	// ReleasesForHelmRequest lists the Releases of the HelmRequest, from the oldest to the newest
+	ReleasesForHelmRequest(hr *v1beta1.HelmRequest) ([]*v1beta1.Release, error)
+}
+
+// ReleasesForHelmRequest implements ReleaseListerExpansion
+func (s *releaseLister) ReleasesForHelmRequest(hr *v1beta1.HelmRequest) ([]*v1beta1.Release, error) {
+	key := hr.GetReleaseNamespace() + "/" + hr.GetReleaseName()
+	objs, err := byIndex(s.indexer, ReleaseNameIndex, releaseNameIndexFunc, key)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*v1beta1.Release, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.(*v1beta1.Release))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Spec.Version < result[j].Spec.Version
+	})
+	return result, nil
+}
@@ -0,0 +1,262 @@
+// Package diff compares the desired manifests of a HelmRequest with the manifests of its deployed
+// Release, resource by resource.
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ChangeType is the type of change of a resource
+type ChangeType string
+
+const (
+	// Added means the resource only exists in the desired manifests
+	Added ChangeType = "Added"
+	// Removed means the resource only exists in the deployed manifests
+	Removed ChangeType = "Removed"
+	// Modified means the resource exists in both but differs
+	Modified ChangeType = "Modified"
+	// Unchanged means the resource is the same in both
+	Unchanged ChangeType = "Unchanged"
+)
+
+// ResourceKey identifies a resource in manifests
+type ResourceKey struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (k ResourceKey) String() string {
+	if k.Namespace == "" {
+		return fmt.Sprintf("%s/%s", k.Kind, k.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", k.Namespace, k.Kind, k.Name)
+}
+
+// ResourceDiff is the change of a resource
+type ResourceDiff struct {
+	ResourceKey `json:",inline"`
+	Change      ChangeType `json:"change"`
+	// Diff is the unified diff from the deployed to the desired one, empty if unchanged
+	Diff string `json:"diff,omitempty"`
+}
+
+// Result is the diff of all resources, sorted by kind, namespace and name
+type Result struct {
+	Resources []ResourceDiff `json:"resources"`
+}
+
+// HasChanges checks whether any resource is added, removed or modified
+func (r *Result) HasChanges() bool {
+	for _, item := range r.Resources {
+		if item.Change != Unchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// Changed returns the resources that are not unchanged
+func (r *Result) Changed() []ResourceDiff {
+	var result []ResourceDiff
+	for _, item := range r.Resources {
+		if item.Change != Unchanged {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// String returns the unified diffs of all changed resources
+func (r *Result) String() string {
+	var sb strings.Builder
+	for _, item := range r.Changed() {
+		sb.WriteString(item.Diff)
+	}
+	return sb.String()
+}
+
+// Options are the options of diffing manifests
+type Options struct {
+	// Namespace is used for the resources without a namespace, usually the namespace of the release
+	Namespace string
+	// Context is the number of context lines in the diff, default to 3
+	Context int
+	// ShowSecrets disables masking the data of Secrets
+	ShowSecrets bool
+}
+
+// Manifests compares the deployed manifests with the desired ones. Both are multi-document yaml, eg:
+// the ManifestData of a Release. Resources are matched by apiVersion, kind, namespace and name, and
+// compared after normalized, so the order of fields and comments do not matter.
+func Manifests(deployed, desired string, opts Options) (*Result, error) {
+	if opts.Context <= 0 {
+		opts.Context = 3
+	}
+
+	from, err := ParseManifests(deployed, opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("parse deployed manifests error: %s", err.Error())
+	}
+	to, err := ParseManifests(desired, opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("parse desired manifests error: %s", err.Error())
+	}
+
+	keys := map[ResourceKey]bool{}
+	for k := range from {
+		keys[k] = true
+	}
+	for k := range to {
+		keys[k] = true
+	}
+	sorted := make([]ResourceKey, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.APIVersion < b.APIVersion
+	})
+
+	result := &Result{}
+	for _, key := range sorted {
+		oldObj, newObj := from[key], to[key]
+		if key.Kind == "Secret" && !opts.ShowSecrets {
+			maskSecrets(oldObj, newObj)
+		}
+		oldText, err := marshal(oldObj)
+		if err != nil {
+			return nil, err
+		}
+		newText, err := marshal(newObj)
+		if err != nil {
+			return nil, err
+		}
+
+		item := ResourceDiff{ResourceKey: key}
+		switch {
+		case oldObj == nil:
+			item.Change = Added
+		case newObj == nil:
+			item.Change = Removed
+		case oldText == newText:
+			item.Change = Unchanged
+		default:
+			item.Change = Modified
+		}
+		if item.Change != Unchanged {
+			item.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        splitLines(oldText),
+				B:        splitLines(newText),
+				FromFile: "deployed/" + key.String(),
+				ToFile:   "desired/" + key.String(),
+				Context:  opts.Context,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.Resources = append(result.Resources, item)
+	}
+	return result, nil
+}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// ParseManifests parses the resources in the multi-document manifests by their keys, namespace is used
+// for the resources without a namespace. List kinds are not expanded.
+func ParseManifests(manifests, namespace string) (map[ResourceKey]map[string]interface{}, error) {
+	result := map[ResourceKey]map[string]interface{}{}
+	for _, doc := range documentSeparator.Split(manifests, -1) {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+
+		key := ResourceKey{Namespace: namespace}
+		key.APIVersion, _ = obj["apiVersion"].(string)
+		key.Kind, _ = obj["kind"].(string)
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			key.Name, _ = metadata["name"].(string)
+			if ns, _ := metadata["namespace"].(string); ns != "" {
+				key.Namespace = ns
+			}
+		}
+		if key.Kind == "" || key.Name == "" {
+			return nil, fmt.Errorf("resource without kind or name: %s", strings.TrimSpace(doc))
+		}
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("duplicated resource %s", key)
+		}
+		result[key] = obj
+	}
+	return result, nil
+}
+
+func marshal(obj map[string]interface{}) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// splitLines splits the text into lines with the line endings, difflib.SplitLines adds an extra empty
+// line for texts end with a line ending
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// maskSecrets replaces the values in data and stringData of the Secrets, values changed are masked
+// differently on both sides, so the diff still shows which keys changed
+func maskSecrets(old, desired map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		oldData, _ := old[field].(map[string]interface{})
+		desiredData, _ := desired[field].(map[string]interface{})
+		for k, v := range oldData {
+			if dv, ok := desiredData[k]; ok && fmt.Sprint(dv) == fmt.Sprint(v) {
+				oldData[k] = "***"
+			} else {
+				oldData[k] = "*** (before)"
+			}
+		}
+		for k := range desiredData {
+			if oldData[k] == "***" {
+				desiredData[k] = "***"
+			} else {
+				desiredData[k] = "*** (after)"
+			}
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/release"
+)
+
+const deployed = `# Source: nginx/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: web
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: removed
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: nginx
+data:
+  password: b2xk
+  user: YWRtaW4=
+stringData:
+  token: old
+`
+
+const desired = `apiVersion: v1
+kind: Service
+metadata:
+  # the order of fields and comments do not matter
+  name: nginx
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  namespace: web
+  name: nginx
+spec:
+  replicas: 2
+---
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: added
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: nginx
+data:
+  password: bmV3
+  user: YWRtaW4=
+stringData:
+  token: new
+`
+
+func TestParseManifests(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests string
+		keys      []ResourceKey
+		err       string
+	}{
+		{name: "empty"},
+		{
+			name:      "default namespace",
+			manifests: deployed,
+			keys: []ResourceKey{
+				{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: "nginx"},
+				{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "web", Name: "nginx"},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "removed"},
+				{APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "nginx"},
+			},
+		},
+		{name: "without name", manifests: "apiVersion: v1\nkind: Service\nmetadata: {}", err: "resource without kind or name"},
+		{name: "without kind", manifests: "apiVersion: v1\nmetadata: {name: nginx}", err: "resource without kind or name"},
+		{name: "duplicated", manifests: "kind: Service\nmetadata: {name: nginx}\n---\nkind: Service\nmetadata: {name: nginx, namespace: default}", err: "duplicated resource default/Service/nginx"},
+		{name: "invalid", manifests: "kind: [", err: "yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseManifests(tt.manifests, "default")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("expect error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result) != len(tt.keys) {
+				t.Fatalf("expect %d resources, got %d", len(tt.keys), len(result))
+			}
+			for _, key := range tt.keys {
+				if _, ok := result[key]; !ok {
+					t.Errorf("expect resource %s", key)
+				}
+			}
+		})
+	}
+}
+
+func TestManifests(t *testing.T) {
+	result, err := Manifests(deployed, desired, Options{Namespace: "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := map[string]ChangeType{}
+	for _, item := range result.Resources {
+		changes[item.String()] = item.Change
+		if (item.Change == Unchanged) != (item.Diff == "") {
+			t.Errorf("expect diff only for changed %s, got %q", item, item.Diff)
+		}
+	}
+	expect := map[string]ChangeType{
+		"default/ConfigMap/added":   Added,
+		"default/ConfigMap/removed": Removed,
+		"web/Deployment/nginx":      Modified,
+		"default/Secret/nginx":      Modified,
+		"default/Service/nginx":     Unchanged,
+	}
+	if !reflect.DeepEqual(changes, expect) {
+		t.Errorf("expect changes %v, got %v", expect, changes)
+	}
+	if !result.HasChanges() || len(result.Changed()) != 4 {
+		t.Errorf("expect 4 changed resources, got %v", result.Changed())
+	}
+
+	// sorted by kind, namespace and name
+	var kinds []string
+	for _, item := range result.Resources {
+		kinds = append(kinds, item.Kind+"/"+item.Name)
+	}
+	sorted := []string{"ConfigMap/added", "ConfigMap/removed", "Deployment/nginx", "Secret/nginx", "Service/nginx"}
+	if !reflect.DeepEqual(kinds, sorted) {
+		t.Errorf("expect order %v, got %v", sorted, kinds)
+	}
+
+	diff := result.String()
+	for _, line := range []string{"--- deployed/web/Deployment/nginx", "+++ desired/web/Deployment/nginx", "-  replicas: 1", "+  replicas: 2"} {
+		if !strings.Contains(diff, line+"\n") {
+			t.Errorf("expect line %q in diff:\n%s", line, diff)
+		}
+	}
+
+	unchanged, err := Manifests(deployed, deployed, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unchanged.HasChanges() || unchanged.String() != "" {
+		t.Errorf("expect no changes, got %s", unchanged)
+	}
+
+	if _, err := Manifests("kind: [", desired, Options{}); err == nil || !strings.Contains(err.Error(), "parse deployed manifests error") {
+		t.Errorf("expect deployed error, got %v", err)
+	}
+	if _, err := Manifests(deployed, "kind: [", Options{}); err == nil || !strings.Contains(err.Error(), "parse desired manifests error") {
+		t.Errorf("expect desired error, got %v", err)
+	}
+}
+
+func TestManifestsSecrets(t *testing.T) {
+	tests := []struct {
+		name        string
+		deployed    string
+		desired     string
+		showSecrets bool
+		contains    []string
+		excludes    []string
+	}{
+		{
+			name:     "masked",
+			deployed: deployed,
+			desired:  desired,
+			contains: []string{"-  password: '*** (before)'", "+  password: '*** (after)'", "   user: '***'", "-  token: '*** (before)'", "+  token: '*** (after)'"},
+			excludes: []string{"b2xk", "bmV3", "YWRtaW4=", "old", "new"},
+		},
+		{
+			name:        "shown",
+			deployed:    deployed,
+			desired:     desired,
+			showSecrets: true,
+			contains:    []string{"-  password: b2xk", "+  password: bmV3", "   user: YWRtaW4=", "-  token: old", "+  token: new"},
+		},
+		{
+			name:     "added masked",
+			desired:  "kind: Secret\nmetadata: {name: nginx}\ndata: {password: bmV3}",
+			contains: []string{"+  password: '*** (after)'"},
+			excludes: []string{"bmV3"},
+		},
+		{
+			name:     "removed masked",
+			deployed: "kind: Secret\nmetadata: {name: nginx}\nstringData: {token: old}",
+			contains: []string{"-  token: '*** (before)'"},
+			excludes: []string{"old"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Manifests(tt.deployed, tt.desired, Options{Namespace: "default", ShowSecrets: tt.showSecrets})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var diff string
+			for _, item := range result.Changed() {
+				if item.Kind == "Secret" {
+					diff = item.Diff
+				}
+			}
+			for _, line := range tt.contains {
+				if !strings.Contains(diff, line+"\n") {
+					t.Errorf("expect line %q in diff:\n%s", line, diff)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(diff, s) {
+					t.Errorf("expect %q masked in diff:\n%s", s, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestLatestDeployed(t *testing.T) {
+	newRelease := func(name string, version int, status release.Status) *v1beta1.Release {
+		rel := &v1beta1.Release{}
+		rel.Spec.Name, rel.Spec.Version, rel.Status.Status = name, version, status
+		return rel
+	}
+	releases := []*v1beta1.Release{
+		newRelease("nginx", 1, release.StatusSuperseded),
+		newRelease("nginx", 3, release.StatusDeployed),
+		newRelease("nginx", 2, release.StatusDeployed),
+		newRelease("nginx", 4, release.StatusFailed),
+		newRelease("redis", 5, release.StatusDeployed),
+	}
+	if rel := LatestDeployed(releases, "nginx"); rel != releases[1] {
+		t.Errorf("expect version 3, got %v", rel)
+	}
+	if rel := LatestDeployed(releases, "mysql"); rel != nil {
+		t.Errorf("expect nil, got %v", rel)
+	}
+}
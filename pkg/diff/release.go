@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/render"
+	"github.com/alauda/helm-crds/pkg/values"
+	"helm.sh/helm/pkg/release"
+)
+
+// LatestDeployed returns the deployed Release of the name with the highest version, nil if not found
+func LatestDeployed(releases []*v1beta1.Release, name string) *v1beta1.Release {
+	var latest *v1beta1.Release
+	for _, rel := range releases {
+		if rel.Spec.Name != name || rel.Status.Status != release.StatusDeployed {
+			continue
+		}
+		if latest == nil || rel.Spec.Version > latest.Spec.Version {
+			latest = rel
+		}
+	}
+	return latest
+}
+
+// HelmRequest renders the HelmRequest and compares it with the latest deployed Release in releases. If
+// there is no deployed Release, all resources are added.
+func HelmRequest(ctx context.Context, hr *v1beta1.HelmRequest, releases []*v1beta1.Release, charts values.ChartLoader,
+	resolver values.Resolver, opts Options) (*Result, error) {
+	deployed := LatestDeployed(releases, hr.GetReleaseName())
+
+	desired, err := render.RenderWithOptions(ctx, hr, charts, resolver, render.Options{IsUpgrade: deployed != nil})
+	if err != nil {
+		return nil, fmt.Errorf("render HelmRequest %s/%s error: %s", hr.Namespace, hr.Name, err.Error())
+	}
+
+	if opts.Namespace == "" {
+		opts.Namespace = hr.GetReleaseNamespace()
+	}
+	var manifest string
+	if deployed != nil {
+		manifest = deployed.Spec.ManifestData
+	}
+	return Manifests(manifest, desired.Manifest(), opts)
+}
@@ -0,0 +1,499 @@
+// Package drift detects the drift between the live objects in the cluster and the manifests of the
+// deployed Release, and optionally corrects it.
+package drift
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/diff"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog"
+)
+
+const (
+	// ReasonNoDrift is the reason of the Drifted condition when nothing drifted
+	ReasonNoDrift = "InSync"
+	// ReasonDrifted is the reason of the Drifted condition when some objects drifted
+	ReasonDrifted = "ObjectsDrifted"
+	// ReasonCorrected is the reason of the Drifted condition when the drift is corrected
+	ReasonCorrected = "DriftCorrected"
+
+	// maxSummaryResources is the max number of resources listed in the summary
+	maxSummaryResources = 5
+
+	// mergeKey is the key the lists of objects are matched by, eg: containers, volumes and env
+	mergeKey = "name"
+)
+
+// ignoredFields are the fields managed by the apiserver or controllers, they are never reported
+var ignoredFields = []string{
+	"status",
+	"metadata.creationTimestamp",
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.generation",
+	"metadata.selfLink",
+	"metadata.managedFields",
+	"metadata.ownerReferences",
+	"metadata.finalizers",
+	"metadata.annotations.kubectl.kubernetes.io/last-applied-configuration",
+	"metadata.annotations.deployment.kubernetes.io/revision",
+}
+
+// quantityMapRegex matches the paths of the maps whose values are quantities: resources of containers
+// and PersistentVolumeClaims, overhead of Pods, hard of ResourceQuotas, capacity of PersistentVolumes
+// and limits of LimitRanges
+var quantityMapRegex = regexp.MustCompile(`(^|\.)resources\.(limits|requests)$|(^|\.)overhead$|^spec\.(hard|capacity)$|^spec\.limits\[\d+\]\.(max|min|default|defaultRequest|maxLimitRequestRatio)$`)
+
+// FieldDrift is a field differs from the Release
+type FieldDrift struct {
+	// Path is the path of the field, eg: spec.template.spec.containers[0].image
+	Path string `json:"path"`
+	// Expected is the value in the Release, Actual is the live value. They are masked for Secrets
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// ResourceDrift is the drift of a resource
+type ResourceDrift struct {
+	diff.ResourceKey `json:",inline"`
+	// Missing means the object is not found in the cluster
+	Missing bool         `json:"missing,omitempty"`
+	Fields  []FieldDrift `json:"fields,omitempty"`
+
+	desired *unstructured.Unstructured
+	mapping *meta.RESTMapping
+}
+
+// Report is the result of the drift detection
+type Report struct {
+	// Release is the name of the Release CR checked
+	Release string `json:"release"`
+	// Resources are the drifted resources
+	Resources []ResourceDrift `json:"resources,omitempty"`
+}
+
+// Drifted checks whether any resource drifted
+func (r *Report) Drifted() bool {
+	return len(r.Resources) > 0
+}
+
+// Summary returns a short description of the drifted resources
+func (r *Report) Summary() string {
+	if !r.Drifted() {
+		return "no drift found"
+	}
+	var items []string
+	for i, item := range r.Resources {
+		if i == maxSummaryResources {
+			items = append(items, fmt.Sprintf("and %d more", len(r.Resources)-i))
+			break
+		}
+		if item.Missing {
+			items = append(items, fmt.Sprintf("%s(missing)", item.ResourceKey))
+			continue
+		}
+		var paths []string
+		for _, f := range item.Fields {
+			paths = append(paths, f.Path)
+		}
+		items = append(items, fmt.Sprintf("%s(%s)", item.ResourceKey, strings.Join(paths, ", ")))
+	}
+	return fmt.Sprintf("%d resources drifted from release %s: %s", len(r.Resources), r.Release, strings.Join(items, "; "))
+}
+
+// Detector detects the drift of Releases in a cluster
+type Detector struct {
+	Client dynamic.Interface
+	Mapper meta.RESTMapper
+}
+
+// NewDetector creates a Detector
+func NewDetector(client dynamic.Interface, mapper meta.RESTMapper) *Detector {
+	return &Detector{Client: client, Mapper: mapper}
+}
+
+// Detect compares the objects in the ManifestData of the Release with the live ones. Only the fields in
+// the manifests are compared, so the fields defaulted by the apiserver are not reported, and the fields
+// managed by the apiserver are ignored. namespace is used for the objects without a namespace, usually
+// the namespace of the release.
+func (d *Detector) Detect(rel *v1beta1.Release, namespace string) (*Report, error) {
+	resources, err := diff.ParseManifests(rel.Spec.ManifestData, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifests of release %s error: %s", rel.Name, err.Error())
+	}
+
+	keys := make([]diff.ResourceKey, 0, len(resources))
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	report := &Report{Release: rel.Name}
+	for _, key := range keys {
+		desired := &unstructured.Unstructured{Object: normalize(resources[key]).(map[string]interface{})}
+		gv, err := schema.ParseGroupVersion(key.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apiVersion of %s: %s", key, err.Error())
+		}
+		mapping, err := d.Mapper.RESTMapping(gv.WithKind(key.Kind).GroupKind(), gv.Version)
+		if err != nil {
+			return nil, fmt.Errorf("find resource of %s error: %s", key, err.Error())
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			key.Namespace = ""
+		}
+		desired.SetNamespace(key.Namespace)
+		if key.Kind == "Secret" {
+			foldStringData(desired.Object)
+		}
+
+		live, err := d.resource(mapping, key.Namespace).Get(key.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			report.Resources = append(report.Resources, ResourceDrift{ResourceKey: key, Missing: true, desired: desired, mapping: mapping})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get %s error: %s", key, err.Error())
+		}
+
+		fields := compare("", desired.Object, live.Object, nil)
+		if len(fields) == 0 {
+			continue
+		}
+		if key.Kind == "Secret" {
+			for i := range fields {
+				fields[i].Expected, fields[i].Actual = "***", "***"
+			}
+		}
+		report.Resources = append(report.Resources, ResourceDrift{ResourceKey: key, Fields: fields, desired: desired, mapping: mapping})
+	}
+	return report, nil
+}
+
+// Correct patches the drifted objects in the report with the manifests of the Release, and creates the
+// missing ones. It only fixes the fields in the manifests, other changes are kept. Built-in kinds are
+// patched with strategic merge patches, so the lists with merge keys, eg: containers, are merged by the
+// keys. Custom resources do not support it, their lists in the manifests replace the live ones.
+func (d *Detector) Correct(report *Report) error {
+	for _, item := range report.Resources {
+		client := d.resource(item.mapping, item.Namespace)
+		if item.Missing {
+			klog.Infof("create missing %s of release %s", item.ResourceKey, report.Release)
+			if _, err := client.Create(item.desired, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("create %s error: %s", item.ResourceKey, err.Error())
+			}
+			continue
+		}
+
+		klog.Infof("correct drifted %s of release %s", item.ResourceKey, report.Release)
+		data, err := json.Marshal(item.desired.Object)
+		if err != nil {
+			return err
+		}
+		if _, err := client.Patch(item.Name, patchType(item.mapping), data, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("patch %s error: %s", item.ResourceKey, err.Error())
+		}
+	}
+	return nil
+}
+
+// patchType returns the strategic merge patch for the kinds known by the kubernetes scheme, and the
+// json merge patch for others
+func patchType(mapping *meta.RESTMapping) types.PatchType {
+	if scheme.Scheme.Recognizes(mapping.GroupVersionKind) {
+		return types.StrategicMergePatchType
+	}
+	return types.MergePatchType
+}
+
+func (d *Detector) resource(mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return d.Client.Resource(mapping.Resource).Namespace(namespace)
+	}
+	return d.Client.Resource(mapping.Resource)
+}
+
+// Check detects the drift of the Release according to the DriftPolicy of the HelmRequest, corrects it if
+// the policy is DriftPolicyCorrect, and sets the Drifted condition. Returns nil report if the policy is
+// DriftPolicyIgnore.
+func (d *Detector) Check(hr *v1beta1.HelmRequest, rel *v1beta1.Release, now time.Time) (*Report, error) {
+	policy := hr.GetDriftPolicy()
+	if policy == v1beta1.DriftPolicyIgnore {
+		hr.Status.RemoveCondition(v1beta1.HelmRequestDrifted)
+		return nil, nil
+	}
+
+	report, err := d.Detect(rel, hr.GetReleaseNamespace())
+	if err != nil {
+		return nil, err
+	}
+	corrected := false
+	if policy == v1beta1.DriftPolicyCorrect && report.Drifted() {
+		if err := d.Correct(report); err != nil {
+			SetCondition(hr, report, false, now)
+			return report, err
+		}
+		corrected = true
+	}
+	SetCondition(hr, report, corrected, now)
+	return report, nil
+}
+
+// SetCondition sets the Drifted condition of the HelmRequest by the report. corrected means the drift in
+// the report has been corrected.
+func SetCondition(hr *v1beta1.HelmRequest, report *Report, corrected bool, now time.Time) {
+	c := v1beta1.HelmRequestCondition{
+		Type:    v1beta1.HelmRequestDrifted,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonNoDrift,
+		Message: report.Summary(),
+	}
+	switch {
+	case report.Drifted() && corrected:
+		c.Reason = ReasonCorrected
+	case report.Drifted():
+		c.Status = v1.ConditionTrue
+		c.Reason = ReasonDrifted
+	}
+	hr.Status.SetCondition(c, now)
+}
+
+// compare returns the fields in desired differ from live, the fields only in live are ignored. The lists
+// of objects with names are compared by the names, and the items only in live are ignored too, eg: the
+// sidecar containers injected. The paths of the items are by their indexes in desired.
+func compare(path string, desired, live interface{}, result []FieldDrift) []FieldDrift {
+	if isIgnored(path) {
+		return result
+	}
+
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return append(result, newFieldDrift(path, desired, live))
+		}
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			lv, ok := l[k]
+			if !ok {
+				// null and empty values in manifests are usually dropped by the apiserver
+				if isEmpty(d[k]) {
+					continue
+				}
+				result = append(result, newFieldDrift(p, d[k], nil))
+				continue
+			}
+			if isQuantity(path, k) {
+				if !equalQuantity(d[k], lv) {
+					result = append(result, newFieldDrift(p, d[k], lv))
+				}
+				continue
+			}
+			result = compare(p, d[k], lv, result)
+		}
+		return result
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return append(result, newFieldDrift(path, desired, live))
+		}
+		if names, ok := itemNames(d); ok {
+			if liveNames, ok := itemNames(l); ok {
+				items := make(map[string]interface{}, len(l))
+				for i, name := range liveNames {
+					items[name] = l[i]
+				}
+				for i, name := range names {
+					p := fmt.Sprintf("%s[%d]", path, i)
+					lv, ok := items[name]
+					if !ok {
+						result = append(result, newFieldDrift(p, d[i], nil))
+						continue
+					}
+					result = compare(p, d[i], lv, result)
+				}
+				return result
+			}
+		}
+		if len(l) != len(d) {
+			return append(result, newFieldDrift(path, desired, live))
+		}
+		for i := range d {
+			result = compare(fmt.Sprintf("%s[%d]", path, i), d[i], l[i], result)
+		}
+		return result
+	}
+
+	if !reflect.DeepEqual(normalize(desired), normalize(live)) {
+		return append(result, newFieldDrift(path, desired, live))
+	}
+	return result
+}
+
+// itemNames returns the names of the items in the list, false if the list is empty, any item is not an
+// object with a name or the names are duplicated
+func itemNames(list []interface{}) ([]string, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	names := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := obj[mergeKey].(string)
+		if !ok || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, true
+}
+
+// foldStringData moves stringData of a Secret into data, as the apiserver does
+func foldStringData(obj map[string]interface{}) {
+	stringData, ok := obj["stringData"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	data, ok := obj["data"].(map[string]interface{})
+	if !ok {
+		data = map[string]interface{}{}
+	}
+	for k, v := range stringData {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+	}
+	obj["data"] = data
+	delete(obj, "stringData")
+}
+
+func isIgnored(path string) bool {
+	for _, f := range ignoredFields {
+		if path == f {
+			return true
+		}
+	}
+	return false
+}
+
+// isQuantity returns whether the field key of the map at path is a resource.Quantity, eg: the values
+// in resources.limits of containers
+func isQuantity(path, key string) bool {
+	if key == "sizeLimit" {
+		return strings.HasSuffix(path, "emptyDir")
+	}
+	return quantityMapRegex.MatchString(path)
+}
+
+// equalQuantity compares two quantities, they may be numbers or strings in different formats, eg: 1
+// and "1000m", "1Gi" and 1073741824. Values can't be parsed are compared as they are.
+func equalQuantity(a, b interface{}) bool {
+	qa, err := parseQuantity(a)
+	if err != nil {
+		return reflect.DeepEqual(normalize(a), normalize(b))
+	}
+	qb, err := parseQuantity(b)
+	if err != nil {
+		return reflect.DeepEqual(normalize(a), normalize(b))
+	}
+	return qa.Cmp(qb) == 0
+}
+
+func parseQuantity(v interface{}) (resource.Quantity, error) {
+	switch t := normalize(v).(type) {
+	case string:
+		return resource.ParseQuantity(t)
+	case float64:
+		return resource.ParseQuantity(strconv.FormatFloat(t, 'f', -1, 64))
+	}
+	return resource.Quantity{}, fmt.Errorf("%v is not a quantity", v)
+}
+
+func isEmpty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(t) == 0
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// normalize converts the numbers to float64, so numbers parsed from yaml and json are comparable
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			out[k] = normalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			out[i] = normalize(item)
+		}
+		return out
+	case int:
+		return float64(t)
+	case int32:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case float32:
+		return float64(t)
+	}
+	return v
+}
+
+func newFieldDrift(path string, expected, actual interface{}) FieldDrift {
+	return FieldDrift{Path: path, Expected: format(expected), Actual: format(actual)}
+}
+
+func format(v interface{}) string {
+	if v == nil {
+		return "<none>"
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,482 @@
+package drift
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func parse(t *testing.T, s string) map[string]interface{} {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(s), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func resources(limits, requests string) string {
+	return `
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        resources:
+          limits:
+            ` + limits + `
+          requests:
+            ` + requests + `
+`
+}
+
+func TestCompareQuantity(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		live    string
+		drifted []string
+	}{
+		{
+			name:    "int and string cpu",
+			desired: resources("cpu: 1", "cpu: 1"),
+			live:    resources(`cpu: "1"`, "cpu: 1000m"),
+		},
+		{
+			name:    "memory in bytes",
+			desired: resources("memory: 1Gi", "memory: 512Mi"),
+			live:    resources("memory: 1073741824", `memory: "536870912"`),
+		},
+		{
+			name:    "decimal cpu",
+			desired: resources("cpu: 0.5", "cpu: 100m"),
+			live:    resources("cpu: 500m", "cpu: 0.1"),
+		},
+		{
+			name:    "changed",
+			desired: resources("cpu: 1", "memory: 1Gi"),
+			live:    resources("cpu: 2", "memory: 1G"),
+			drifted: []string{
+				"spec.template.spec.containers[0].resources.limits.cpu",
+				"spec.template.spec.containers[0].resources.requests.memory",
+			},
+		},
+		{
+			name:    "not a quantity",
+			desired: resources("cpu: 1", "cpu: foo"),
+			live:    resources("cpu: 1", "cpu: bar"),
+			drifted: []string{"spec.template.spec.containers[0].resources.requests.cpu"},
+		},
+		{
+			name:    "other fields are not quantities",
+			desired: "spec:\n  replicas: 1\n  resources:\n    limits:\n      cpu: 1",
+			live:    "spec:\n  replicas: \"1\"\n  resources:\n    limits:\n      cpu: \"1\"",
+			drifted: []string{"spec.replicas"},
+		},
+		{
+			name:    "resource quota",
+			desired: "spec:\n  hard:\n    requests.cpu: 2\n    requests.memory: 2Gi\n    pods: 10",
+			live:    "spec:\n  hard:\n    requests.cpu: \"2\"\n    requests.memory: 2048Mi\n    pods: \"10\"",
+		},
+		{
+			name:    "empty dir",
+			desired: "spec:\n  volumes:\n  - name: cache\n    emptyDir:\n      sizeLimit: 1Gi",
+			live:    "spec:\n  volumes:\n  - name: cache\n    emptyDir:\n      sizeLimit: 1024Mi",
+		},
+		{
+			name:    "limit range",
+			desired: "spec:\n  limits:\n  - type: Container\n    max:\n      cpu: 2\n    default:\n      memory: 1Gi",
+			live:    "spec:\n  limits:\n  - type: Container\n    max:\n      cpu: 2000m\n    default:\n      memory: 1073741824",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := normalize(parse(t, tt.desired))
+			result := compare("", desired, parse(t, tt.live), nil)
+			if len(result) != len(tt.drifted) {
+				t.Fatalf("expect %d drifted fields, got %v", len(tt.drifted), result)
+			}
+			for i, path := range tt.drifted {
+				if result[i].Path != path {
+					t.Errorf("expect %s drifted, got %s", path, result[i].Path)
+				}
+			}
+		})
+	}
+}
+
+const manifests = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.17
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: nginx
+stringData:
+  password: s3cret
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: nginx
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get]
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: nginx
+data:
+  key: value
+---
+apiVersion: example.com/v1
+kind: Foo
+metadata:
+  name: nginx
+spec:
+  items: [a, b]
+`
+
+// liveObjects are drifted from the manifests, except the ConfigMap is missing
+var liveObjects = []string{`apiVersion: apps/v1
+kind: Deployment
+metadata: {name: nginx, namespace: default, uid: "1", resourceVersion: "2"}
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: istio-proxy
+        image: istio/proxyv2
+      - name: nginx
+        image: nginx:1.16
+        imagePullPolicy: IfNotPresent
+status:
+  replicas: 1`,
+	`apiVersion: v1
+kind: Secret
+metadata: {name: nginx, namespace: default}
+data:
+  password: b2xk
+  extra: ZXh0cmE=`,
+	`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata: {name: nginx}
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get, list]`,
+	`apiVersion: example.com/v1
+kind: Foo
+metadata: {name: nginx, namespace: default}
+spec:
+  items: [a]
+  other: kept`,
+}
+
+func newMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Foo"}, meta.RESTScopeNamespace)
+	return mapper
+}
+
+// newClient creates a fake dynamic client applies strategic merge patches by the types in the kubernetes
+// scheme as the apiserver does, the fake one only works with typed objects
+func newClient(t *testing.T, objects ...string) *dynamicfake.FakeDynamicClient {
+	s := runtime.NewScheme()
+	tracker := clienttesting.NewObjectTracker(s, serializer.NewCodecFactory(s).UniversalDecoder())
+	for _, obj := range objects {
+		if err := tracker.Add(&unstructured.Unstructured{Object: parse(t, obj)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := dynamicfake.NewSimpleDynamicClient(s)
+	client.ReactionChain = nil
+	client.AddReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		if patch.GetPatchType() != types.StrategicMergePatchType {
+			return false, nil, nil
+		}
+		obj, err := tracker.Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		typed, err := scheme.Scheme.New(obj.GetObjectKind().GroupVersionKind())
+		if err != nil {
+			return true, nil, err
+		}
+		old, err := json.Marshal(obj)
+		if err != nil {
+			return true, nil, err
+		}
+		data, err := strategicpatch.StrategicMergePatch(old, patch.GetPatch(), typed)
+		if err != nil {
+			return true, nil, err
+		}
+		patched := &unstructured.Unstructured{}
+		if err := patched.UnmarshalJSON(data); err != nil {
+			return true, nil, err
+		}
+		return true, patched, tracker.Update(patch.GetResource(), patched, patch.GetNamespace())
+	})
+	client.AddReactor("*", "*", clienttesting.ObjectReaction(tracker))
+	return client
+}
+
+func newRelease() *v1beta1.Release {
+	rel := &v1beta1.Release{}
+	rel.Name = "nginx.v1"
+	rel.Spec.ManifestData = manifests
+	return rel
+}
+
+func TestDetect(t *testing.T) {
+	detector := NewDetector(newClient(t, liveObjects...), newMapper())
+	report, err := detector.Detect(newRelease(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string][]FieldDrift{
+		"ClusterRole/nginx":       {{Path: "rules[0].verbs", Expected: `["get"]`, Actual: `["get","list"]`}},
+		"default/ConfigMap/nginx": nil,
+		"default/Deployment/nginx": {
+			{Path: "spec.replicas", Expected: "2", Actual: "1"},
+			{Path: "spec.template.spec.containers[0].image", Expected: "nginx:1.17", Actual: "nginx:1.16"},
+		},
+		"default/Foo/nginx":    {{Path: "spec.items", Expected: `["a","b"]`, Actual: `["a"]`}},
+		"default/Secret/nginx": {{Path: "data.password", Expected: "***", Actual: "***"}},
+	}
+	got := map[string][]FieldDrift{}
+	for _, item := range report.Resources {
+		got[item.String()] = item.Fields
+		if item.Missing != (item.Kind == "ConfigMap") {
+			t.Errorf("expect only the ConfigMap missing, got %s missing %v", item.ResourceKey, item.Missing)
+		}
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect drift %v, got %v", expect, got)
+	}
+	if !report.Drifted() || !strings.HasPrefix(report.Summary(), "5 resources drifted from release nginx.v1: ClusterRole/nginx(rules[0].verbs); default/ConfigMap/nginx(missing)") {
+		t.Errorf("unexpected summary %s", report.Summary())
+	}
+
+	if _, err := detector.Detect(&v1beta1.Release{Spec: v1beta1.ReleaseSpec{ManifestData: "kind: Bar\nmetadata: {name: bar}"}}, "default"); err == nil {
+		t.Error("expect error for unknown kind")
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	client := newClient(t, liveObjects...)
+	detector := NewDetector(client, newMapper())
+	report, err := detector.Detect(newRelease(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.ClearActions()
+	if err := detector.Correct(report); err != nil {
+		t.Fatal(err)
+	}
+
+	patches := map[string]types.PatchType{}
+	for _, action := range client.Actions() {
+		switch a := action.(type) {
+		case clienttesting.PatchAction:
+			patches[a.GetNamespace()+"/"+a.GetResource().Resource] = a.GetPatchType()
+		case clienttesting.CreateAction:
+			if a.GetNamespace() != "default" || a.GetResource().Resource != "configmaps" {
+				t.Errorf("expect configmap created in default, got %s in %s", a.GetResource().Resource, a.GetNamespace())
+			}
+		}
+	}
+	expect := map[string]types.PatchType{
+		"default/deployments": types.StrategicMergePatchType,
+		"default/secrets":     types.StrategicMergePatchType,
+		"/clusterroles":       types.StrategicMergePatchType,
+		"default/foos":        types.MergePatchType,
+	}
+	if !reflect.DeepEqual(patches, expect) {
+		t.Errorf("expect patches %v, got %v", expect, patches)
+	}
+
+	report, err = detector.Detect(newRelease(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Drifted() {
+		t.Errorf("expect no drift after corrected, got %s", report.Summary())
+	}
+
+	// the fields and containers not in the manifests are kept
+	deploy, err := client.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace("default").Get("nginx", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers, _, _ := unstructured.NestedSlice(deploy.Object, "spec", "template", "spec", "containers")
+	if len(containers) != 2 || containers[0].(map[string]interface{})["name"] != "istio-proxy" ||
+		containers[1].(map[string]interface{})["imagePullPolicy"] != "IfNotPresent" {
+		t.Errorf("expect the sidecar and defaulted fields kept, got %v", containers)
+	}
+	secret, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}).Namespace("default").Get("nginx", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _, _ := unstructured.NestedStringMap(secret.Object, "data"); data["password"] != "czNjcmV0" || data["extra"] != "ZXh0cmE=" {
+		t.Errorf("expect password corrected and extra kept, got %v", data)
+	}
+	foo, err := client.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}).Namespace("default").Get("nginx", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other, _, _ := unstructured.NestedString(foo.Object, "spec", "other"); other != "kept" {
+		t.Errorf("expect spec.other kept, got %v", foo.Object)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		policy v1beta1.DriftPolicy
+		status v1.ConditionStatus
+		reason string
+		// patched means the objects are corrected
+		patched bool
+	}{
+		{policy: v1beta1.DriftPolicyIgnore},
+		{policy: "", status: v1.ConditionTrue, reason: ReasonDrifted},
+		{policy: v1beta1.DriftPolicyDetect, status: v1.ConditionTrue, reason: ReasonDrifted},
+		{policy: v1beta1.DriftPolicyCorrect, status: v1.ConditionFalse, reason: ReasonCorrected, patched: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			client := newClient(t, liveObjects...)
+			detector := NewDetector(client, newMapper())
+			hr := &v1beta1.HelmRequest{}
+			hr.Spec.DriftPolicy = tt.policy
+			hr.Spec.Namespace = "default"
+			hr.Status.SetCondition(v1beta1.HelmRequestCondition{Type: v1beta1.HelmRequestDrifted, Status: v1.ConditionTrue}, time.Now())
+
+			report, err := detector.Check(hr, newRelease(), time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := hr.Status.GetCondition(v1beta1.HelmRequestDrifted)
+			if tt.policy == v1beta1.DriftPolicyIgnore {
+				if report != nil || c != nil || len(client.Actions()) != 0 {
+					t.Errorf("expect nothing checked, got report %v, condition %v", report, c)
+				}
+				return
+			}
+			if c == nil || c.Status != tt.status || c.Reason != tt.reason || c.Message != report.Summary() {
+				t.Fatalf("expect condition %s with reason %s, got %v", tt.status, tt.reason, c)
+			}
+			patched := false
+			for _, action := range client.Actions() {
+				if action.GetVerb() == "patch" || action.GetVerb() == "create" {
+					patched = true
+				}
+			}
+			if patched != tt.patched {
+				t.Errorf("expect patched %v, got %v", tt.patched, patched)
+			}
+			if !tt.patched {
+				return
+			}
+
+			if _, err := detector.Check(hr, newRelease(), time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			if c := hr.Status.GetCondition(v1beta1.HelmRequestDrifted); c.Status != v1.ConditionFalse || c.Reason != ReasonNoDrift {
+				t.Errorf("expect condition false with reason %s, got %v", ReasonNoDrift, c)
+			}
+		})
+	}
+}
+
+func TestCompareLists(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		live    string
+		drifted []string
+	}{
+		{
+			name:    "by names",
+			desired: "containers:\n- {name: a, image: a}\n- {name: b, image: b}",
+			live:    "containers:\n- {name: sidecar, image: s}\n- {name: b, image: b}\n- {name: a, image: a}",
+		},
+		{
+			name:    "changed by names",
+			desired: "containers:\n- {name: a, image: a}\n- {name: b, image: b}",
+			live:    "containers:\n- {name: b, image: c}",
+			drifted: []string{"containers[0]", "containers[1].image"},
+		},
+		{
+			name:    "by indexes",
+			desired: "args: [a, b]",
+			live:    "args: [b, a]",
+			drifted: []string{"args[0]", "args[1]"},
+		},
+		{
+			name:    "length",
+			desired: "args: [a, b]",
+			live:    "args: [a, b, c]",
+			drifted: []string{"args"},
+		},
+		{
+			name:    "without names",
+			desired: "ports:\n- {containerPort: 80}",
+			live:    "ports:\n- {containerPort: 80}\n- {containerPort: 443}",
+			drifted: []string{"ports"},
+		},
+		{
+			name:    "duplicated names",
+			desired: "env:\n- {name: a, value: '1'}\n- {name: a, value: '2'}",
+			live:    "env:\n- {name: a, value: '2'}\n- {name: a, value: '1'}",
+			drifted: []string{"env[0].value", "env[1].value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := compare("", normalize(parse(t, tt.desired)), parse(t, tt.live), nil)
+			var paths []string
+			for _, f := range result {
+				paths = append(paths, f.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.drifted) {
+				t.Errorf("expect drifted %v, got %v", tt.drifted, paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/render"
+	"github.com/alauda/helm-crds/pkg/values"
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Format is the format of the GitOps manifest in a bundle
+type Format string
+
+const (
+	// FormatFlux is the HelmRelease of the flux helm operator
+	FormatFlux Format = "flux"
+	// FormatArgo is the Application of Argo CD
+	FormatArgo Format = "argo"
+)
+
+const (
+	// ReferenceFile is the file name of the chart reference in a bundle
+	ReferenceFile = "chart-reference.yaml"
+	// ValuesFile is the file name of the effective values in a bundle, except the values from Secrets
+	ValuesFile = "values.yaml"
+	// FluxFile is the file name of the flux HelmRelease in a bundle
+	FluxFile = "helmrelease.yaml"
+	// ArgoFile is the file name of the Argo CD Application in a bundle
+	ArgoFile = "application.yaml"
+
+	// defaultArgoNamespace is the namespace Argo CD watches Applications by default
+	defaultArgoNamespace = "argocd"
+	// defaultArgoServer is the in cluster api server of Argo CD
+	defaultArgoServer = "https://kubernetes.default.svc"
+)
+
+// BundleOptions are the options of exporting to a GitOps bundle
+type BundleOptions struct {
+	// Format is the format of the GitOps manifest, default to FormatFlux
+	Format Format
+	// ArgoNamespace is the namespace of the Argo CD Application, default to argocd
+	ArgoNamespace string
+	// ArgoProject is the project of the Argo CD Application, default to default
+	ArgoProject string
+	// ArgoServer is the destination server of the Argo CD Application, default to the in cluster server
+	ArgoServer string
+}
+
+// ChartSource is where the chart is pulled from outside of the platform
+type ChartSource struct {
+	// Type is the type of the ChartRepo, Chart or Git
+	Type v1beta1.ChartRepoType `json:"type"`
+	// Repository is the url of the helm repo or the git repo
+	Repository string `json:"repository"`
+	// Chart is the name of the chart
+	Chart string `json:"chart"`
+	// Version is the exact chart version of the HelmRequest
+	Version string `json:"version"`
+	// Path is the directory of the chart in the git repo, it's guessed as <source path>/<chart> since
+	// charts can be anywhere under the source path. Only for Git
+	Path string `json:"path,omitempty"`
+	// Ref is the commit the ChartRepo last synced, or the branch, tag or commit in the ChartRepo if it
+	// never synced. Only for Git
+	Ref string `json:"ref,omitempty"`
+}
+
+// Bundle is a HelmRequest exported for GitOps tools. Credentials of the repos and the values from
+// Secrets are not included.
+type Bundle struct {
+	Source ChartSource
+	// Values are the effective values except the ones from Secrets
+	Values map[string]interface{}
+	// SecretValues are the values from Secrets, they are referenced by the flux HelmRelease and dropped
+	// from the Argo CD Application
+	SecretValues []*v1.SecretKeySelector
+	// Warnings are the differences from the HelmRequest should be checked before applying the bundle
+	Warnings []string
+	// Files are the contents of the bundle by file name
+	Files map[string][]byte
+}
+
+// Write writes the files of the bundle into dir, dir is created if not exists
+func (b *Bundle) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b.Files[name], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToBundle exports the HelmRequest to a bundle, contains the chart reference, the effective values and
+// a GitOps manifest. The chart must be in a repo that can be reached without the platform, that's a
+// ChartRepo of Chart or Git type. Values from Secrets are never written in the bundle, the flux
+// HelmRelease references the Secrets by valuesFrom, and the Argo CD Application drops them with a
+// warning.
+func (e *Exporter) ToBundle(ctx context.Context, hr *v1beta1.HelmRequest, resolver values.Resolver, opts BundleOptions) (*Bundle, error) {
+	if opts.Format == "" {
+		opts.Format = FormatFlux
+	}
+
+	loader := &render.RepoLoader{Client: e.Client, KubeClient: e.KubeClient}
+	cr, cv, err := loader.Resolve(ctx, hr)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{Files: map[string][]byte{}}
+	source := ChartSource{
+		Type:    v1beta1.ChartRepoType(cr.Spec.Type),
+		Chart:   cv.Name,
+		Version: cv.Version,
+	}
+	switch source.Type {
+	case "", v1beta1.ChartRepoChart:
+		source.Type, source.Repository = v1beta1.ChartRepoChart, cr.Spec.URL
+	case v1beta1.ChartRepoGit:
+		source.Repository = cr.Spec.Source.URL
+		source.Path = path.Join(cr.Spec.Source.Path, cv.Name)
+		// GitOps tools can't pick a chart version from a git repo, pin the commit the version is synced
+		// from, so the bundle doesn't follow the branch
+		source.Ref = cr.Status.Revision
+		if source.Ref == "" {
+			source.Ref = cr.Spec.Source.Ref
+			ref := source.Ref
+			if ref == "" {
+				ref = "HEAD"
+			}
+			b.Warnings = append(b.Warnings, fmt.Sprintf("git repo %s has no synced commit, the bundle follows %s instead of chart version %s",
+				cr.Name, ref, cv.Version))
+		}
+	default:
+		return nil, fmt.Errorf("charts in %s repo %s can't be referenced by GitOps tools", cr.Spec.Type, cr.Name)
+	}
+	b.Source = source
+
+	// the values from Secrets are resolved by the GitOps tools or dropped, never written in the bundle
+	plain := hr.DeepCopy()
+	plain.Spec.ValuesFrom = nil
+	afterConfigMap := false
+	for _, src := range hr.Spec.ValuesFrom {
+		if src.SecretKeyRef == nil {
+			plain.Spec.ValuesFrom = append(plain.Spec.ValuesFrom, src)
+			afterConfigMap = afterConfigMap || src.ConfigMapKeyRef != nil
+			continue
+		}
+		b.SecretValues = append(b.SecretValues, src.SecretKeyRef)
+		switch {
+		case opts.Format == FormatArgo:
+			b.Warnings = append(b.Warnings, fmt.Sprintf("values from key %s of Secret %s are not exported, Argo CD Applications can't reference Secrets",
+				src.SecretKeyRef.Key, src.SecretKeyRef.Name))
+		case afterConfigMap:
+			b.Warnings = append(b.Warnings, fmt.Sprintf("values from key %s of Secret %s no longer override the values from the ConfigMaps before it, flux applies valuesFrom before values",
+				src.SecretKeyRef.Key, src.SecretKeyRef.Name))
+		}
+	}
+	vals, err := resolver.Resolve(ctx, plain)
+	if err != nil {
+		return nil, fmt.Errorf("resolve values error: %s", err.Error())
+	}
+	b.Values = vals
+
+	if b.Files[ReferenceFile], err = yaml.Marshal(source); err != nil {
+		return nil, err
+	}
+	if b.Files[ValuesFile], err = yaml.Marshal(vals); err != nil {
+		return nil, err
+	}
+
+	switch opts.Format {
+	case FormatFlux:
+		b.Files[FluxFile], err = yaml.Marshal(fluxHelmRelease(hr, &source, vals, b.SecretValues))
+	case FormatArgo:
+		b.Files[ArgoFile], err = yaml.Marshal(argoApplication(hr, &source, b.Files[ValuesFile], opts))
+	default:
+		return nil, fmt.Errorf("unknown bundle format %s", opts.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// fluxHelmRelease generates the HelmRelease of the flux helm operator, in the HelmRequest's namespace. The
+// values from Secrets are referenced by valuesFrom
+func fluxHelmRelease(hr *v1beta1.HelmRequest, source *ChartSource, vals map[string]interface{}, secrets []*v1.SecretKeySelector) map[string]interface{} {
+	chart := map[string]interface{}{}
+	if source.Type == v1beta1.ChartRepoGit {
+		chart["git"] = source.Repository
+		chart["path"] = source.Path
+		if source.Ref != "" {
+			chart["ref"] = source.Ref
+		}
+	} else {
+		chart["repository"] = source.Repository
+		chart["name"] = source.Chart
+		chart["version"] = source.Version
+	}
+
+	spec := map[string]interface{}{
+		"releaseName":     hr.GetReleaseName(),
+		"targetNamespace": hr.GetReleaseNamespace(),
+		"chart":           chart,
+	}
+	if len(vals) > 0 {
+		spec["values"] = vals
+	}
+	var valuesFrom []interface{}
+	for _, ref := range secrets {
+		secretKeyRef := map[string]interface{}{
+			"name":      ref.Name,
+			"namespace": hr.Namespace,
+			"key":       ref.Key,
+		}
+		if ref.Optional != nil {
+			secretKeyRef["optional"] = *ref.Optional
+		}
+		valuesFrom = append(valuesFrom, map[string]interface{}{"secretKeyRef": secretKeyRef})
+	}
+	if len(valuesFrom) > 0 {
+		spec["valuesFrom"] = valuesFrom
+	}
+	return map[string]interface{}{
+		"apiVersion": "helm.fluxcd.io/v1",
+		"kind":       "HelmRelease",
+		"metadata": map[string]interface{}{
+			"name":      hr.Name,
+			"namespace": hr.Namespace,
+		},
+		"spec": spec,
+	}
+}
+
+// argoApplication generates the Application of Argo CD, values are inlined as a yaml string
+func argoApplication(hr *v1beta1.HelmRequest, source *ChartSource, vals []byte, opts BundleOptions) map[string]interface{} {
+	if opts.ArgoNamespace == "" {
+		opts.ArgoNamespace = defaultArgoNamespace
+	}
+	if opts.ArgoProject == "" {
+		opts.ArgoProject = "default"
+	}
+	if opts.ArgoServer == "" {
+		opts.ArgoServer = defaultArgoServer
+	}
+
+	helm := map[string]interface{}{
+		"releaseName": hr.GetReleaseName(),
+	}
+	if string(vals) != "{}\n" {
+		helm["values"] = string(vals)
+	}
+	src := map[string]interface{}{
+		"repoURL": source.Repository,
+		"helm":    helm,
+	}
+	if source.Type == v1beta1.ChartRepoGit {
+		src["path"] = source.Path
+		if source.Ref != "" {
+			src["targetRevision"] = source.Ref
+		}
+	} else {
+		src["chart"] = source.Chart
+		src["targetRevision"] = source.Version
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"name":      hr.Name,
+			"namespace": opts.ArgoNamespace,
+		},
+		"spec": map[string]interface{}{
+			"project": opts.ArgoProject,
+			"source":  src,
+			"destination": map[string]interface{}{
+				"server":    opts.ArgoServer,
+				"namespace": hr.GetReleaseNamespace(),
+			},
+		},
+	}
+}
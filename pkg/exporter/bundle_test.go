@@ -0,0 +1,251 @@
+package exporter
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	"github.com/alauda/helm-crds/pkg/values"
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newRepoChart(repo string) *v1beta1.Chart {
+	return &v1beta1.Chart{
+		ObjectMeta: metav1.ObjectMeta{Name: v1beta1.ChartObjectName(repo, "nginx"), Namespace: "default"},
+		Spec: v1beta1.ChartSpec{Versions: []*v1beta1.ChartVersion{
+			{ChartMetadata: v1beta1.ChartMetadata{Name: "nginx", Version: "1.0.0"}},
+			{ChartMetadata: v1beta1.ChartMetadata{Name: "nginx", Version: "1.1.0"}},
+		}},
+		Status: v1beta1.ChartStatus{Repo: repo},
+	}
+}
+
+func newBundleExporter() *Exporter {
+	gitSource := &v1beta1.ChartRepoSource{URL: "https://git.example.com/charts.git", Path: "charts", Ref: "main"}
+	client := fake.NewSimpleClientset(
+		&v1beta1.ChartRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"},
+			Spec:       v1beta1.ChartRepoSpec{URL: "https://charts.example.com", Type: string(v1beta1.ChartRepoChart)},
+		},
+		&v1beta1.ChartRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: "synced", Namespace: "default"},
+			Spec:       v1beta1.ChartRepoSpec{URL: "http://captain/synced", Type: string(v1beta1.ChartRepoGit), Source: gitSource},
+			Status:     v1beta1.ChartRepoStatus{Revision: "3f2a1b"},
+		},
+		&v1beta1.ChartRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: "unsynced", Namespace: "default"},
+			Spec:       v1beta1.ChartRepoSpec{URL: "http://captain/unsynced", Type: string(v1beta1.ChartRepoGit), Source: gitSource},
+		},
+		&v1beta1.ChartRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: "svn", Namespace: "default"},
+			Spec: v1beta1.ChartRepoSpec{URL: "http://captain/svn", Type: string(v1beta1.ChartRepoSvn),
+				Source: &v1beta1.ChartRepoSource{URL: "svn://svn.example.com/charts"}},
+		},
+		newRepoChart("stable"), newRepoChart("synced"), newRepoChart("unsynced"), newRepoChart("svn"),
+	)
+	kubeClient := kubefake.NewSimpleClientset(
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: "default"},
+			Data:       map[string]string{"values.yaml": "image: {tag: '1.17'}\nreplicas: 1"},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+			Data:       map[string][]byte{"values.yaml": []byte("password: s3cret")},
+		},
+	)
+	return NewExporter(client, kubeClient)
+}
+
+func configMapValues() v1beta1.ValuesFromSource {
+	return v1beta1.ValuesFromSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "values"}, Key: "values.yaml",
+	}}
+}
+
+func secretValues() v1beta1.ValuesFromSource {
+	optional := true
+	return v1beta1.ValuesFromSource{SecretKeyRef: &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "creds"}, Key: "values.yaml", Optional: &optional,
+	}}
+}
+
+func TestToBundle(t *testing.T) {
+	tests := []struct {
+		name       string
+		chart      string
+		format     Format
+		valuesFrom []v1beta1.ValuesFromSource
+		source     ChartSource
+		// manifest is the expected GitOps manifest in yaml
+		manifest string
+		warnings []string
+		err      string
+	}{
+		{
+			name:       "flux",
+			chart:      "stable/nginx",
+			valuesFrom: []v1beta1.ValuesFromSource{secretValues(), configMapValues()},
+			source:     ChartSource{Type: v1beta1.ChartRepoChart, Repository: "https://charts.example.com", Chart: "nginx", Version: "1.1.0"},
+			manifest: `apiVersion: helm.fluxcd.io/v1
+kind: HelmRelease
+metadata: {name: nginx, namespace: default}
+spec:
+  releaseName: nginx
+  targetNamespace: web
+  chart: {repository: "https://charts.example.com", name: nginx, version: 1.1.0}
+  values: {image: {tag: "1.17"}, replicas: 2}
+  valuesFrom:
+  - secretKeyRef: {name: creds, namespace: default, key: values.yaml, optional: true}
+`,
+		},
+		{
+			name:       "flux secret after config map",
+			chart:      "stable/nginx",
+			valuesFrom: []v1beta1.ValuesFromSource{configMapValues(), secretValues()},
+			source:     ChartSource{Type: v1beta1.ChartRepoChart, Repository: "https://charts.example.com", Chart: "nginx", Version: "1.1.0"},
+			warnings:   []string{"values from key values.yaml of Secret creds no longer override the values from the ConfigMaps before it, flux applies valuesFrom before values"},
+		},
+		{
+			name:       "argo",
+			chart:      "stable/nginx",
+			format:     FormatArgo,
+			valuesFrom: []v1beta1.ValuesFromSource{configMapValues(), secretValues()},
+			source:     ChartSource{Type: v1beta1.ChartRepoChart, Repository: "https://charts.example.com", Chart: "nginx", Version: "1.1.0"},
+			manifest: `apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata: {name: nginx, namespace: argocd}
+spec:
+  project: default
+  source:
+    repoURL: "https://charts.example.com"
+    chart: nginx
+    targetRevision: 1.1.0
+    helm:
+      releaseName: nginx
+      values: |
+        image:
+          tag: "1.17"
+        replicas: 2
+  destination: {server: "https://kubernetes.default.svc", namespace: web}
+`,
+			warnings: []string{"values from key values.yaml of Secret creds are not exported, Argo CD Applications can't reference Secrets"},
+		},
+		{
+			name:   "git pinned",
+			chart:  "synced/nginx",
+			source: ChartSource{Type: v1beta1.ChartRepoGit, Repository: "https://git.example.com/charts.git", Chart: "nginx", Version: "1.1.0", Path: "charts/nginx", Ref: "3f2a1b"},
+			manifest: `apiVersion: helm.fluxcd.io/v1
+kind: HelmRelease
+metadata: {name: nginx, namespace: default}
+spec:
+  releaseName: nginx
+  targetNamespace: web
+  chart: {git: "https://git.example.com/charts.git", path: charts/nginx, ref: 3f2a1b}
+  values: {replicas: 2}
+`,
+		},
+		{
+			name:     "git not synced",
+			chart:    "unsynced/nginx",
+			format:   FormatArgo,
+			source:   ChartSource{Type: v1beta1.ChartRepoGit, Repository: "https://git.example.com/charts.git", Chart: "nginx", Version: "1.1.0", Path: "charts/nginx", Ref: "main"},
+			warnings: []string{"git repo unsynced has no synced commit, the bundle follows main instead of chart version 1.1.0"},
+		},
+		{name: "svn", chart: "svn/nginx", err: "charts in SVN repo svn can't be referenced by GitOps tools"},
+		{name: "unknown format", chart: "stable/nginx", format: "kustomize", err: "unknown bundle format kustomize"},
+	}
+	e := newBundleExporter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := newHelmRequest()
+			hr.Spec.Chart, hr.Spec.Version = tt.chart, "1.1.0"
+			hr.Spec.ValuesFrom = tt.valuesFrom
+			hr.Spec.Values = map[string]interface{}{"replicas": 2}
+
+			b, err := e.ToBundle(context.Background(), hr, values.NewClientResolver(e.KubeClient), BundleOptions{Format: tt.format})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expect error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(b.Source, tt.source) {
+				t.Errorf("expect source %+v, got %+v", tt.source, b.Source)
+			}
+			if !reflect.DeepEqual(b.Warnings, tt.warnings) {
+				t.Errorf("expect warnings %q, got %q", tt.warnings, b.Warnings)
+			}
+			if len(b.SecretValues) != len(tt.valuesFrom)/2 {
+				t.Errorf("expect %d secret values, got %v", len(tt.valuesFrom)/2, b.SecretValues)
+			}
+			for name, data := range b.Files {
+				if strings.Contains(string(data), "s3cret") {
+					t.Errorf("expect no values from secrets in %s, got %s", name, data)
+				}
+			}
+
+			reference := ChartSource{}
+			if err := yaml.Unmarshal(b.Files[ReferenceFile], &reference); err != nil || reference != tt.source {
+				t.Errorf("expect reference %+v, got %+v, %v", tt.source, reference, err)
+			}
+			if tt.manifest == "" {
+				return
+			}
+			file := FluxFile
+			if tt.format == FormatArgo {
+				file = ArgoFile
+			}
+			var expect, got interface{}
+			if err := yaml.Unmarshal([]byte(tt.manifest), &expect); err != nil {
+				t.Fatal(err)
+			}
+			if err := yaml.Unmarshal(b.Files[file], &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, expect) {
+				t.Errorf("expect %s:\n%s\ngot:\n%s", file, tt.manifest, b.Files[file])
+			}
+		})
+	}
+}
+
+func TestBundleWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b, err := newBundleExporter().ToBundle(context.Background(), newHelmRequest(), values.NewClientResolver(kubefake.NewSimpleClientset()), BundleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "nginx")
+	if err := b.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{ReferenceFile, ValuesFile, FluxFile} {
+		data, err := ioutil.ReadFile(filepath.Join(out, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != string(b.Files[name]) {
+			t.Errorf("expect %s written as %s, got %s", name, b.Files[name], data)
+		}
+	}
+	if string(b.Files[ValuesFile]) != "{}\n" {
+		t.Errorf("expect empty values, got %s", b.Files[ValuesFile])
+	}
+}
@@ -0,0 +1,120 @@
+// Package exporter is the reverse of package importer. It exports a HelmRequest and its Release
+// history to helm's storage, so the release can be managed by the helm CLI, or to a GitOps bundle.
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	listers "github.com/alauda/helm-crds/pkg/client/listers/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/importer"
+	"helm.sh/helm/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+)
+
+// HelmOptions are the options of exporting to helm storage
+type HelmOptions struct {
+	// Storage is the helm storage to write, default to importer.StorageSecret
+	Storage importer.Storage
+	// DryRun only reports the revisions would be exported, nothing is written
+	DryRun bool
+}
+
+// HelmResult is the result of exporting to helm storage
+type HelmResult struct {
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+	Storage   importer.Storage `json:"storage"`
+	// Exported are the revisions written to helm storage
+	Exported []int `json:"exported"`
+	// Existing are the revisions already in helm storage, they are not changed
+	Existing []int `json:"existing,omitempty"`
+}
+
+// Exporter exports HelmRequests
+type Exporter struct {
+	Client     versioned.Interface
+	KubeClient kubernetes.Interface
+}
+
+// NewExporter creates an Exporter
+func NewExporter(client versioned.Interface, kubeClient kubernetes.Interface) *Exporter {
+	return &Exporter{Client: client, KubeClient: kubeClient}
+}
+
+// Releases returns the Releases of the HelmRequest, from the oldest to the newest
+func (e *Exporter) Releases(ctx context.Context, hr *v1beta1.HelmRequest) ([]*v1beta1.Release, error) {
+	namespace := hr.GetReleaseNamespace()
+	list, err := e.Client.AppV1beta1().Releases(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{v1beta1.ReleaseNameLabel: hr.GetReleaseName()}.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list releases in namespace %s error: %s", namespace, err.Error())
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, listers.ReleaseIndexers())
+	for i := range list.Items {
+		indexer.Add(&list.Items[i])
+	}
+	return listers.NewReleaseLister(indexer).ReleasesForHelmRequest(hr)
+}
+
+// ToHelm writes the Release history of the HelmRequest to helm storage in the release namespace, with
+// the same keys as helm. Revisions already in the storage are kept. The HelmRequest and the Releases
+// are not deleted, that's left to the caller once the export is verified.
+func (e *Exporter) ToHelm(ctx context.Context, hr *v1beta1.HelmRequest, opts HelmOptions) (*HelmResult, error) {
+	namespace := hr.GetReleaseNamespace()
+	if opts.Storage == "" {
+		opts.Storage = importer.StorageSecret
+	}
+
+	var d driver.Driver
+	switch opts.Storage {
+	case importer.StorageSecret:
+		d = driver.NewSecrets(e.KubeClient.CoreV1().Secrets(namespace))
+	case importer.StorageConfigMap:
+		d = driver.NewConfigMaps(e.KubeClient.CoreV1().ConfigMaps(namespace))
+	default:
+		return nil, fmt.Errorf("unknown helm storage %s", opts.Storage)
+	}
+
+	releases, err := e.Releases(ctx, hr)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no release found for HelmRequest %s/%s", hr.Namespace, hr.Name)
+	}
+
+	result := &HelmResult{Namespace: namespace, Name: hr.GetReleaseName(), Storage: opts.Storage}
+	for _, rel := range releases {
+		rls, err := rel.ToHelmRelease()
+		if err != nil {
+			return nil, err
+		}
+		rls.Namespace = namespace
+
+		// helm uses the same key as the Release object, see storage.makeKey
+		key := v1beta1.ReleaseObjectName(rls.Name, rls.Version)
+		if _, err := d.Get(key); err == nil {
+			result.Existing = append(result.Existing, rls.Version)
+			continue
+		} else if err != driver.ErrReleaseNotFound {
+			return nil, fmt.Errorf("get release %s/%s from helm storage error: %s", namespace, key, err.Error())
+		}
+
+		if !opts.DryRun {
+			if err := d.Create(key, rls); err != nil && err != driver.ErrReleaseExists {
+				return nil, fmt.Errorf("create release %s/%s in helm storage error: %s", namespace, key, err.Error())
+			}
+			klog.Infof("exported release %s/%s to helm %s storage", namespace, key, opts.Storage)
+		}
+		result.Exported = append(result.Exported, rls.Version)
+	}
+	return result, nil
+}
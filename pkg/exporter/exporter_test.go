@@ -0,0 +1,147 @@
+package exporter
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	"github.com/alauda/helm-crds/pkg/importer"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/release"
+	"helm.sh/helm/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newHelmRelease(name string, version int, status release.Status) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "web",
+		Version:   version,
+		Info:      &release.Info{Status: status},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "nginx", Version: "1.0.0"}},
+		Config:    map[string]interface{}{"replicas": float64(version)},
+	}
+}
+
+// newReleases returns nginx revisions 1 to 3 in the release namespace web, and a release of another name
+func newReleases(t *testing.T) []runtime.Object {
+	var objects []runtime.Object
+	for _, rls := range []*release.Release{
+		newHelmRelease("nginx", 3, release.StatusDeployed),
+		newHelmRelease("nginx", 1, release.StatusSuperseded),
+		newHelmRelease("nginx", 2, release.StatusSuperseded),
+		newHelmRelease("redis", 1, release.StatusDeployed),
+	} {
+		rel, err := v1beta1.NewRelease(rls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, rel)
+	}
+	return objects
+}
+
+func newHelmRequest() *v1beta1.HelmRequest {
+	return &v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec:       v1beta1.HelmRequestSpec{Chart: "stable/nginx", Version: "1.0.0", Namespace: "web"},
+	}
+}
+
+func TestReleases(t *testing.T) {
+	e := NewExporter(fake.NewSimpleClientset(newReleases(t)...), kubefake.NewSimpleClientset())
+	releases, err := e.Releases(context.Background(), newHelmRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int
+	for _, rel := range releases {
+		if rel.Spec.Name != "nginx" {
+			t.Errorf("expect release nginx, got %s", rel.Spec.Name)
+		}
+		versions = append(versions, rel.Spec.Version)
+	}
+	if !reflect.DeepEqual(versions, []int{1, 2, 3}) {
+		t.Errorf("expect versions [1 2 3], got %v", versions)
+	}
+}
+
+func TestToHelm(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     HelmOptions
+		exported []int
+		existing []int
+		err      string
+	}{
+		{name: "default", exported: []int{2, 3}, existing: []int{1}},
+		{name: "config map", opts: HelmOptions{Storage: importer.StorageConfigMap}, exported: []int{1, 2, 3}},
+		{name: "dry run", opts: HelmOptions{DryRun: true}, exported: []int{2, 3}, existing: []int{1}},
+		{name: "unknown storage", opts: HelmOptions{Storage: "Memory"}, err: "unknown helm storage Memory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			secrets := driver.NewSecrets(kubeClient.CoreV1().Secrets("web"))
+			// revision 1 is already in helm storage, and it's not overwritten
+			existing := newHelmRelease("nginx", 1, release.StatusSuperseded)
+			existing.Config = map[string]interface{}{"replicas": "existing"}
+			if err := secrets.Create("nginx.v1", existing); err != nil {
+				t.Fatal(err)
+			}
+
+			e := NewExporter(fake.NewSimpleClientset(newReleases(t)...), kubeClient)
+			result, err := e.ToHelm(context.Background(), newHelmRequest(), tt.opts)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expect error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Namespace != "web" || result.Name != "nginx" || !reflect.DeepEqual(result.Exported, tt.exported) ||
+				!reflect.DeepEqual(result.Existing, tt.existing) {
+				t.Errorf("expect exported %v and existing %v in web/nginx, got %+v", tt.exported, tt.existing, result)
+			}
+
+			var d driver.Driver = secrets
+			if tt.opts.Storage == importer.StorageConfigMap {
+				d = driver.NewConfigMaps(kubeClient.CoreV1().ConfigMaps("web"))
+			}
+			stored, err := d.List(func(*release.Release) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			expect := len(tt.exported) + len(tt.existing)
+			if tt.opts.DryRun {
+				expect = len(tt.existing)
+			}
+			if len(stored) != expect {
+				t.Fatalf("expect %d releases in helm storage, got %d", expect, len(stored))
+			}
+			for _, rls := range stored {
+				if rls.Name != "nginx" || rls.Namespace != "web" {
+					t.Errorf("expect release web/nginx, got %s/%s", rls.Namespace, rls.Name)
+				}
+				if rls.Version == 1 && tt.existing != nil && rls.Config["replicas"] != "existing" {
+					t.Errorf("expect existing revision kept, got %v", rls.Config)
+				}
+				if rls.Version == 3 && (rls.Info.Status != release.StatusDeployed || rls.Config["replicas"] != float64(3)) {
+					t.Errorf("unexpected revision 3: %s %v", rls.Info.Status, rls.Config)
+				}
+			}
+		})
+	}
+
+	e := NewExporter(fake.NewSimpleClientset(), kubefake.NewSimpleClientset())
+	if _, err := e.ToHelm(context.Background(), newHelmRequest(), HelmOptions{}); err == nil || !strings.Contains(err.Error(), "no release found") {
+		t.Errorf("expect no release found, got %v", err)
+	}
+}
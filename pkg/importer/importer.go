@@ -0,0 +1,338 @@
+// Package importer adopts the releases installed by the helm CLI. It reads the releases from helm's
+// Secret or ConfigMap storage, creates the Release objects with the full history, and a HelmRequest
+// with the chart, version and values of the latest revision.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	"helm.sh/helm/pkg/chartutil"
+	"helm.sh/helm/pkg/release"
+	"helm.sh/helm/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+// AdoptedFromAnnotation is set on the imported HelmRequests, the value is the helm storage the release
+// is imported from, eg: Secret
+const AdoptedFromAnnotation = "captain.cpaas.io/adopted-from"
+
+// Storage is the storage of helm releases
+type Storage string
+
+const (
+	// StorageSecret is helm's default Secret storage
+	StorageSecret Storage = driver.SecretsDriverName
+	// StorageConfigMap is helm's ConfigMap storage
+	StorageConfigMap Storage = driver.ConfigMapsDriverName
+)
+
+// Action is what the importer does to a release
+type Action string
+
+const (
+	// ActionAdopt means the release is adopted
+	ActionAdopt Action = "Adopt"
+	// ActionSkip means the release is skipped, see Item.Reason
+	ActionSkip Action = "Skip"
+)
+
+// Options are the options of importing
+type Options struct {
+	// Namespace is where the releases are imported from, all namespaces if empty
+	Namespace string
+	// Storage is the helm storage to read, both Secret and ConfigMap if empty
+	Storage Storage
+	// Releases are the names of the releases to import, all releases if empty
+	Releases []string
+	// Repo is the chart repo of the imported HelmRequests. If empty, it's looked up from the Charts
+	// in the namespace and the ClusterCharts by the chart name and version
+	Repo string
+	// ClusterName is the cluster name of the imported HelmRequests
+	ClusterName string
+	// DryRun only reports what would be adopted, nothing is created
+	DryRun bool
+}
+
+// Item is the import result of a release
+type Item struct {
+	Namespace string  `json:"namespace"`
+	Name      string  `json:"name"`
+	Storage   Storage `json:"storage"`
+	// Revisions are the versions of the release in helm storage, from the oldest to the newest
+	Revisions []int  `json:"revisions"`
+	Chart     string `json:"chart,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Action    Action `json:"action"`
+	// Reason is why the release is skipped or failed to import
+	Reason string `json:"reason,omitempty"`
+
+	// HelmRequest and Releases are the objects to create, nil if skipped
+	HelmRequest *v1beta1.HelmRequest `json:"-"`
+	Releases    []*v1beta1.Release   `json:"-"`
+}
+
+// Report is the result of an import, sorted by namespace and name
+type Report struct {
+	DryRun bool   `json:"dryRun"`
+	Items  []Item `json:"items"`
+}
+
+// Adopted returns the number of the adopted releases
+func (r *Report) Adopted() int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Action == ActionAdopt {
+			n++
+		}
+	}
+	return n
+}
+
+// Importer imports helm releases
+type Importer struct {
+	Client     versioned.Interface
+	KubeClient kubernetes.Interface
+}
+
+// NewImporter creates an Importer
+func NewImporter(client versioned.Interface, kubeClient kubernetes.Interface) *Importer {
+	return &Importer{Client: client, KubeClient: kubeClient}
+}
+
+// Import reads the releases and adopts them. Errors of a single release are recorded in the report and
+// don't stop the others, the returned error is for the failures of reading helm storage.
+func (i *Importer) Import(ctx context.Context, opts Options) (*Report, error) {
+	namespaces := []string{opts.Namespace}
+	if opts.Namespace == "" {
+		list, err := i.KubeClient.CoreV1().Namespaces().List(metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("list namespaces error: %s", err.Error())
+		}
+		namespaces = namespaces[:0]
+		for _, ns := range list.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	storages := []Storage{StorageSecret, StorageConfigMap}
+	if opts.Storage != "" {
+		storages = []Storage{opts.Storage}
+	}
+
+	report := &Report{DryRun: opts.DryRun}
+	for _, ns := range namespaces {
+		for _, storage := range storages {
+			items, err := i.importNamespace(ctx, ns, storage, opts)
+			if err != nil {
+				return nil, err
+			}
+			report.Items = append(report.Items, items...)
+		}
+	}
+	sort.SliceStable(report.Items, func(a, b int) bool {
+		x, y := report.Items[a], report.Items[b]
+		if x.Namespace != y.Namespace {
+			return x.Namespace < y.Namespace
+		}
+		return x.Name < y.Name
+	})
+	return report, nil
+}
+
+func (i *Importer) importNamespace(ctx context.Context, namespace string, storage Storage, opts Options) ([]Item, error) {
+	var d driver.Driver
+	switch storage {
+	case StorageSecret:
+		d = driver.NewSecrets(i.KubeClient.CoreV1().Secrets(namespace))
+	case StorageConfigMap:
+		d = driver.NewConfigMaps(i.KubeClient.CoreV1().ConfigMaps(namespace))
+	default:
+		return nil, fmt.Errorf("unknown helm storage %s", storage)
+	}
+
+	releases, err := d.List(func(rls *release.Release) bool {
+		return len(opts.Releases) == 0 || contains(opts.Releases, rls.Name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list releases in %s storage of namespace %s error: %s", storage, namespace, err.Error())
+	}
+
+	history := map[string][]*release.Release{}
+	for _, rls := range releases {
+		if rls.Namespace == "" {
+			rls.Namespace = namespace
+		}
+		history[rls.Name] = append(history[rls.Name], rls)
+	}
+
+	var items []Item
+	for name, revisions := range history {
+		sort.Slice(revisions, func(a, b int) bool {
+			return revisions[a].Version < revisions[b].Version
+		})
+		item := i.plan(ctx, namespace, name, storage, revisions, opts)
+		if item.Action == ActionAdopt && !opts.DryRun {
+			if err := i.apply(ctx, &item); err != nil {
+				klog.Errorf("import release %s/%s error: %s", namespace, name, err.Error())
+				item.Action, item.Reason = ActionSkip, err.Error()
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// plan builds the objects to create for the release
+func (i *Importer) plan(ctx context.Context, namespace, name string, storage Storage, revisions []*release.Release, opts Options) Item {
+	item := Item{Namespace: namespace, Name: name, Storage: storage, Action: ActionSkip}
+	for _, rls := range revisions {
+		item.Revisions = append(item.Revisions, rls.Version)
+	}
+
+	latest := revisions[len(revisions)-1]
+	if latest.Chart == nil || latest.Chart.Metadata == nil {
+		item.Reason = "latest revision has no chart"
+		return item
+	}
+	item.Chart, item.Version = latest.Chart.Metadata.Name, latest.Chart.Metadata.Version
+	if latest.Info != nil {
+		switch latest.Info.Status {
+		case release.StatusUninstalled:
+			item.Reason = "release is uninstalled"
+			return item
+		case release.StatusPendingInstall, release.StatusPendingUpgrade, release.StatusPendingRollback, release.StatusUninstalling:
+			// the values and manifests of an unfinished operation are not what's running
+			item.Reason = fmt.Sprintf("latest revision %d is %s, import it after the operation is finished", latest.Version, latest.Info.Status)
+			return item
+		}
+	}
+
+	_, err := i.Client.AppV1beta1().HelmRequests(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		item.Reason = fmt.Sprintf("HelmRequest %s/%s already exists", namespace, name)
+		return item
+	}
+	if !errors.IsNotFound(err) {
+		item.Reason = fmt.Sprintf("get HelmRequest %s/%s error: %s", namespace, name, err.Error())
+		return item
+	}
+
+	repo := opts.Repo
+	if repo == "" {
+		if repo, err = i.findRepo(ctx, namespace, item.Chart, item.Version); err != nil {
+			item.Reason = err.Error()
+			return item
+		}
+	}
+	item.Chart = fmt.Sprintf("%s/%s", repo, item.Chart)
+
+	for _, rls := range revisions {
+		rel, err := v1beta1.NewRelease(rls)
+		if err != nil {
+			item.Reason = err.Error()
+			return item
+		}
+		item.Releases = append(item.Releases, rel)
+	}
+
+	item.HelmRequest = &v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{AdoptedFromAnnotation: string(storage)},
+		},
+		Spec: v1beta1.HelmRequestSpec{
+			ClusterName: opts.ClusterName,
+			ReleaseName: name,
+			Namespace:   latest.Namespace,
+			Chart:       item.Chart,
+			Version:     item.Version,
+			HelmValues:  v1beta1.HelmValues{Values: chartutil.Values(latest.Config)},
+		},
+	}
+	item.Action = ActionAdopt
+	return item
+}
+
+// findRepo finds the only repo that has the chart version, from the Charts in the namespace and the
+// ClusterCharts
+func (i *Importer) findRepo(ctx context.Context, namespace, chart, version string) (string, error) {
+	var charts []*v1beta1.Chart
+	list, err := i.Client.AppV1beta1().Charts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("list charts in namespace %s error: %s", namespace, err.Error())
+	}
+	for idx := range list.Items {
+		charts = append(charts, &list.Items[idx])
+	}
+	clusterList, err := i.Client.AppV1beta1().ClusterCharts().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("list cluster charts error: %s", err.Error())
+	}
+	for idx := range clusterList.Items {
+		charts = append(charts, clusterList.Items[idx].AsChart())
+	}
+
+	repos := map[string]bool{}
+	for _, ch := range charts {
+		repo := ch.Status.Repo
+		if repo == "" {
+			if idx := strings.Index(ch.Name, "."); idx >= 0 {
+				repo = ch.Name[idx+1:]
+			}
+		}
+		if ch.Name != v1beta1.ChartObjectName(repo, chart) {
+			continue
+		}
+		if cv := ch.Get(version); cv != nil && !cv.Removed {
+			repos[repo] = true
+		}
+	}
+
+	switch len(repos) {
+	case 0:
+		return "", fmt.Errorf("no repo has chart %s-%s", chart, version)
+	case 1:
+		for repo := range repos {
+			return repo, nil
+		}
+	}
+	var names []string
+	for repo := range repos {
+		names = append(names, repo)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("chart %s-%s found in multiple repos: %s", chart, version, strings.Join(names, ", "))
+}
+
+// apply creates the Releases and the HelmRequest, existing Releases are kept
+func (i *Importer) apply(ctx context.Context, item *Item) error {
+	for _, rel := range item.Releases {
+		_, err := i.Client.AppV1beta1().Releases(rel.Namespace).Create(ctx, rel, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("create release %s/%s error: %s", rel.Namespace, rel.Name, err.Error())
+		}
+	}
+	if _, err := i.Client.AppV1beta1().HelmRequests(item.Namespace).Create(ctx, item.HelmRequest, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("create HelmRequest %s/%s error: %s", item.Namespace, item.Name, err.Error())
+	}
+	klog.Infof("imported release %s/%s with %d revisions", item.Namespace, item.Name, len(item.Releases))
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
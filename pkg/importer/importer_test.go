@@ -0,0 +1,216 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/release"
+	"helm.sh/helm/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newHelmRelease(name string, version int, status release.Status, chartName, chartVersion string) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "default",
+		Version:   version,
+		Info:      &release.Info{Status: status},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: chartName, Version: chartVersion}},
+		Config:    map[string]interface{}{"replicas": float64(version)},
+		Manifest:  "kind: Service\nmetadata:\n  name: " + name,
+	}
+}
+
+func newChart(repo, name string, versions ...string) *v1beta1.Chart {
+	ch := &v1beta1.Chart{ObjectMeta: metav1.ObjectMeta{Name: v1beta1.ChartObjectName(repo, name), Namespace: "default"}}
+	ch.Status.Repo = repo
+	for _, v := range versions {
+		ch.Spec.Versions = append(ch.Spec.Versions, &v1beta1.ChartVersion{ChartMetadata: v1beta1.ChartMetadata{Name: name, Version: v}})
+	}
+	return ch
+}
+
+// newClients returns the clients with the helm releases in the storage
+func newClients(t *testing.T) (*fake.Clientset, *kubefake.Clientset) {
+	web := newChart("incubator", "web", "1.0.0")
+	web.Namespace = ""
+	// only the version removed from the repo
+	removed := newChart("incubator", "nginx", "1.1.0")
+	removed.Spec.Versions[0].Removed = true
+	client := fake.NewSimpleClientset(
+		newChart("stable", "nginx", "1.0.0", "1.1.0"),
+		newChart("stable", "web", "1.0.0"),
+		&v1beta1.ClusterChart{ObjectMeta: web.ObjectMeta, Spec: web.Spec, Status: web.Status},
+		removed,
+		&v1beta1.HelmRequest{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}},
+	)
+
+	kubeClient := kubefake.NewSimpleClientset()
+	secrets := driver.NewSecrets(kubeClient.CoreV1().Secrets("default"))
+	configMaps := driver.NewConfigMaps(kubeClient.CoreV1().ConfigMaps("default"))
+	for _, rls := range []*release.Release{
+		newHelmRelease("nginx", 2, release.StatusDeployed, "nginx", "1.1.0"),
+		newHelmRelease("nginx", 1, release.StatusSuperseded, "nginx", "1.0.0"),
+		newHelmRelease("installing", 1, release.StatusPendingInstall, "nginx", "1.0.0"),
+		newHelmRelease("upgrading", 1, release.StatusDeployed, "nginx", "1.0.0"),
+		newHelmRelease("upgrading", 2, release.StatusPendingUpgrade, "nginx", "1.1.0"),
+		newHelmRelease("rollingback", 1, release.StatusPendingRollback, "nginx", "1.0.0"),
+		newHelmRelease("uninstalled", 1, release.StatusUninstalled, "nginx", "1.0.0"),
+		newHelmRelease("existing", 1, release.StatusDeployed, "nginx", "1.0.0"),
+		newHelmRelease("ambiguous", 1, release.StatusDeployed, "web", "1.0.0"),
+		newHelmRelease("unknown", 1, release.StatusDeployed, "mysql", "1.0.0"),
+	} {
+		if err := secrets.Create(fmt.Sprintf("%s.v%d", rls.Name, rls.Version), rls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := configMaps.Create("legacy.v1", newHelmRelease("legacy", 1, release.StatusDeployed, "nginx", "1.0.0")); err != nil {
+		t.Fatal(err)
+	}
+	return client, kubeClient
+}
+
+func TestImport(t *testing.T) {
+	expect := []Item{
+		{Name: "ambiguous", Storage: StorageSecret, Revisions: []int{1}, Chart: "web", Version: "1.0.0", Action: ActionSkip,
+			Reason: "chart web-1.0.0 found in multiple repos: incubator, stable"},
+		{Name: "existing", Storage: StorageSecret, Revisions: []int{1}, Chart: "nginx", Version: "1.0.0", Action: ActionSkip,
+			Reason: "HelmRequest default/existing already exists"},
+		{Name: "installing", Storage: StorageSecret, Revisions: []int{1}, Chart: "nginx", Version: "1.0.0", Action: ActionSkip,
+			Reason: "latest revision 1 is pending-install, import it after the operation is finished"},
+		{Name: "legacy", Storage: StorageConfigMap, Revisions: []int{1}, Chart: "stable/nginx", Version: "1.0.0", Action: ActionAdopt},
+		{Name: "nginx", Storage: StorageSecret, Revisions: []int{1, 2}, Chart: "stable/nginx", Version: "1.1.0", Action: ActionAdopt},
+		{Name: "rollingback", Storage: StorageSecret, Revisions: []int{1}, Chart: "nginx", Version: "1.0.0", Action: ActionSkip,
+			Reason: "latest revision 1 is pending-rollback, import it after the operation is finished"},
+		{Name: "uninstalled", Storage: StorageSecret, Revisions: []int{1}, Chart: "nginx", Version: "1.0.0", Action: ActionSkip,
+			Reason: "release is uninstalled"},
+		{Name: "unknown", Storage: StorageSecret, Revisions: []int{1}, Chart: "mysql", Version: "1.0.0", Action: ActionSkip,
+			Reason: "no repo has chart mysql-1.0.0"},
+		{Name: "upgrading", Storage: StorageSecret, Revisions: []int{1, 2}, Chart: "nginx", Version: "1.1.0", Action: ActionSkip,
+			Reason: "latest revision 2 is pending-upgrade, import it after the operation is finished"},
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		t.Run(fmt.Sprintf("dry run %v", dryRun), func(t *testing.T) {
+			client, kubeClient := newClients(t)
+			report, err := NewImporter(client, kubeClient).Import(context.Background(), Options{Namespace: "default", DryRun: dryRun})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.DryRun != dryRun || report.Adopted() != 2 {
+				t.Errorf("expect dry run %v and 2 adopted, got %v and %d", dryRun, report.DryRun, report.Adopted())
+			}
+			if len(report.Items) != len(expect) {
+				t.Fatalf("expect %d items, got %d", len(expect), len(report.Items))
+			}
+			for i, item := range report.Items {
+				item.HelmRequest, item.Releases = nil, nil
+				expect[i].Namespace = "default"
+				if !reflect.DeepEqual(item, expect[i]) {
+					t.Errorf("expect item %+v, got %+v", expect[i], item)
+				}
+			}
+
+			hrs, err := client.AppV1beta1().HelmRequests("default").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			releases, err := client.AppV1beta1().Releases("default").List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dryRun {
+				if len(hrs.Items) != 1 || len(releases.Items) != 0 {
+					t.Errorf("expect nothing created in dry run, got %d HelmRequests and %d Releases", len(hrs.Items), len(releases.Items))
+				}
+				return
+			}
+			if len(hrs.Items) != 3 || len(releases.Items) != 3 {
+				t.Errorf("expect 2 HelmRequests and 3 Releases created, got %d HelmRequests and %d Releases", len(hrs.Items)-1, len(releases.Items))
+			}
+
+			hr, err := client.AppV1beta1().HelmRequests("default").Get(context.Background(), "nginx", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hr.Annotations[AdoptedFromAnnotation] != string(StorageSecret) || hr.Spec.Chart != "stable/nginx" || hr.Spec.Version != "1.1.0" ||
+				hr.Spec.ReleaseName != "nginx" || hr.Spec.Namespace != "default" || hr.Spec.Values["replicas"] != float64(2) {
+				t.Errorf("unexpected HelmRequest %+v", hr)
+			}
+			for _, name := range []string{v1beta1.ReleaseObjectName("nginx", 1), v1beta1.ReleaseObjectName("nginx", 2)} {
+				if _, err := client.AppV1beta1().Releases("default").Get(context.Background(), name, metav1.GetOptions{}); err != nil {
+					t.Errorf("expect release %s created, got %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestImportOptions(t *testing.T) {
+	client, kubeClient := newClients(t)
+	report, err := NewImporter(client, kubeClient).Import(context.Background(), Options{
+		Namespace:   "default",
+		Storage:     StorageSecret,
+		Releases:    []string{"ambiguous", "legacy"},
+		Repo:        "incubator",
+		ClusterName: "business",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// legacy is in ConfigMap storage, and the repo is not looked up for ambiguous
+	if len(report.Items) != 1 || report.Items[0].Name != "ambiguous" || report.Items[0].Action != ActionAdopt {
+		t.Fatalf("expect ambiguous adopted only, got %+v", report.Items)
+	}
+	hr, err := client.AppV1beta1().HelmRequests("default").Get(context.Background(), "ambiguous", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Spec.Chart != "incubator/web" || hr.Spec.ClusterName != "business" {
+		t.Errorf("expect chart incubator/web in cluster business, got %s in %s", hr.Spec.Chart, hr.Spec.ClusterName)
+	}
+
+	if _, err := NewImporter(client, kubeClient).Import(context.Background(), Options{Namespace: "default", Storage: "Memory"}); err == nil {
+		t.Error("expect error for unknown storage")
+	}
+}
+
+func TestFindRepo(t *testing.T) {
+	tests := []struct {
+		chart   string
+		version string
+		repo    string
+		err     string
+	}{
+		{chart: "nginx", version: "1.0.0", repo: "stable"},
+		// the removed version in incubator is ignored
+		{chart: "nginx", version: "1.1.0", repo: "stable"},
+		{chart: "web", version: "1.0.0", err: "chart web-1.0.0 found in multiple repos: incubator, stable"},
+		{chart: "web", version: "2.0.0", err: "no repo has chart web-2.0.0"},
+	}
+	client, kubeClient := newClients(t)
+	i := NewImporter(client, kubeClient)
+	for _, tt := range tests {
+		t.Run(tt.chart+"-"+tt.version, func(t *testing.T) {
+			repo, err := i.findRepo(context.Background(), "default", tt.chart, tt.version)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("expect error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo != tt.repo {
+				t.Errorf("expect repo %s, got %s", tt.repo, repo)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	listers "github.com/alauda/helm-crds/pkg/client/listers/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/verify"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/chart/loader"
+	"helm.sh/helm/pkg/repo"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RepoLoader loads the charts of HelmRequests from the chart repos in the cluster, with the same lookup
+// order as the controller, see listers.ResolveChart. It reads the resources by the clients directly, so
+// it's for the places without informers, eg: CLI.
+type RepoLoader struct {
+	Client     versioned.Interface
+	KubeClient kubernetes.Interface
+	// HTTPClient is used to download charts, default to http.DefaultClient
+	HTTPClient *http.Client
+	// RepoNamespace is the namespace of the ChartRepos when the chart reference has no namespace,
+	// default to the namespace of the HelmRequest
+	RepoNamespace string
+}
+
+// Load implements values.ChartLoader
+func (l *RepoLoader) Load(ctx context.Context, hr *v1beta1.HelmRequest) (*chart.Chart, error) {
+	ref, err := hr.GetChartReference()
+	if err != nil {
+		return nil, err
+	}
+
+	// only charts in repos have a verification config, the others can never be verified
+	if hr.Spec.RequireVerifiedChart && ref.Kind != v1beta1.ChartReferenceRepo {
+		return nil, fmt.Errorf("chart %s is required to be verified, but only charts in repos can be verified", hr.Spec.Chart)
+	}
+
+	switch ref.Kind {
+	case v1beta1.ChartReferenceURL:
+		d := &verify.Downloader{Client: l.HTTPClient}
+		cv := &repo.ChartVersion{
+			Metadata: &chart.Metadata{Name: ref.Chart, Version: ref.Version},
+			URLs:     []string{ref.URL},
+		}
+		data, _, err := d.Download(ctx, ref.URL, cv)
+		if err != nil {
+			return nil, err
+		}
+		return loader.LoadArchive(bytes.NewReader(data))
+	case v1beta1.ChartReferenceOCI:
+		return nil, fmt.Errorf("loading charts from OCI registries is not supported: %s", hr.Spec.Chart)
+	}
+
+	cr, cv, err := l.Resolve(ctx, hr)
+	if err != nil {
+		return nil, err
+	}
+	d, err := l.downloader(cr)
+	if err != nil {
+		return nil, err
+	}
+	data, result, err := d.Download(ctx, cr.Spec.URL, cv.ToRepoChartVersion())
+	if err != nil {
+		return nil, err
+	}
+	if hr.Spec.RequireVerifiedChart && (result == nil || result.Mode == v1beta1.ChartVerificationNone) {
+		return nil, fmt.Errorf("chart %s-%s is required to be verified, but repo %s has no verification", cv.Name, cv.Version, cr.Name)
+	}
+	return loader.LoadArchive(bytes.NewReader(data))
+}
+
+// Resolve finds the repo and the chart version of the HelmRequest, the chart must be in a repo.
+// ClusterChartRepos are returned in the form of ChartRepo.
+func (l *RepoLoader) Resolve(ctx context.Context, hr *v1beta1.HelmRequest) (*v1beta1.ChartRepo, *v1beta1.ChartVersion, error) {
+	ref, err := hr.GetChartReference()
+	if err != nil {
+		return nil, nil, err
+	}
+	if ref.Kind != v1beta1.ChartReferenceRepo {
+		return nil, nil, fmt.Errorf("chart %s is not in a repo", hr.Spec.Chart)
+	}
+
+	lookup := hr.DeepCopy()
+	if ref.Namespace == "" && l.RepoNamespace != "" {
+		lookup.Namespace = l.RepoNamespace
+	}
+	ch, kind, err := l.resolveChart(ctx, lookup, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cv := ch.Get(ref.Version)
+	if cv == nil {
+		if cv, err = ch.Match(ref.Version); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cv == nil {
+		return nil, nil, fmt.Errorf("no version of chart %s matches %s", hr.Spec.Chart, ref.Version)
+	}
+
+	repoName := ch.Status.Repo
+	if repoName == "" {
+		repoName = ref.Repo
+	}
+	cr, err := l.getRepo(ctx, kind, ch.Namespace, repoName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cr, cv, nil
+}
+
+// resolveChart lists the charts and finds the chart by listers.ResolveChart
+func (l *RepoLoader) resolveChart(ctx context.Context, hr *v1beta1.HelmRequest, ref *v1beta1.ChartReference) (*v1beta1.Chart, v1beta1.ChartKind, error) {
+	namespace := hr.Namespace
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+
+	charts := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if hr.Spec.ChartKind != v1beta1.ChartKindClusterChart {
+		list, err := l.Client.AppV1beta1().Charts(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("list charts in namespace %s error: %s", namespace, err.Error())
+		}
+		for i := range list.Items {
+			charts.Add(&list.Items[i])
+		}
+	}
+
+	var clusterChartLister listers.ClusterChartLister
+	if hr.Spec.ChartKind != v1beta1.ChartKindChart && ref.Namespace == "" {
+		clusterCharts := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		list, err := l.Client.AppV1beta1().ClusterCharts().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("list cluster charts error: %s", err.Error())
+		}
+		for i := range list.Items {
+			clusterCharts.Add(&list.Items[i])
+		}
+		clusterChartLister = listers.NewClusterChartLister(clusterCharts)
+	}
+
+	return listers.ResolveChart(listers.NewChartLister(charts), clusterChartLister, hr)
+}
+
+// getRepo gets the repo of the chart, ClusterChartRepos are returned in the form of ChartRepo
+func (l *RepoLoader) getRepo(ctx context.Context, kind v1beta1.ChartKind, namespace, name string) (*v1beta1.ChartRepo, error) {
+	if kind == v1beta1.ChartKindClusterChart {
+		cr, err := l.Client.AppV1beta1().ClusterChartRepos().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get cluster chart repo %s error: %s", name, err.Error())
+		}
+		return cr.AsChartRepo(), nil
+	}
+	cr, err := l.Client.AppV1beta1().ChartRepos(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get chart repo %s/%s error: %s", namespace, name, err.Error())
+	}
+	return cr, nil
+}
+
+// downloader creates a Downloader with the auth and verification of the repo
+func (l *RepoLoader) downloader(cr *v1beta1.ChartRepo) (*verify.Downloader, error) {
+	d := &verify.Downloader{Client: l.HTTPClient}
+	if cr.Spec.Secret != nil {
+		secret, err := l.getSecret(cr, cr.Spec.Secret)
+		if err != nil {
+			return nil, err
+		}
+		d.Username, d.Password = string(secret.Data["username"]), string(secret.Data["password"])
+	}
+
+	var keyring *v1.Secret
+	if cr.GetVerificationMode() == v1beta1.ChartVerificationProvenance && cr.Spec.Verification.Keyring != nil {
+		secret, err := l.getSecret(cr, cr.Spec.Verification.Keyring)
+		if err != nil {
+			return nil, err
+		}
+		keyring = secret
+	}
+	verifier, err := verify.New(cr, keyring)
+	if err != nil {
+		return nil, err
+	}
+	d.Verifier = verifier
+	return d, nil
+}
+
+// getSecret gets the secret referenced by the repo, the namespace defaults to the namespace of the repo
+func (l *RepoLoader) getSecret(cr *v1beta1.ChartRepo, ref *v1.SecretReference) (*v1.Secret, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = cr.Namespace
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("namespace of secret %s is required for cluster chart repo %s", ref.Name, cr.Name)
+	}
+	secret, err := l.KubeClient.CoreV1().Secrets(namespace).Get(ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get secret %s/%s error: %s", namespace, ref.Name, err.Error())
+	}
+	return secret, nil
+}
@@ -0,0 +1,158 @@
+package render
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+// chartServer serves nginx-0.1.0.tgz and counts the requests
+type chartServer struct {
+	*httptest.Server
+	archive  []byte
+	requests int
+}
+
+func newChartServer(t *testing.T) *chartServer {
+	dir, err := ioutil.TempDir("", "loader-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ch := &chart.Chart{Metadata: &chart.Metadata{APIVersion: "v1", Name: "nginx", Version: "0.1.0"}}
+	file, err := chartutil.Save(ch, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &chartServer{archive: archive}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests++
+		if !strings.HasSuffix(r.URL.Path, "/nginx-0.1.0.tgz") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(s.archive)
+	}))
+	return s
+}
+
+func (s *chartServer) digest() string {
+	return fmt.Sprintf("%x", sha256.Sum256(s.archive))
+}
+
+func newHelmRequest(chart string, requireVerified bool) *v1beta1.HelmRequest {
+	return &v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec:       v1beta1.HelmRequestSpec{Chart: chart, RequireVerifiedChart: requireVerified},
+	}
+}
+
+func TestLoadNotInRepo(t *testing.T) {
+	srv := newChartServer(t)
+	defer srv.Close()
+	l := &RepoLoader{Client: fake.NewSimpleClientset(), KubeClient: kubefake.NewSimpleClientset()}
+
+	tests := []struct {
+		name            string
+		chart           string
+		requireVerified bool
+		err             string
+	}{
+		{name: "url", chart: srv.URL + "/charts/nginx-0.1.0.tgz"},
+		{name: "url required to be verified", chart: srv.URL + "/charts/nginx-0.1.0.tgz", requireVerified: true, err: "only charts in repos can be verified"},
+		{name: "oci required to be verified", chart: "oci://example.com/charts/nginx:0.1.0", requireVerified: true, err: "only charts in repos can be verified"},
+		{name: "oci", chart: "oci://example.com/charts/nginx:0.1.0", err: "not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv.requests = 0
+			ch, err := l.Load(context.Background(), newHelmRequest(tt.chart, tt.requireVerified))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expect error %q, got %v", tt.err, err)
+				}
+				if srv.requests != 0 {
+					t.Errorf("expect no download, got %d requests", srv.requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ch.Name() != "nginx" || ch.Metadata.Version != "0.1.0" {
+				t.Errorf("unexpected chart %s-%s", ch.Name(), ch.Metadata.Version)
+			}
+		})
+	}
+}
+
+func TestLoadFromRepo(t *testing.T) {
+	srv := newChartServer(t)
+	defer srv.Close()
+
+	tests := []struct {
+		name            string
+		mode            v1beta1.ChartVerificationMode
+		requireVerified bool
+		digest          string
+		err             string
+	}{
+		{name: "no verification", mode: v1beta1.ChartVerificationNone},
+		{name: "no verification but required", mode: v1beta1.ChartVerificationNone, requireVerified: true, err: "has no verification"},
+		{name: "digest", mode: v1beta1.ChartVerificationDigest, requireVerified: true, digest: srv.digest()},
+		{name: "digest mismatch", mode: v1beta1.ChartVerificationDigest, digest: "0000", err: "digest mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &v1beta1.ChartRepo{
+				ObjectMeta: metav1.ObjectMeta{Name: "stable", Namespace: "default"},
+				Spec: v1beta1.ChartRepoSpec{
+					URL:          srv.URL,
+					Verification: &v1beta1.ChartVerification{Mode: tt.mode},
+				},
+			}
+			ch := &v1beta1.Chart{
+				ObjectMeta: metav1.ObjectMeta{Name: v1beta1.ChartObjectName("stable", "nginx"), Namespace: "default"},
+				Spec: v1beta1.ChartSpec{Versions: []*v1beta1.ChartVersion{{
+					ChartMetadata: v1beta1.ChartMetadata{Name: "nginx", Version: "0.1.0"},
+					URLs:          []string{"charts/nginx-0.1.0.tgz"},
+					Digest:        tt.digest,
+				}}},
+				Status: v1beta1.ChartStatus{Repo: "stable"},
+			}
+			l := &RepoLoader{Client: fake.NewSimpleClientset(cr, ch), KubeClient: kubefake.NewSimpleClientset()}
+
+			loaded, err := l.Load(context.Background(), newHelmRequest("stable/nginx@0.1.0", tt.requireVerified))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expect error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded.Name() != "nginx" {
+				t.Errorf("unexpected chart %s", loaded.Name())
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Package render renders HelmRequests to kubernetes manifests offline, the same way helm does when
+// installing, so the changes can be reviewed before they are applied.
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/values"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/chart/loader"
+	"helm.sh/helm/pkg/chartutil"
+	"helm.sh/helm/pkg/engine"
+	"helm.sh/helm/pkg/release"
+	"helm.sh/helm/pkg/releaseutil"
+)
+
+// notesFileSuffix is the suffix of the notes template, the same as helm
+const notesFileSuffix = "NOTES.txt"
+
+// Options are the options of rendering
+type Options struct {
+	// Capabilities is the kubernetes version and api versions used by templates, default to
+	// chartutil.DefaultCapabilities, so rendering does not require a cluster
+	Capabilities *chartutil.Capabilities
+	// IsUpgrade sets .Release.IsUpgrade instead of .Release.IsInstall
+	IsUpgrade bool
+	// Strict fails on missing values in templates
+	Strict bool
+}
+
+// Result is the rendered HelmRequest
+type Result struct {
+	// Chart is the chart used to render
+	Chart *chart.Chart
+	// Values is the effective values of the HelmRequest, before coalesced with the defaults of the chart
+	Values chartutil.Values
+	// Manifests are the rendered resources, sorted in the install order
+	Manifests []releaseutil.Manifest
+	// Hooks are the rendered hooks
+	Hooks []*release.Hook
+	// Notes is the rendered NOTES.txt of the chart, subcharts' notes are dropped like helm does
+	Notes string
+}
+
+// Manifest returns all manifests in one document, the same format as release.Manifest
+func (r *Result) Manifest() string {
+	var b bytes.Buffer
+	for _, m := range r.Manifests {
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+	return b.String()
+}
+
+// Render renders the HelmRequest with the default options
+func Render(ctx context.Context, hr *v1beta1.HelmRequest, charts values.ChartLoader, resolver values.Resolver) (*Result, error) {
+	return RenderWithOptions(ctx, hr, charts, resolver, Options{})
+}
+
+// RenderWithOptions loads the chart and resolves the values of the HelmRequest, then renders them with
+// helm's engine. Notes the loaded chart is modified by the dependency processing, loaders should not
+// return a shared chart.
+func RenderWithOptions(ctx context.Context, hr *v1beta1.HelmRequest, charts values.ChartLoader, resolver values.Resolver, opts Options) (*Result, error) {
+	caps := opts.Capabilities
+	if caps == nil {
+		caps = chartutil.DefaultCapabilities
+	}
+
+	ch, err := charts.Load(ctx, hr)
+	if err != nil {
+		return nil, fmt.Errorf("load chart %s error: %s", hr.Spec.Chart, err.Error())
+	}
+	vals, err := resolver.Resolve(ctx, hr)
+	if err != nil {
+		return nil, fmt.Errorf("resolve values error: %s", err.Error())
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if ch.Metadata.KubeVersion != "" && !chartutil.IsCompatibleRange(ch.Metadata.KubeVersion, caps.KubeVersion.String()) {
+		return nil, fmt.Errorf("chart requires kubernetesVersion: %s which is incompatible with Kubernetes %s",
+			ch.Metadata.KubeVersion, caps.KubeVersion.String())
+	}
+	if err := chartutil.ProcessDependencies(ch, vals); err != nil {
+		return nil, fmt.Errorf("process dependencies error: %s", err.Error())
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      hr.GetReleaseName(),
+		Namespace: hr.GetReleaseNamespace(),
+		IsInstall: !opts.IsUpgrade,
+		IsUpgrade: opts.IsUpgrade,
+	}
+	renderValues, err := chartutil.ToRenderValues(ch, vals, options, caps)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := engine.Engine{Strict: opts.Strict}.Render(ch, renderValues)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Chart: ch, Values: vals}
+	for name, content := range files {
+		if strings.HasSuffix(name, notesFileSuffix) {
+			if name == path.Join(ch.Name(), "templates", notesFileSuffix) {
+				result.Notes = content
+			}
+			delete(files, name)
+		}
+	}
+
+	result.Hooks, result.Manifests, err = releaseutil.SortManifests(files, caps.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, fmt.Errorf("parse rendered manifests error: %s", err.Error())
+	}
+	return result, nil
+}
+
+// NewLocalLoader creates a ChartLoader always loads the chart at path, it can be a chart directory or
+// a .tgz archive. The chart in the HelmRequest is ignored.
+func NewLocalLoader(path string) values.ChartLoader {
+	return values.ChartLoaderFunc(func(ctx context.Context, hr *v1beta1.HelmRequest) (*chart.Chart, error) {
+		return loader.Load(path)
+	})
+}
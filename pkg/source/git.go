@@ -0,0 +1,122 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+const (
+	gitUsernameEnv = "CAPTAIN_GIT_USERNAME"
+	gitPasswordEnv = "CAPTAIN_GIT_PASSWORD"
+)
+
+// credentialHelper answers the credentials from the environment variables, it's only used for http(s) urls
+var credentialHelper = fmt.Sprintf(`credential.helper=!f() { test "$1" = get || exit 0; echo "username=${%s}"; echo "password=${%s}"; }; f`,
+	gitUsernameEnv, gitPasswordEnv)
+
+// Git fetches charts from a git repo. It use the git command, so git must be installed.
+type Git struct {
+	// URL is the url of the git repo, any url supported by git works, eg: https://, ssh://, file://
+	URL string
+	// Ref is a branch, tag or commit. Default to the remote HEAD
+	Ref string
+	// Auth is used for http(s) urls
+	Auth *Auth
+}
+
+// Fetch implements Fetcher. It fetches Ref into dir and returns the commit id
+func (g *Git) Fetch(ctx context.Context, dir string) (string, error) {
+	ref := g.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	if _, err := g.run(ctx, "", "init", "-q", dir); err != nil {
+		return "", err
+	}
+
+	// a shallow fetch works for branches and tags, and for commits if the server allows it. If it does not,
+	// fetch everything and resolve the ref locally
+	target := "FETCH_HEAD"
+	if _, err := g.run(ctx, dir, "fetch", "-q", "--depth", "1", g.URL, ref); err != nil {
+		klog.V(4).Infof("shallow fetch %s of %s failed, fallback to full fetch: %s", ref, g.URL, err.Error())
+		if target, err = g.fetchAll(ctx, dir, ref); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := g.run(ctx, dir, "checkout", "-q", "--force", target); err != nil {
+		return "", err
+	}
+
+	commit, err := g.run(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// fetchAll fetches all the branches, tags and the remote HEAD, and returns the commit ref points to.
+// Branches are looked up in the fetched remote branches first, since there is no local branch.
+func (g *Git) fetchAll(ctx context.Context, dir, ref string) (string, error) {
+	if _, err := g.run(ctx, dir, "fetch", "-q", "--tags", g.URL,
+		"+refs/heads/*:refs/remotes/origin/*", "+HEAD:refs/remotes/origin/HEAD"); err != nil {
+		return "", err
+	}
+
+	for _, name := range []string{"refs/remotes/origin/" + ref, ref} {
+		commit, err := g.run(ctx, dir, "rev-parse", "--verify", "-q", name+"^{commit}")
+		if err == nil {
+			return strings.TrimSpace(commit), nil
+		}
+	}
+	return "", fmt.Errorf("ref %s not found in %s", ref, g.URL)
+}
+
+// run runs a git command in dir. The credentials are passed to git by a credential helper reading
+// environment variables, so they don't show up in the process list or the error messages
+func (g *Git) run(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if g.Auth != nil && g.Auth.Username != "" {
+		args = append([]string{"-c", "credential.helper=", "-c", credentialHelper}, args...)
+		env = append(env, gitUsernameEnv+"="+g.Auth.Username, gitPasswordEnv+"="+g.Auth.Password)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s error: %s: %s", gitCommand(args), err.Error(), g.redact(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// gitCommand returns the git sub command in args, skipping the -c options
+func gitCommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+// redact removes the credentials from git output, eg: the url in the error message may contain them
+func (g *Git) redact(msg string) string {
+	if g.Auth != nil && g.Auth.Password != "" {
+		msg = strings.Replace(msg, g.Auth.Password, "***", -1)
+		msg = strings.Replace(msg, url.QueryEscape(g.Auth.Password), "***", -1)
+	}
+	return strings.TrimSpace(msg)
+}
@@ -0,0 +1,229 @@
+package source
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRepo is a bare git repo in a temp dir, with a work tree to push commits from
+type gitRepo struct {
+	t    *testing.T
+	root string
+	bare string
+	work string
+}
+
+func newGitRepo(t *testing.T) *gitRepo {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root, err := ioutil.TempDir("", "git-source-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &gitRepo{t: t, root: root, bare: filepath.Join(root, "repo.git"), work: filepath.Join(root, "work")}
+	r.git("", "init", "-q", "--bare", r.bare)
+	r.git(r.bare, "symbolic-ref", "HEAD", "refs/heads/main")
+	r.git("", "init", "-q", r.work)
+	r.git(r.work, "checkout", "-q", "-b", "main")
+	return r
+}
+
+func (r *gitRepo) cleanup() {
+	os.RemoveAll(r.root)
+}
+
+func (r *gitRepo) git(dir string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %s: %s", strings.Join(args, " "), err.Error(), out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes a chart with the version to the branch, pushes it and returns the commit id
+func (r *gitRepo) commit(branch, version string) string {
+	r.git(r.work, "checkout", "-q", "-B", branch)
+	dir := filepath.Join(r.work, "charts", "nginx")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		r.t.Fatal(err)
+	}
+	chart := "apiVersion: v1\nname: nginx\nversion: " + version + "\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(chart), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+	r.git(r.work, "add", "-A")
+	r.git(r.work, "commit", "-q", "-m", version)
+	r.git(r.work, "push", "-q", "--force", r.bare, branch)
+	return r.git(r.work, "rev-parse", "HEAD")
+}
+
+func chartVersion(t *testing.T, dir string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "charts", "nginx", "Chart.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "version: ") {
+			return strings.TrimPrefix(line, "version: ")
+		}
+	}
+	return ""
+}
+
+func TestGitFetch(t *testing.T) {
+	repo := newGitRepo(t)
+	defer repo.cleanup()
+
+	first := repo.commit("main", "0.1.0")
+	repo.git(repo.work, "tag", "v0.1.0")
+	repo.git(repo.work, "push", "-q", repo.bare, "v0.1.0")
+	head := repo.commit("main", "0.2.0")
+	dev := repo.commit("dev", "0.3.0-dev")
+
+	tests := []struct {
+		name    string
+		ref     string
+		commit  string
+		version string
+	}{
+		{name: "default to remote HEAD", ref: "", commit: head, version: "0.2.0"},
+		{name: "branch", ref: "dev", commit: dev, version: "0.3.0-dev"},
+		{name: "tag", ref: "v0.1.0", commit: first, version: "0.1.0"},
+		// file:// does not allow fetching a commit directly, so it goes to the full fetch
+		{name: "commit", ref: first, commit: first, version: "0.1.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "git-fetch-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			g := &Git{URL: "file://" + repo.bare, Ref: tt.ref}
+			commit, err := g.Fetch(context.Background(), dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if commit != tt.commit {
+				t.Errorf("expect commit %s, got %s", tt.commit, commit)
+			}
+			if v := chartVersion(t, dir); v != tt.version {
+				t.Errorf("expect chart version %s, got %s", tt.version, v)
+			}
+		})
+	}
+}
+
+func TestGitFetchAll(t *testing.T) {
+	repo := newGitRepo(t)
+	defer repo.cleanup()
+	head := repo.commit("main", "0.1.0")
+	dev := repo.commit("dev", "0.2.0")
+
+	tests := []struct {
+		ref    string
+		commit string
+	}{
+		{ref: "HEAD", commit: head},
+		{ref: "main", commit: head},
+		{ref: "dev", commit: dev},
+		{ref: dev, commit: dev},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "git-fetch-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			g := &Git{URL: "file://" + repo.bare}
+			if _, err := g.run(context.Background(), "", "init", "-q", dir); err != nil {
+				t.Fatal(err)
+			}
+			commit, err := g.fetchAll(context.Background(), dir, tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if commit != tt.commit {
+				t.Errorf("expect commit %s, got %s", tt.commit, commit)
+			}
+		})
+	}
+
+	dir, err := ioutil.TempDir("", "git-fetch-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	g := &Git{URL: "file://" + repo.bare, Ref: "missing"}
+	if _, err := g.Fetch(context.Background(), dir); err == nil {
+		t.Error("expect error for missing ref")
+	}
+}
+
+func TestGitCredentials(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "git-fetch-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the server asks for credentials and records what it gets, then fails the request
+	var user, password string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		if user, password, ok = r.BasicAuth(); !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "not a git server", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	g := &Git{URL: srv.URL + "/charts.git", Auth: &Auth{Username: "admin", Password: "s3cret"}}
+	_, err = g.Fetch(context.Background(), dir)
+	if err == nil {
+		t.Fatal("expect error")
+	}
+	if user != "admin" || password != "s3cret" {
+		t.Errorf("credentials are not sent, got %s:%s", user, password)
+	}
+	if strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("password is not redacted: %s", err.Error())
+	}
+	if !strings.HasPrefix(err.Error(), "git fetch error") {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+}
+
+func TestGitCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"fetch", "-q"}, want: "fetch"},
+		{args: []string{"-c", "credential.helper=", "-c", credentialHelper, "checkout"}, want: "checkout"},
+		{args: nil, want: ""},
+	}
+	for _, tt := range tests {
+		if got := gitCommand(tt.args); got != tt.want {
+			t.Errorf("gitCommand(%v) = %s, expect %s", tt.args, got, tt.want)
+		}
+	}
+}
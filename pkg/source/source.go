@@ -0,0 +1,210 @@
+// Package source builds helm chart repositories from charts stored in a VCS, such as git or svn.
+// The result is an IndexFile with packaged chart archives, so Chart resources can be generated the
+// same way as for a normal http chart repo.
+package source
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/chart/loader"
+	"helm.sh/helm/pkg/chartutil"
+	"helm.sh/helm/pkg/provenance"
+	"helm.sh/helm/pkg/repo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+// Fetcher fetches the content of a VCS into a local directory
+type Fetcher interface {
+	// Fetch fetches the source into dir and returns the revision it got, eg: commit id for git
+	Fetch(ctx context.Context, dir string) (string, error)
+}
+
+// Auth contains the credentials used to access the VCS
+type Auth struct {
+	Username string
+	Password string
+}
+
+// AuthFromSecret read credentials from a Secret, it use the same keys as the ChartRepo secret:
+// username and password
+func AuthFromSecret(secret *v1.Secret) *Auth {
+	if secret == nil {
+		return nil
+	}
+	return &Auth{
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+	}
+}
+
+// New creates a Fetcher for the ChartRepo according to it's type
+func New(cr *v1beta1.ChartRepo, auth *Auth) (Fetcher, error) {
+	if cr.Spec.Source == nil {
+		return nil, fmt.Errorf("chartrepo %s/%s has no .spec.source", cr.GetNamespace(), cr.GetName())
+	}
+
+	switch v1beta1.ChartRepoType(cr.Spec.Type) {
+	case v1beta1.ChartRepoGit:
+		return &Git{URL: cr.Spec.Source.URL, Ref: cr.Spec.Source.Ref, Auth: auth}, nil
+	case v1beta1.ChartRepoSvn:
+		return &Svn{URL: cr.Spec.Source.URL, Revision: cr.Spec.Source.Ref, Auth: auth}, nil
+	default:
+		return nil, fmt.Errorf("unsupported chartrepo type: %s", cr.Spec.Type)
+	}
+}
+
+// Options controls how to build the chart repo
+type Options struct {
+	// BaseURL is prepended to the archive name in the chart urls. If empty, the urls are relative.
+	BaseURL string
+	// WorkDir is where the source is fetched to. A temp dir will be used if empty, and it will be
+	// removed after build.
+	WorkDir string
+}
+
+// Result is a chart repo built from a source
+type Result struct {
+	// Revision is the revision of the source
+	Revision string
+	// Index is the index file of the built chart repo
+	Index *repo.IndexFile
+	// Archives contains the packaged charts, key is the archive file name, eg: nginx-0.1.0.tgz
+	Archives map[string][]byte
+}
+
+// MarkSynced records the result in the status of the ChartRepo, including the source revision
+func (r *Result) MarkSynced(cr *v1beta1.ChartRepo, start, end time.Time) {
+	cr.MarkSynced(start, end, len(r.Archives), r.Revision)
+}
+
+// Build fetches the source, finds all the charts under path and packages them
+func Build(ctx context.Context, f Fetcher, path string, opts Options) (*Result, error) {
+	dir := opts.WorkDir
+	if dir == "" {
+		tmp, err := ioutil.TempDir("", "chart-source-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+
+	revision, err := f.Fetch(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := securePath(dir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	charts, err := FindCharts(root)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ioutil.TempDir("", "chart-package-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(out)
+
+	result := &Result{
+		Revision: revision,
+		Index:    repo.NewIndexFile(),
+		Archives: map[string][]byte{},
+	}
+	for _, c := range charts {
+		archive, err := Package(c, out)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := result.Archives[archive.Name]; ok {
+			klog.Warningf("duplicate chart %s found in %s, skip it", archive.Name, c)
+			continue
+		}
+		result.Archives[archive.Name] = archive.Data
+		result.Index.Add(archive.Metadata, archive.Name, opts.BaseURL, archive.Digest)
+	}
+	result.Index.SortEntries()
+
+	klog.V(4).Infof("build %d charts from revision %s", len(result.Archives), revision)
+	return result, nil
+}
+
+// FindCharts returns all the chart directories under root. A chart directory is a directory contains
+// Chart.yaml, it's sub directories will not be searched (they are sub charts).
+func FindCharts(root string) ([]string, error) {
+	var charts []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, chartutil.ChartfileName)); err == nil {
+			charts = append(charts, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return charts, err
+}
+
+// Archive is a packaged chart
+type Archive struct {
+	// Name is the archive file name
+	Name     string
+	Data     []byte
+	Digest   string
+	Metadata *chart.Metadata
+}
+
+// Package packages the chart in dir to an archive in out
+func Package(dir, out string) (*Archive, error) {
+	ch, err := loader.LoadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("load chart %s error: %s", dir, err.Error())
+	}
+	file, err := chartutil.Save(ch, out)
+	if err != nil {
+		return nil, fmt.Errorf("package chart %s error: %s", dir, err.Error())
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := provenance.DigestFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{
+		Name:     filepath.Base(file),
+		Data:     data,
+		Digest:   digest,
+		Metadata: ch.Metadata,
+	}, nil
+}
+
+// securePath joins path to root and make sure the result is still under root
+func securePath(root, path string) (string, error) {
+	p := filepath.Join(root, filepath.Clean("/"+path))
+	if p != root && !strings.HasPrefix(p, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is out of the source", path)
+	}
+	return p, nil
+}
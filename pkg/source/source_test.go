@@ -0,0 +1,60 @@
+package source
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeFetcher writes the charts into the dir and returns the revision
+type fakeFetcher struct {
+	revision string
+	charts   map[string]string
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, dir string) (string, error) {
+	for name, version := range f.charts {
+		chartDir := filepath.Join(dir, "charts", name)
+		if err := os.MkdirAll(chartDir, 0755); err != nil {
+			return "", err
+		}
+		chart := "apiVersion: v1\nname: " + name + "\nversion: " + version + "\n"
+		if err := ioutil.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chart), 0644); err != nil {
+			return "", err
+		}
+	}
+	return f.revision, nil
+}
+
+func TestBuildRevisionInStatus(t *testing.T) {
+	f := &fakeFetcher{revision: "abc123", charts: map[string]string{"nginx": "0.1.0", "redis": "1.0.0"}}
+	result, err := Build(context.Background(), f, "charts", Options{BaseURL: "http://charts.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Revision != "abc123" {
+		t.Errorf("expect revision abc123, got %s", result.Revision)
+	}
+	if len(result.Archives) != 2 {
+		t.Errorf("expect 2 archives, got %d", len(result.Archives))
+	}
+
+	cr := &v1beta1.ChartRepo{ObjectMeta: metav1.ObjectMeta{Name: "git", Generation: 3}}
+	start := time.Now()
+	result.MarkSynced(cr, start, start.Add(time.Second))
+	if cr.Status.Revision != "abc123" {
+		t.Errorf("expect status revision abc123, got %s", cr.Status.Revision)
+	}
+	if cr.Status.ChartCount != 2 || cr.Status.ObservedGeneration != 3 {
+		t.Errorf("unexpected status %+v", cr.Status)
+	}
+	if cr.Status.LastSyncDuration.Duration != time.Second {
+		t.Errorf("expect duration 1s, got %s", cr.Status.LastSyncDuration.Duration)
+	}
+}
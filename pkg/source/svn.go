@@ -0,0 +1,69 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Svn fetches charts from a svn repo. It use the svn command, so svn must be installed.
+type Svn struct {
+	// URL is the url of the svn repo or a sub path of it, eg: svn://, https://, file://
+	URL string
+	// Revision is the revision to checkout, default to HEAD
+	Revision string
+	// Auth is the username and password for the svn server
+	Auth *Auth
+}
+
+// Fetch implements Fetcher. It checkouts Revision into dir and returns the revision number
+func (s *Svn) Fetch(ctx context.Context, dir string) (string, error) {
+	revision := s.Revision
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	if _, err := s.run(ctx, "checkout", "-q", "--force", "--revision", revision, s.URL, dir); err != nil {
+		return "", err
+	}
+
+	out, err := s.run(ctx, "info", "--show-item", "revision", dir)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// run runs a svn command in non-interactive mode, the credentials will not be cached. The password is
+// passed by stdin, so it does not show up in the process list
+func (s *Svn) run(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "svn", s.args(args...)...)
+	if s.Auth != nil && s.Auth.Username != "" {
+		cmd.Stdin = strings.NewReader(s.Auth.Password)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("svn %s error: %s: %s", args[0], err.Error(), s.redact(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// args appends the global options to args, it requires svn 1.10+ for --password-from-stdin
+func (s *Svn) args(args ...string) []string {
+	opts := []string{"--non-interactive", "--no-auth-cache"}
+	if s.Auth != nil && s.Auth.Username != "" {
+		opts = append(opts, "--username", s.Auth.Username, "--password-from-stdin")
+	}
+	return append(args, opts...)
+}
+
+func (s *Svn) redact(msg string) string {
+	if s.Auth != nil && s.Auth.Password != "" {
+		msg = strings.Replace(msg, s.Auth.Password, "***", -1)
+	}
+	return strings.TrimSpace(msg)
+}
@@ -0,0 +1,103 @@
+package source
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSvnArgs(t *testing.T) {
+	s := &Svn{Auth: &Auth{Username: "admin", Password: "s3cret"}}
+	args := s.args("checkout", "file:///repo", "dir")
+	for _, arg := range args {
+		if strings.Contains(arg, "s3cret") {
+			t.Fatalf("password in args: %v", args)
+		}
+	}
+	expect := "checkout file:///repo dir --non-interactive --no-auth-cache --username admin --password-from-stdin"
+	if got := strings.Join(args, " "); got != expect {
+		t.Errorf("expect %s, got %s", expect, got)
+	}
+
+	anonymous := &Svn{}
+	if got := strings.Join(anonymous.args("info"), " "); got != "info --non-interactive --no-auth-cache" {
+		t.Errorf("unexpected args %s", got)
+	}
+}
+
+func TestSvnFetch(t *testing.T) {
+	for _, bin := range []string{"svn", "svnadmin"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s is not installed", bin)
+		}
+	}
+	root, err := ioutil.TempDir("", "svn-source-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	repo := filepath.Join(root, "repo")
+	if out, err := exec.Command("svnadmin", "create", repo).CombinedOutput(); err != nil {
+		t.Fatalf("svnadmin create: %s: %s", err.Error(), out)
+	}
+	url := "file://" + repo
+
+	// import two revisions of a chart
+	for _, version := range []string{"0.1.0", "0.2.0"} {
+		src := filepath.Join(root, "src-"+version, "charts", "nginx")
+		if err := os.MkdirAll(src, 0755); err != nil {
+			t.Fatal(err)
+		}
+		chart := "apiVersion: v1\nname: nginx\nversion: " + version + "\n"
+		if err := ioutil.WriteFile(filepath.Join(src, "Chart.yaml"), []byte(chart), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("svn", "import", "-q", "-m", version, filepath.Join(root, "src-"+version, "charts"), url+"/"+version)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("svn import: %s: %s", err.Error(), out)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		revision string
+		expect   string
+		version  string
+	}{
+		{name: "head", url: url + "/0.2.0", expect: "2", version: "0.2.0"},
+		{name: "revision", url: url, revision: "1", expect: "1", version: "0.1.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(root, "checkout-"+tt.name)
+			s := &Svn{URL: tt.url, Revision: tt.revision}
+			revision, err := s.Fetch(context.Background(), dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if revision != tt.expect {
+				t.Errorf("expect revision %s, got %s", tt.expect, revision)
+			}
+			charts, err := FindCharts(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(charts) != 1 {
+				t.Fatalf("expect 1 chart, got %v", charts)
+			}
+			data, err := ioutil.ReadFile(filepath.Join(charts[0], "Chart.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "version: "+tt.version) {
+				t.Errorf("expect chart version %s, got %s", tt.version, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Package status updates the status of HelmRequests, ChartRepos and Charts. The changes are sent as merge
+// patches to the status subresource, and retried with the latest object on conflicts, so controllers
+// don't need to implement the read-modify-write loop themselves.
+package status
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+)
+
+// resource gets and patches the status of the objects of a kind, namespaced or cluster scoped
+type resource struct {
+	get   func(ctx context.Context, name string) (metav1.Object, error)
+	patch func(ctx context.Context, name string, data []byte) (metav1.Object, error)
+	// status returns the status of the object and a mutated copy of it
+	status func(obj metav1.Object) (interface{}, interface{})
+}
+
+// UpdateHelmRequestStatus gets the HelmRequest by key(<namespace>/<name>), calls mutate with a copy of its
+// status and patches the changes. mutate is called again with the latest status on conflicts, so it should
+// not depend on the status it got last time. Nothing is sent if mutate changes nothing. Returns the latest
+// HelmRequest.
+func UpdateHelmRequestStatus(ctx context.Context, client versioned.Interface, key string, mutate func(*v1beta1.HelmRequestStatus)) (*v1beta1.HelmRequest, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, err
+	}
+	hrs := client.AppV1beta1().HelmRequests(namespace)
+	obj, err := update(ctx, name, resource{
+		get: func(ctx context.Context, name string) (metav1.Object, error) {
+			return hrs.Get(ctx, name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, name string, data []byte) (metav1.Object, error) {
+			return hrs.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{}, "status")
+		},
+		status: func(obj metav1.Object) (interface{}, interface{}) {
+			hr := obj.(*v1beta1.HelmRequest)
+			status := hr.Status.DeepCopy()
+			mutate(status)
+			return &hr.Status, status
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.HelmRequest), nil
+}
+
+// UpdateChartRepoStatus is the same as UpdateHelmRequestStatus, but for ChartRepos
+func UpdateChartRepoStatus(ctx context.Context, client versioned.Interface, key string, mutate func(*v1beta1.ChartRepoStatus)) (*v1beta1.ChartRepo, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, err
+	}
+	repos := client.AppV1beta1().ChartRepos(namespace)
+	obj, err := update(ctx, name, resource{
+		get: func(ctx context.Context, name string) (metav1.Object, error) {
+			return repos.Get(ctx, name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, name string, data []byte) (metav1.Object, error) {
+			return repos.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{}, "status")
+		},
+		status: func(obj metav1.Object) (interface{}, interface{}) {
+			return repoStatus(&obj.(*v1beta1.ChartRepo).Status, mutate)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ChartRepo), nil
+}
+
+// UpdateClusterChartRepoStatus is the same as UpdateChartRepoStatus, the key is the name of the
+// ClusterChartRepo
+func UpdateClusterChartRepoStatus(ctx context.Context, client versioned.Interface, name string, mutate func(*v1beta1.ChartRepoStatus)) (*v1beta1.ClusterChartRepo, error) {
+	repos := client.AppV1beta1().ClusterChartRepos()
+	obj, err := update(ctx, name, resource{
+		get: func(ctx context.Context, name string) (metav1.Object, error) {
+			return repos.Get(ctx, name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, name string, data []byte) (metav1.Object, error) {
+			return repos.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{}, "status")
+		},
+		status: func(obj metav1.Object) (interface{}, interface{}) {
+			return repoStatus(&obj.(*v1beta1.ClusterChartRepo).Status, mutate)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterChartRepo), nil
+}
+
+// UpdateChartStatus is the same as UpdateHelmRequestStatus, but for Charts
+func UpdateChartStatus(ctx context.Context, client versioned.Interface, key string, mutate func(*v1beta1.ChartStatus)) (*v1beta1.Chart, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, err
+	}
+	charts := client.AppV1beta1().Charts(namespace)
+	obj, err := update(ctx, name, resource{
+		get: func(ctx context.Context, name string) (metav1.Object, error) {
+			return charts.Get(ctx, name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, name string, data []byte) (metav1.Object, error) {
+			return charts.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{}, "status")
+		},
+		status: func(obj metav1.Object) (interface{}, interface{}) {
+			return chartStatus(&obj.(*v1beta1.Chart).Status, mutate)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.Chart), nil
+}
+
+// UpdateClusterChartStatus is the same as UpdateChartStatus, the key is the name of the ClusterChart
+func UpdateClusterChartStatus(ctx context.Context, client versioned.Interface, name string, mutate func(*v1beta1.ChartStatus)) (*v1beta1.ClusterChart, error) {
+	charts := client.AppV1beta1().ClusterCharts()
+	obj, err := update(ctx, name, resource{
+		get: func(ctx context.Context, name string) (metav1.Object, error) {
+			return charts.Get(ctx, name, metav1.GetOptions{})
+		},
+		patch: func(ctx context.Context, name string, data []byte) (metav1.Object, error) {
+			return charts.Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{}, "status")
+		},
+		status: func(obj metav1.Object) (interface{}, interface{}) {
+			return chartStatus(&obj.(*v1beta1.ClusterChart).Status, mutate)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.ClusterChart), nil
+}
+
+// repoStatus returns the status and a mutated copy of it, shared by ChartRepos and ClusterChartRepos
+func repoStatus(status *v1beta1.ChartRepoStatus, mutate func(*v1beta1.ChartRepoStatus)) (interface{}, interface{}) {
+	cur := status.DeepCopy()
+	mutate(cur)
+	return status, cur
+}
+
+// chartStatus is the same as repoStatus, shared by Charts and ClusterCharts
+func chartStatus(status *v1beta1.ChartStatus, mutate func(*v1beta1.ChartStatus)) (interface{}, interface{}) {
+	cur := status.DeepCopy()
+	mutate(cur)
+	return status, cur
+}
+
+// update runs the read-modify-write loop of the object of the name, and returns the latest object.
+// Conflicts are retried with backoff.
+func update(ctx context.Context, name string, r resource) (metav1.Object, error) {
+	var obj metav1.Object
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.get(ctx, name)
+		if err != nil {
+			return err
+		}
+		obj = latest
+		old, cur := r.status(latest)
+		if equality.Semantic.DeepEqual(old, cur) {
+			return nil
+		}
+		data, err := statusPatch(old, cur, latest.GetResourceVersion())
+		if err != nil {
+			return err
+		}
+		patched, err := r.patch(ctx, name, data)
+		if err != nil {
+			return err
+		}
+		obj = patched
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// statusPatch creates a merge patch from the old status to the new one. The resource version is included
+// so the patch fails with a conflict if the object has been changed after it's read.
+func statusPatch(old, cur interface{}, resourceVersion string) ([]byte, error) {
+	oldData, err := json.Marshal(map[string]interface{}{"status": old})
+	if err != nil {
+		return nil, err
+	}
+	curData, err := json.Marshal(map[string]interface{}{"status": cur})
+	if err != nil {
+		return nil, err
+	}
+	data, err := jsonpatch.CreateMergePatch(oldData, curData)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := map[string]interface{}{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	patch["metadata"] = map[string]interface{}{"resourceVersion": resourceVersion}
+	return json.Marshal(patch)
+}
@@ -0,0 +1,241 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/fake"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func meta(namespace, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: namespace, Name: name, ResourceVersion: "1"}
+}
+
+func newClient() *fake.Clientset {
+	return fake.NewSimpleClientset(
+		&v1beta1.HelmRequest{ObjectMeta: meta("default", "nginx"), Status: v1beta1.HelmRequestStatus{Phase: v1beta1.HelmRequestPending}},
+		&v1beta1.ChartRepo{ObjectMeta: meta("default", "stable")},
+		&v1beta1.ClusterChartRepo{ObjectMeta: meta("", "stable")},
+		&v1beta1.Chart{ObjectMeta: meta("default", "nginx.stable")},
+		&v1beta1.ClusterChart{ObjectMeta: meta("", "nginx.stable")},
+	)
+}
+
+// patches returns the status patches sent by the client
+func patches(t *testing.T, client *fake.Clientset) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, action := range client.Actions() {
+		patch, ok := action.(clienttesting.PatchAction)
+		if !ok {
+			continue
+		}
+		if patch.GetSubresource() != "status" {
+			t.Errorf("expect subresource status, got %q", patch.GetSubresource())
+		}
+		data := map[string]interface{}{}
+		if err := json.Unmarshal(patch.GetPatch(), &data); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, data)
+	}
+	return result
+}
+
+func resourceVersion(patch map[string]interface{}) interface{} {
+	metadata, _ := patch["metadata"].(map[string]interface{})
+	return metadata["resourceVersion"]
+}
+
+func TestUpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		update func(client *fake.Clientset) (string, error)
+	}{
+		{
+			name: "helmrequest",
+			update: func(client *fake.Clientset) (string, error) {
+				hr, err := UpdateHelmRequestStatus(ctx, client, "default/nginx", func(status *v1beta1.HelmRequestStatus) {
+					status.Phase = v1beta1.HelmRequestSynced
+				})
+				if err != nil {
+					return "", err
+				}
+				return string(hr.Status.Phase), nil
+			},
+		},
+		{
+			name: "chartrepo",
+			update: func(client *fake.Clientset) (string, error) {
+				cr, err := UpdateChartRepoStatus(ctx, client, "default/stable", func(status *v1beta1.ChartRepoStatus) {
+					status.Phase = v1beta1.ChartRepoSynced
+				})
+				if err != nil {
+					return "", err
+				}
+				return string(cr.Status.Phase), nil
+			},
+		},
+		{
+			name: "clusterchartrepo",
+			update: func(client *fake.Clientset) (string, error) {
+				cr, err := UpdateClusterChartRepoStatus(ctx, client, "stable", func(status *v1beta1.ChartRepoStatus) {
+					status.Phase = v1beta1.ChartRepoSynced
+				})
+				if err != nil {
+					return "", err
+				}
+				return string(cr.Status.Phase), nil
+			},
+		},
+		{
+			name: "chart",
+			update: func(client *fake.Clientset) (string, error) {
+				chart, err := UpdateChartStatus(ctx, client, "default/nginx.stable", func(status *v1beta1.ChartStatus) {
+					status.LatestVersion = "Synced"
+				})
+				if err != nil {
+					return "", err
+				}
+				return chart.Status.LatestVersion, nil
+			},
+		},
+		{
+			name: "clusterchart",
+			update: func(client *fake.Clientset) (string, error) {
+				chart, err := UpdateClusterChartStatus(ctx, client, "nginx.stable", func(status *v1beta1.ChartStatus) {
+					status.LatestVersion = "Synced"
+				})
+				if err != nil {
+					return "", err
+				}
+				return chart.Status.LatestVersion, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient()
+			got, err := tt.update(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "Synced" {
+				t.Errorf("expect Synced, got %q", got)
+			}
+			sent := patches(t, client)
+			if len(sent) != 1 {
+				t.Fatalf("expect 1 patch, got %d", len(sent))
+			}
+			if rv := resourceVersion(sent[0]); rv != "1" {
+				t.Errorf("expect resourceVersion 1 in the patch, got %v", rv)
+			}
+			if _, ok := sent[0]["spec"]; ok {
+				t.Errorf("expect only the status in the patch, got %v", sent[0])
+			}
+		})
+	}
+}
+
+func TestUpdateStatusConflict(t *testing.T) {
+	client := newClient()
+	conflicts := 1
+	client.PrependReactor("patch", "helmrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if conflicts == 0 {
+			return false, nil, nil
+		}
+		conflicts--
+		return true, nil, errors.NewConflict(schema.GroupResource{Group: "app.alauda.io", Resource: "helmrequests"}, "nginx", nil)
+	})
+
+	called := 0
+	hr, err := UpdateHelmRequestStatus(context.Background(), client, "default/nginx", func(status *v1beta1.HelmRequestStatus) {
+		called++
+		status.Phase = v1beta1.HelmRequestSynced
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if called != 2 {
+		t.Errorf("expect mutate called 2 times, got %d", called)
+	}
+	if len(patches(t, client)) != 2 {
+		t.Errorf("expect 2 patches, got %d", len(patches(t, client)))
+	}
+	if hr.Status.Phase != v1beta1.HelmRequestSynced {
+		t.Errorf("expect phase %s, got %s", v1beta1.HelmRequestSynced, hr.Status.Phase)
+	}
+}
+
+func TestUpdateStatusNoop(t *testing.T) {
+	client := newClient()
+	hr, err := UpdateHelmRequestStatus(context.Background(), client, "default/nginx", func(status *v1beta1.HelmRequestStatus) {
+		status.Phase = v1beta1.HelmRequestPending
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent := patches(t, client); len(sent) != 0 {
+		t.Errorf("expect no patch, got %v", sent)
+	}
+	if hr.Name != "nginx" || hr.Status.Phase != v1beta1.HelmRequestPending {
+		t.Errorf("expect the latest nginx, got %s in %s", hr.Name, hr.Status.Phase)
+	}
+}
+
+func TestUpdateStatusError(t *testing.T) {
+	client := newClient()
+	mutate := func(status *v1beta1.HelmRequestStatus) { status.Phase = v1beta1.HelmRequestSynced }
+	if _, err := UpdateHelmRequestStatus(context.Background(), client, "default/redis", mutate); !errors.IsNotFound(err) {
+		t.Errorf("expect not found error, got %v", err)
+	}
+	if _, err := UpdateHelmRequestStatus(context.Background(), client, "a/b/c", mutate); err == nil {
+		t.Error("expect error with an invalid key")
+	}
+
+	conflict := errors.NewConflict(schema.GroupResource{Group: "app.alauda.io", Resource: "helmrequests"}, "nginx", nil)
+	client.PrependReactor("patch", "helmrequests", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, conflict
+	})
+	if _, err := UpdateHelmRequestStatus(context.Background(), client, "default/nginx", mutate); !errors.IsConflict(err) {
+		t.Errorf("expect conflict error after retries, got %v", err)
+	}
+}
+
+func TestUpdateStatusStale(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewValidatingClientset(&v1beta1.HelmRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx", ResourceVersion: "100"},
+		Spec:       v1beta1.HelmRequestSpec{Chart: "stable/nginx", ReleaseName: "nginx", Namespace: "default"},
+	})
+
+	// the HelmRequest is changed by others after it's read the first time
+	called := 0
+	hr, err := UpdateHelmRequestStatus(ctx, client, "default/nginx", func(status *v1beta1.HelmRequestStatus) {
+		called++
+		if called == 1 {
+			patch := []byte(`{"metadata":{"labels":{"app":"nginx"}}}`)
+			if _, err := client.AppV1beta1().HelmRequests("default").Patch(ctx, "nginx", types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		status.Phase = v1beta1.HelmRequestSynced
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if called != 2 {
+		t.Errorf("expect mutate called 2 times, got %d", called)
+	}
+	if hr.Status.Phase != v1beta1.HelmRequestSynced || hr.Labels["app"] != "nginx" {
+		t.Errorf("expect phase %s with the labels kept, got %s and %v", v1beta1.HelmRequestSynced, hr.Status.Phase, hr.Labels)
+	}
+}
@@ -0,0 +1,147 @@
+// Package values resolves the effective values of HelmRequests and validates them against the
+// values.schema.json of charts.
+package values
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/chartutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// Resolver resolves the effective values of a HelmRequest
+type Resolver interface {
+	Resolve(ctx context.Context, hr *v1beta1.HelmRequest) (chartutil.Values, error)
+}
+
+// kubeResolver reads ValuesFrom from ConfigMaps and Secrets in the namespace of the HelmRequest
+type kubeResolver struct {
+	getConfigMap func(namespace, name string) (*v1.ConfigMap, error)
+	getSecret    func(namespace, name string) (*v1.Secret, error)
+}
+
+// NewListerResolver creates a Resolver reads ConfigMaps and Secrets from listers
+func NewListerResolver(configMaps corelisters.ConfigMapLister, secrets corelisters.SecretLister) Resolver {
+	return &kubeResolver{
+		getConfigMap: func(namespace, name string) (*v1.ConfigMap, error) {
+			return configMaps.ConfigMaps(namespace).Get(name)
+		},
+		getSecret: func(namespace, name string) (*v1.Secret, error) {
+			return secrets.Secrets(namespace).Get(name)
+		},
+	}
+}
+
+// NewClientResolver creates a Resolver reads ConfigMaps and Secrets from the apiserver, it's for the
+// places without informers, eg: admission webhooks and CLI
+func NewClientResolver(client kubernetes.Interface) Resolver {
+	return &kubeResolver{
+		getConfigMap: func(namespace, name string) (*v1.ConfigMap, error) {
+			return client.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+		},
+		getSecret: func(namespace, name string) (*v1.Secret, error) {
+			return client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+		},
+	}
+}
+
+// Resolve merges the values in ValuesFrom in order, then the values in the spec. Later values override
+// earlier ones, and maps are merged recursively.
+func (r *kubeResolver) Resolve(ctx context.Context, hr *v1beta1.HelmRequest) (chartutil.Values, error) {
+	result := map[string]interface{}{}
+	for i, item := range hr.Spec.ValuesFrom {
+		vals, err := r.resolveSource(hr.Namespace, item)
+		if err != nil {
+			return nil, fmt.Errorf(".spec.valuesFrom[%d]: %s", i, err.Error())
+		}
+		Merge(result, vals)
+	}
+	Merge(result, hr.Spec.Values)
+	return result, nil
+}
+
+func (r *kubeResolver) resolveSource(namespace string, source v1beta1.ValuesFromSource) (map[string]interface{}, error) {
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+		cm, err := r.getConfigMap(namespace, ref.Name)
+		if err != nil {
+			if errors.IsNotFound(err) && isOptional(ref.Optional) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("get configmap %s error: %s", ref.Name, err.Error())
+		}
+		data, ok := cm.Data[ref.Key]
+		if !ok {
+			if isOptional(ref.Optional) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("key %s not found in configmap %s", ref.Key, ref.Name)
+		}
+		return parse(data, "configmap", ref.Name, ref.Key)
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+		secret, err := r.getSecret(namespace, ref.Name)
+		if err != nil {
+			if errors.IsNotFound(err) && isOptional(ref.Optional) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("get secret %s error: %s", ref.Name, err.Error())
+		}
+		data, ok := secret.Data[ref.Key]
+		if !ok {
+			if isOptional(ref.Optional) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("key %s not found in secret %s", ref.Key, ref.Name)
+		}
+		return parse(string(data), "secret", ref.Name, ref.Key)
+	}
+	return nil, nil
+}
+
+func parse(data, kind, name, key string) (map[string]interface{}, error) {
+	vals, err := chartutil.ReadValues([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s of %s %s error: %s", key, kind, name, err.Error())
+	}
+	return vals, nil
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// Merge merges src into dst recursively, values in src override the ones in dst, except that both are
+// maps. Returns dst.
+func Merge(dst, src map[string]interface{}) map[string]interface{} {
+	for k, v := range src {
+		srcMap, ok := asMap(v)
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dstMap, ok := asMap(dst[k])
+		if !ok {
+			dstMap = map[string]interface{}{}
+		}
+		dst[k] = Merge(dstMap, srcMap)
+	}
+	return dst
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case chartutil.Values:
+		return m, true
+	}
+	return nil, false
+}
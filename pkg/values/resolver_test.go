@@ -0,0 +1,155 @@
+package values
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/chartutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newObjects() []runtime.Object {
+	return []runtime.Object{
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "values"},
+			Data: map[string]string{
+				"base.yaml":    "image: {repository: nginx, tag: '1.16'}\nreplicas: 1",
+				"invalid.yaml": "image: [",
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "values"},
+			Data:       map[string][]byte{"secret.yaml": []byte("image: {tag: '1.17'}\npassword: s3cret")},
+		},
+		// the same name in another namespace is never used
+		&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "other"},
+			Data:       map[string]string{"base.yaml": "replicas: 3"},
+		},
+	}
+}
+
+func configMapRef(name, key string, optional bool) v1beta1.ValuesFromSource {
+	return v1beta1.ValuesFromSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: name}, Key: key, Optional: &optional,
+	}}
+}
+
+func secretRef(name, key string, optional bool) v1beta1.ValuesFromSource {
+	return v1beta1.ValuesFromSource{SecretKeyRef: &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: name}, Key: key, Optional: &optional,
+	}}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		valuesFrom []v1beta1.ValuesFromSource
+		values     map[string]interface{}
+		expect     map[string]interface{}
+		err        string
+	}{
+		{name: "none", expect: map[string]interface{}{}},
+		{name: "spec only", values: map[string]interface{}{"replicas": float64(2)}, expect: map[string]interface{}{"replicas": float64(2)}},
+		{
+			name:       "merged in order",
+			valuesFrom: []v1beta1.ValuesFromSource{configMapRef("values", "base.yaml", false), secretRef("values", "secret.yaml", false)},
+			values:     map[string]interface{}{"replicas": float64(2)},
+			expect: map[string]interface{}{
+				"image":    map[string]interface{}{"repository": "nginx", "tag": "1.17"},
+				"password": "s3cret",
+				"replicas": float64(2),
+			},
+		},
+		{
+			name:       "optional missing",
+			valuesFrom: []v1beta1.ValuesFromSource{configMapRef("missing", "base.yaml", true), configMapRef("values", "missing.yaml", true), secretRef("missing", "secret.yaml", true)},
+			expect:     map[string]interface{}{},
+		},
+		{name: "missing configmap", valuesFrom: []v1beta1.ValuesFromSource{configMapRef("other", "base.yaml", false)}, err: ".spec.valuesFrom[0]: get configmap other error"},
+		{name: "missing key", valuesFrom: []v1beta1.ValuesFromSource{configMapRef("values", "base.yaml", false), configMapRef("values", "missing.yaml", false)}, err: ".spec.valuesFrom[1]: key missing.yaml not found in configmap values"},
+		{name: "missing secret", valuesFrom: []v1beta1.ValuesFromSource{secretRef("missing", "secret.yaml", false)}, err: "get secret missing error"},
+		{name: "missing secret key", valuesFrom: []v1beta1.ValuesFromSource{secretRef("values", "missing.yaml", false)}, err: "key missing.yaml not found in secret values"},
+		{name: "invalid", valuesFrom: []v1beta1.ValuesFromSource{configMapRef("values", "invalid.yaml", false)}, err: "parse key invalid.yaml of configmap values error"},
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range newObjects() {
+		if _, ok := obj.(*v1.Secret); ok {
+			secrets.Add(obj)
+		} else {
+			indexer.Add(obj)
+		}
+	}
+	resolvers := map[string]Resolver{
+		"client": NewClientResolver(kubefake.NewSimpleClientset(newObjects()...)),
+		"lister": NewListerResolver(corelisters.NewConfigMapLister(indexer), corelisters.NewSecretLister(secrets)),
+	}
+
+	for name, resolver := range resolvers {
+		for _, tt := range tests {
+			t.Run(name+" "+tt.name, func(t *testing.T) {
+				hr := &v1beta1.HelmRequest{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+					Spec: v1beta1.HelmRequestSpec{
+						ValuesFrom: tt.valuesFrom,
+						HelmValues: v1beta1.HelmValues{Values: tt.values},
+					},
+				}
+				vals, err := resolver.Resolve(context.Background(), hr)
+				if tt.err != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.err) {
+						t.Errorf("expect error %q, got %v", tt.err, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(map[string]interface{}(vals), tt.expect) {
+					t.Errorf("expect %v, got %v", tt.expect, vals)
+				}
+			})
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dst := map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "1.16"},
+		"replicas": 1,
+		"ports":    []interface{}{80},
+		"service":  "ClusterIP",
+	}
+	src := map[string]interface{}{
+		"image":   chartutil.Values{"tag": "1.17"},
+		"ports":   []interface{}{443},
+		"service": map[string]interface{}{"type": "NodePort"},
+		"labels":  map[string]interface{}{"app": "nginx"},
+	}
+	expect := map[string]interface{}{
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "1.17"},
+		"replicas": 1,
+		"ports":    []interface{}{443},
+		"service":  map[string]interface{}{"type": "NodePort"},
+		"labels":   map[string]interface{}{"app": "nginx"},
+	}
+	if got := Merge(dst, src); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %v, got %v", expect, got)
+	}
+
+	// the maps in src are copied
+	src["labels"].(map[string]interface{})["app"] = "changed"
+	if dst["labels"].(map[string]interface{})["app"] != "nginx" {
+		t.Errorf("expect the merged maps not shared with src")
+	}
+}
@@ -0,0 +1,178 @@
+package values
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/xeipuuv/gojsonschema"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/chartutil"
+)
+
+// FieldError is an error of a field in values, addressed by JSON pointer
+type FieldError struct {
+	// Chart is the name of the chart, or the path of the subchart, eg: nginx/redis
+	Chart string `json:"chart"`
+	// Pointer is the JSON pointer of the field in the values of the HelmRequest, eg: /image/tag
+	Pointer string `json:"pointer"`
+	// Type is the type of the error, eg: required, invalid_type
+	Type string `json:"type"`
+	// Message is the human readable description
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, e.Message)
+}
+
+// FieldErrors is a list of FieldError
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	var items []string
+	for _, item := range e {
+		items = append(items, item.String())
+	}
+	return fmt.Sprintf("values are invalid: %s", strings.Join(items, "; "))
+}
+
+// ValidateValues validates the values against the values.schema.json of the chart and its dependencies.
+// The dependencies are processed and the values are coalesced with the default values of the chart first,
+// the same as helm does when installing: aliased subcharts are validated against the values under their
+// aliases, and subcharts disabled by conditions or tags are not validated. The returned FieldErrors is nil
+// if the values are valid, and the error is only for the failures of the validation itself, eg: the
+// schema is malformed.
+func ValidateValues(ch *chart.Chart, vals map[string]interface{}) (FieldErrors, error) {
+	// ProcessDependencies modifies the chart, and both of them modify the values in place
+	ch = copyChart(ch)
+	if err := chartutil.ProcessDependencies(ch, Merge(map[string]interface{}{}, vals)); err != nil {
+		return nil, fmt.Errorf("process dependencies error: %s", err.Error())
+	}
+	coalesced, err := chartutil.CoalesceValues(ch, Merge(map[string]interface{}{}, vals))
+	if err != nil {
+		return nil, fmt.Errorf("coalesce values error: %s", err.Error())
+	}
+	return validateChart(ch, coalesced, ch.Name(), "")
+}
+
+// copyChart copies the chart and its dependencies, except the templates, files and schemas which are
+// never modified
+func copyChart(ch *chart.Chart) *chart.Chart {
+	out := *ch
+	if ch.Metadata != nil {
+		md := *ch.Metadata
+		md.Dependencies = nil
+		for _, d := range ch.Metadata.Dependencies {
+			if d != nil {
+				dep := *d
+				md.Dependencies = append(md.Dependencies, &dep)
+			}
+		}
+		out.Metadata = &md
+	}
+	if ch.Values != nil {
+		out.Values = Merge(map[string]interface{}{}, ch.Values)
+	}
+	var deps []*chart.Chart
+	for _, sub := range ch.Dependencies() {
+		deps = append(deps, copyChart(sub))
+	}
+	out.SetDependencies(deps...)
+	return &out
+}
+
+func validateChart(ch *chart.Chart, vals map[string]interface{}, name, prefix string) (FieldErrors, error) {
+	var result FieldErrors
+	if len(ch.Schema) > 0 {
+		errs, err := validateSchema(ch.Schema, vals)
+		if err != nil {
+			return nil, fmt.Errorf("validate values of chart %s error: %s", name, err.Error())
+		}
+		for _, item := range errs {
+			item.Chart = name
+			item.Pointer = prefix + item.Pointer
+			result = append(result, item)
+		}
+	}
+
+	// the dependencies are processed, they are named by their aliases and only enabled ones are left
+	for _, sub := range ch.Dependencies() {
+		subVals, _ := asMap(vals[sub.Name()])
+		errs, err := validateChart(sub, subVals, name+"/"+sub.Name(), prefix+"/"+escapePointer(sub.Name()))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, errs...)
+	}
+	return result, nil
+}
+
+func validateSchema(schema []byte, vals map[string]interface{}) (FieldErrors, error) {
+	data, err := yaml.Marshal(vals)
+	if err != nil {
+		return nil, err
+	}
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, []byte("null")) {
+		data = []byte("{}")
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, err
+	}
+	if result.Valid() {
+		return nil, nil
+	}
+
+	var errs FieldErrors
+	for _, item := range result.Errors() {
+		path := contextPath(item.Context())
+		// required errors are reported on the parent, point them to the missing field
+		if property, ok := item.Details()["property"].(string); ok && item.Type() == "required" {
+			path = append(path, property)
+		}
+		errs = append(errs, FieldError{
+			Pointer: toPointer(path),
+			Type:    item.Type(),
+			Message: item.Description(),
+		})
+	}
+	return errs, nil
+}
+
+// contextPath returns the path segments of the context, without the root
+func contextPath(ctx *gojsonschema.JsonContext) []string {
+	if ctx == nil {
+		return nil
+	}
+	// use a separator will not appear in keys, so keys contain "." or "/" are kept
+	parts := strings.Split(ctx.String("\x00"), "\x00")
+	if len(parts) > 0 && parts[0] == gojsonschema.STRING_CONTEXT_ROOT {
+		parts = parts[1:]
+	}
+	return parts
+}
+
+func toPointer(path []string) string {
+	var sb strings.Builder
+	for _, p := range path {
+		sb.WriteString("/")
+		sb.WriteString(escapePointer(p))
+	}
+	return sb.String()
+}
+
+// escapePointer escapes a JSON pointer segment, see RFC 6901
+func escapePointer(s string) string {
+	return strings.Replace(strings.Replace(s, "~", "~0", -1), "/", "~1", -1)
+}
@@ -0,0 +1,115 @@
+package values
+
+import (
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/pkg/chart"
+)
+
+const nginxSchema = `{
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "image": {"type": "object", "properties": {"tag": {"type": "string"}}},
+    "replicas": {"type": "integer"},
+    "labels": {"type": "object", "properties": {"app.kubernetes.io/name~": {"type": "string"}}}
+  }
+}`
+
+const portSchema = `{"type": "object", "properties": {"port": {"type": "integer"}}}`
+
+func newChart(name string, vals map[string]interface{}, schema string, deps ...*chart.Dependency) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: name, Version: "1.0.0", APIVersion: "v2", Dependencies: deps},
+		Values:   vals,
+		Schema:   []byte(schema),
+	}
+}
+
+// newNginx creates a chart with redis aliased as cache, and mysql enabled by the database tag
+func newNginx() *chart.Chart {
+	ch := newChart("nginx", map[string]interface{}{"image": map[string]interface{}{"tag": "1.17"}}, nginxSchema,
+		&chart.Dependency{Name: "redis", Version: "1.0.0", Alias: "cache", Condition: "cache.enabled"},
+		&chart.Dependency{Name: "mysql", Version: "1.0.0", Tags: []string{"database"}},
+	)
+	ch.SetDependencies(
+		newChart("redis", map[string]interface{}{"port": 6379, "enabled": true}, portSchema),
+		newChart("mysql", map[string]interface{}{"port": 3306}, portSchema),
+	)
+	return ch
+}
+
+func TestValidateValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		vals   string
+		errors []FieldError
+	}{
+		{name: "defaults"},
+		{name: "invalid type", vals: "replicas: two", errors: []FieldError{{Chart: "nginx", Pointer: "/replicas", Type: "invalid_type"}}},
+		{name: "required removed by null", vals: "image: null", errors: []FieldError{{Chart: "nginx", Pointer: "/image", Type: "required"}}},
+		{name: "escaped pointer", vals: "labels: {app.kubernetes.io/name~: 1}", errors: []FieldError{{Chart: "nginx", Pointer: "/labels/app.kubernetes.io~1name~0", Type: "invalid_type"}}},
+		{name: "alias", vals: "cache: {port: x}", errors: []FieldError{{Chart: "nginx/cache", Pointer: "/cache/port", Type: "invalid_type"}}},
+		{name: "chart name is not the key of alias", vals: "redis: {port: x}"},
+		{name: "disabled by condition", vals: "cache: {enabled: false, port: x}"},
+		{name: "enabled by condition", vals: "cache: {enabled: true, port: x}", errors: []FieldError{{Chart: "nginx/cache", Pointer: "/cache/port", Type: "invalid_type"}}},
+		{name: "disabled by tags", vals: "tags: {database: false}\nmysql: {port: x}"},
+		{name: "enabled by tags", vals: "mysql: {port: x}", errors: []FieldError{{Chart: "nginx/mysql", Pointer: "/mysql/port", Type: "invalid_type"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := newNginx()
+			vals, err := parse(tt.vals, "test", "test", "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			original := Merge(map[string]interface{}{}, vals)
+
+			errs, err := ValidateValues(ch, vals)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != len(tt.errors) {
+				t.Fatalf("expect errors %v, got %v", tt.errors, errs)
+			}
+			for i, expect := range tt.errors {
+				got := errs[i]
+				if got.Chart != expect.Chart || got.Pointer != expect.Pointer || got.Type != expect.Type || got.Message == "" {
+					t.Errorf("expect error %+v, got %+v", expect, got)
+				}
+			}
+
+			// neither the chart nor the values are modified
+			if !reflect.DeepEqual(vals, original) {
+				t.Errorf("expect values %v, got %v", original, vals)
+			}
+			if !reflect.DeepEqual(ch, newNginx()) {
+				t.Errorf("expect chart not modified, got %+v", ch.Metadata)
+			}
+		})
+	}
+}
+
+func TestValidateValuesMalformedSchema(t *testing.T) {
+	ch := newChart("nginx", nil, `{"type": "object", "properties": {"port": {"type": 1}}}`)
+	if _, err := ValidateValues(ch, map[string]interface{}{"port": 80}); err == nil {
+		t.Error("expect error for malformed schema")
+	}
+	// charts without schemas are always valid
+	errs, err := ValidateValues(newChart("nginx", nil, ""), map[string]interface{}{"port": "x"})
+	if err != nil || errs != nil {
+		t.Errorf("expect valid, got %v, %v", errs, err)
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	errs := FieldErrors{
+		{Pointer: "", Message: "Invalid type"},
+		{Pointer: "/image/tag", Message: "tag is required"},
+	}
+	expect := "values are invalid: /: Invalid type; /image/tag: tag is required"
+	if errs.Error() != expect {
+		t.Errorf("expect %q, got %q", expect, errs.Error())
+	}
+}
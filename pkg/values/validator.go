@@ -0,0 +1,85 @@
+package values
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/chart"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ReasonValuesValid is the reason of the ValuesValid condition when the values are valid
+	ReasonValuesValid = "SchemaValidated"
+	// ReasonValuesInvalid is the reason of the ValuesValid condition when the values are invalid
+	ReasonValuesInvalid = "SchemaViolated"
+)
+
+// ChartLoader loads the chart a HelmRequest refers to
+type ChartLoader interface {
+	Load(ctx context.Context, hr *v1beta1.HelmRequest) (*chart.Chart, error)
+}
+
+// ChartLoaderFunc is a function implements ChartLoader
+type ChartLoaderFunc func(ctx context.Context, hr *v1beta1.HelmRequest) (*chart.Chart, error)
+
+// Load implements ChartLoader
+func (f ChartLoaderFunc) Load(ctx context.Context, hr *v1beta1.HelmRequest) (*chart.Chart, error) {
+	return f(ctx, hr)
+}
+
+// Validator validates the effective values of HelmRequests against the schemas of their charts. It can
+// be used by the controller before installing, or by the admission webhook as a pre-flight check.
+type Validator struct {
+	Charts ChartLoader
+	Values Resolver
+}
+
+// NewValidator creates a Validator
+func NewValidator(charts ChartLoader, values Resolver) *Validator {
+	return &Validator{Charts: charts, Values: values}
+}
+
+// Validate loads the chart and resolves the values of the HelmRequest, then validates them. The returned
+// FieldErrors is nil if the values are valid, and the error is for failures before the validation, eg:
+// the chart can not be loaded.
+func (v *Validator) Validate(ctx context.Context, hr *v1beta1.HelmRequest) (FieldErrors, error) {
+	ch, err := v.Charts.Load(ctx, hr)
+	if err != nil {
+		return nil, fmt.Errorf("load chart %s error: %s", hr.Spec.Chart, err.Error())
+	}
+	vals, err := v.Values.Resolve(ctx, hr)
+	if err != nil {
+		return nil, fmt.Errorf("resolve values error: %s", err.Error())
+	}
+	return ValidateValues(ch, vals)
+}
+
+// Check is Validate returns a single error, for admission
+func (v *Validator) Check(ctx context.Context, hr *v1beta1.HelmRequest) error {
+	errs, err := v.Validate(ctx, hr)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// SetCondition sets the ValuesValid condition of the HelmRequest by the validation result
+func SetCondition(hr *v1beta1.HelmRequest, errs FieldErrors, now time.Time) {
+	c := v1beta1.HelmRequestCondition{
+		Type:   v1beta1.HelmRequestValuesValid,
+		Status: v1.ConditionTrue,
+		Reason: ReasonValuesValid,
+	}
+	if len(errs) > 0 {
+		c.Status = v1.ConditionFalse
+		c.Reason = ReasonValuesInvalid
+		c.Message = errs.Error()
+	}
+	hr.Status.SetCondition(c, now)
+}
@@ -0,0 +1,105 @@
+package values
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/chart"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidator(t *testing.T) {
+	loader := ChartLoaderFunc(func(ctx context.Context, hr *v1beta1.HelmRequest) (*chart.Chart, error) {
+		if hr.Spec.Chart != "stable/nginx" {
+			return nil, errors.New("not found")
+		}
+		return newNginx(), nil
+	})
+	validator := NewValidator(loader, NewClientResolver(kubefake.NewSimpleClientset(newObjects()...)))
+
+	tests := []struct {
+		name       string
+		chart      string
+		valuesFrom []v1beta1.ValuesFromSource
+		values     map[string]interface{}
+		errors     []FieldError
+		err        string
+	}{
+		{name: "valid", chart: "stable/nginx", valuesFrom: []v1beta1.ValuesFromSource{configMapRef("values", "base.yaml", false)}},
+		{
+			name:   "invalid",
+			chart:  "stable/nginx",
+			values: map[string]interface{}{"replicas": "two"},
+			errors: []FieldError{{Chart: "nginx", Pointer: "/replicas", Type: "invalid_type"}},
+		},
+		{name: "chart not found", chart: "stable/redis", err: "load chart stable/redis error: not found"},
+		{name: "values not resolved", chart: "stable/nginx", valuesFrom: []v1beta1.ValuesFromSource{configMapRef("missing", "base.yaml", false)}, err: "resolve values error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hr := &v1beta1.HelmRequest{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+				Spec: v1beta1.HelmRequestSpec{
+					Chart:      tt.chart,
+					ValuesFrom: tt.valuesFrom,
+					HelmValues: v1beta1.HelmValues{Values: tt.values},
+				},
+			}
+			errs, err := validator.Validate(context.Background(), hr)
+			checkErr := validator.Check(context.Background(), hr)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("expect error %q, got %v", tt.err, err)
+				}
+				if checkErr == nil || checkErr.Error() != err.Error() {
+					t.Errorf("expect check error %v, got %v", err, checkErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != len(tt.errors) {
+				t.Fatalf("expect errors %v, got %v", tt.errors, errs)
+			}
+			for i := range errs {
+				if errs[i].Chart != tt.errors[i].Chart || errs[i].Pointer != tt.errors[i].Pointer || errs[i].Type != tt.errors[i].Type {
+					t.Errorf("expect error %v, got %v", tt.errors[i], errs[i])
+				}
+			}
+			if len(tt.errors) == 0 && checkErr != nil {
+				t.Errorf("expect no check error, got %v", checkErr)
+			}
+			if len(tt.errors) > 0 && (checkErr == nil || checkErr.Error() != errs.Error()) {
+				t.Errorf("expect check error %v, got %v", errs, checkErr)
+			}
+		})
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	hr := &v1beta1.HelmRequest{}
+	now := time.Now()
+
+	errs := FieldErrors{{Chart: "nginx", Pointer: "/replicas", Type: "invalid_type", Message: "Invalid type"}}
+	SetCondition(hr, errs, now)
+	c := hr.Status.GetCondition(v1beta1.HelmRequestValuesValid)
+	if c == nil || c.Status != v1.ConditionFalse || c.Reason != ReasonValuesInvalid || c.Message != errs.Error() {
+		t.Fatalf("expect condition false with reason %s, got %v", ReasonValuesInvalid, c)
+	}
+
+	SetCondition(hr, nil, now.Add(time.Minute))
+	c = hr.Status.GetCondition(v1beta1.HelmRequestValuesValid)
+	if c == nil || c.Status != v1.ConditionTrue || c.Reason != ReasonValuesValid || c.Message != "" {
+		t.Fatalf("expect condition true with reason %s, got %v", ReasonValuesValid, c)
+	}
+	if len(hr.Status.Conditions) != 1 {
+		t.Errorf("expect 1 condition, got %d", len(hr.Status.Conditions))
+	}
+}
@@ -0,0 +1,124 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/repo"
+)
+
+// Downloader downloads chart archives from a ChartRepo and verifies them
+type Downloader struct {
+	// Client is the http client to use, default to http.DefaultClient
+	Client *http.Client
+	// Username and Password are the basic auth of the repo. They are only sent to the same scheme and
+	// host as the repo url unless PassCredentialsAll is true, so an absolute chart url in the index can't
+	// collect them.
+	Username string
+	Password string
+	// PassCredentialsAll sends the credentials to all the hosts, the same as helm's --pass-credentials
+	PassCredentialsAll bool
+	// Verifier verifies the downloaded archives, nil means no verification
+	Verifier *Verifier
+}
+
+// Download downloads the chart version from the repo at repoURL and verifies it. The verification
+// result is always returned if the archive is downloaded, and an error is returned if the verification
+// failed, so callers refuse unverified charts by default. Nothing is checked in None mode.
+func (d *Downloader) Download(ctx context.Context, repoURL string, cv *repo.ChartVersion) ([]byte, *v1beta1.ChartVerificationStatus, error) {
+	if len(cv.URLs) == 0 {
+		return nil, nil, fmt.Errorf("no url found for chart %s-%s", cv.Name, cv.Version)
+	}
+	chartURL, err := resolveURL(repoURL, cv.URLs[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	auth := d.PassCredentialsAll || sameOrigin(repoURL, chartURL)
+	archive, err := d.get(ctx, chartURL, auth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	verifier := d.Verifier
+	if verifier == nil {
+		verifier = &Verifier{Mode: v1beta1.ChartVerificationNone}
+	}
+
+	var prov []byte
+	if verifier.Mode == v1beta1.ChartVerificationProvenance {
+		prov, err = d.get(ctx, chartURL+".prov", auth)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	u, _ := url.Parse(chartURL)
+	result := verifier.Verify(path.Base(u.Path), archive, cv.Digest, prov)
+	if verifier.Mode != v1beta1.ChartVerificationNone && !result.Verified {
+		return archive, result, fmt.Errorf("chart %s-%s is not verified: %s", cv.Name, cv.Version, result.Reason)
+	}
+	return archive, result, nil
+}
+
+// get downloads the url, the credentials are only sent if auth is true
+func (d *Downloader) get(ctx context.Context, u string, auth bool) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if auth && d.Username != "" {
+		req.SetBasicAuth(d.Username, d.Password)
+	}
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s error: %s", u, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// resolveURL resolves chart url relative to the repo url, like helm does
+func resolveURL(repoURL, chartURL string) (string, error) {
+	ref, err := url.Parse(chartURL)
+	if err != nil {
+		return "", fmt.Errorf("parse chart url %s error: %s", chartURL, err.Error())
+	}
+	if ref.IsAbs() {
+		return chartURL, nil
+	}
+	base, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("parse repo url %s error: %s", repoURL, err.Error())
+	}
+	base.Path = path.Clean(base.Path) + "/"
+	return base.ResolveReference(ref).String(), nil
+}
+
+// sameOrigin checks if the chart url has the same scheme and host(including the port) as the repo url
+func sameOrigin(repoURL, chartURL string) bool {
+	r, err := url.Parse(repoURL)
+	if err != nil {
+		return false
+	}
+	c, err := url.Parse(chartURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(r.Scheme, c.Scheme) && strings.EqualFold(r.Host, c.Host)
+}
@@ -0,0 +1,166 @@
+// Package verify verifies downloaded chart archives against the digest in the repo index or their
+// provenance files, according to the ChartVerification of the ChartRepo.
+package verify
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"golang.org/x/crypto/openpgp"
+	"helm.sh/helm/pkg/provenance"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeyringKey is the key of the keyring in the Secret referenced by ChartVerification.Keyring
+const KeyringKey = "keyring"
+
+// Verifier verifies chart archives
+type Verifier struct {
+	Mode    v1beta1.ChartVerificationMode
+	keyring openpgp.EntityList
+}
+
+// New creates a Verifier for the ChartRepo. The keyring Secret is only required in Provenance mode
+func New(cr *v1beta1.ChartRepo, keyring *v1.Secret) (*Verifier, error) {
+	mode := cr.GetVerificationMode()
+	v := &Verifier{Mode: mode}
+	if mode != v1beta1.ChartVerificationProvenance {
+		return v, nil
+	}
+
+	if keyring == nil {
+		return nil, fmt.Errorf("keyring secret is required for chartrepo %s/%s", cr.GetNamespace(), cr.GetName())
+	}
+	data, ok := keyring.Data[KeyringKey]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in secret %s/%s", KeyringKey, keyring.GetNamespace(), keyring.GetName())
+	}
+	ring, err := ReadKeyring(data)
+	if err != nil {
+		return nil, err
+	}
+	v.keyring = ring
+	return v, nil
+}
+
+// ReadKeyring reads a keyring in binary or armored format
+func ReadKeyring(data []byte) (openpgp.EntityList, error) {
+	ring, err := openpgp.ReadKeyRing(bytes.NewReader(data))
+	if err == nil {
+		return ring, nil
+	}
+	ring, armorErr := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if armorErr != nil {
+		return nil, fmt.Errorf("read keyring error: %s", err.Error())
+	}
+	return ring, nil
+}
+
+// Verify verifies a chart archive. name is the archive file name, it should be the same as the one
+// in the provenance file. digest is from the repo index, prov is the content of the provenance file.
+// The result is always returned, Reason will be set if the verification failed. Verified is always false
+// in None mode.
+func (v *Verifier) Verify(name string, archive []byte, digest string, prov []byte) *v1beta1.ChartVerificationStatus {
+	result := &v1beta1.ChartVerificationStatus{
+		Mode: v.Mode,
+		Time: metav1.NewTime(time.Now()),
+	}
+
+	sum, err := provenance.Digest(bytes.NewReader(archive))
+	if err != nil {
+		return fail(result, err)
+	}
+	result.Digest = sum
+
+	switch v.Mode {
+	case v1beta1.ChartVerificationNone:
+		// nothing is checked, so it's not verified, the same as Chart.IsVersionVerified
+		result.Verified = false
+	case v1beta1.ChartVerificationDigest:
+		if err := checkDigest(sum, digest); err != nil {
+			return fail(result, err)
+		}
+		result.Verified = true
+	case v1beta1.ChartVerificationProvenance:
+		if digest != "" {
+			if err := checkDigest(sum, digest); err != nil {
+				return fail(result, err)
+			}
+		}
+		if len(prov) == 0 {
+			return fail(result, fmt.Errorf("provenance file of %s not found", name))
+		}
+		signer, err := v.verifyProvenance(name, archive, prov)
+		if err != nil {
+			return fail(result, err)
+		}
+		result.SignedBy = identity(signer)
+		result.Fingerprint = fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint)
+		result.Verified = true
+	default:
+		return fail(result, fmt.Errorf("unknown verification mode: %s", v.Mode))
+	}
+	return result
+}
+
+// verifyProvenance use helm's Signatory to check the signature and the hash in the provenance file
+func (v *Verifier) verifyProvenance(name string, archive, prov []byte) (*openpgp.Entity, error) {
+	dir, err := ioutil.TempDir("", "chart-verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	chartPath := filepath.Join(dir, filepath.Base(name))
+	if err := ioutil.WriteFile(chartPath, archive, 0600); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(chartPath+".prov", prov, 0600); err != nil {
+		return nil, err
+	}
+
+	sig := &provenance.Signatory{KeyRing: v.keyring}
+	ver, err := sig.Verify(chartPath, chartPath+".prov")
+	if err != nil {
+		return nil, err
+	}
+	return ver.SignedBy, nil
+}
+
+func checkDigest(sum, expected string) error {
+	expected = strings.TrimPrefix(expected, "sha256:")
+	if expected == "" {
+		return fmt.Errorf("no digest found in repo index")
+	}
+	if !strings.EqualFold(sum, expected) {
+		return fmt.Errorf("digest mismatch: expect %s, got %s", expected, sum)
+	}
+	return nil
+}
+
+// identity returns the first identity name of the entity in order, so it's stable
+func identity(e *openpgp.Entity) string {
+	var names []string
+	for name := range e.Identities {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+func fail(result *v1beta1.ChartVerificationStatus, err error) *v1beta1.ChartVerificationStatus {
+	result.Verified = false
+	result.Reason = err.Error()
+	return result
+}
@@ -0,0 +1,166 @@
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"helm.sh/helm/pkg/chart"
+	"helm.sh/helm/pkg/repo"
+)
+
+func TestVerify(t *testing.T) {
+	archive := []byte("chart archive")
+	digest := fmt.Sprintf("%x", sha256.Sum256(archive))
+
+	tests := []struct {
+		name     string
+		mode     v1beta1.ChartVerificationMode
+		digest   string
+		verified bool
+		reason   bool
+	}{
+		{name: "none is never verified", mode: v1beta1.ChartVerificationNone, digest: digest},
+		{name: "digest matches", mode: v1beta1.ChartVerificationDigest, digest: digest, verified: true},
+		{name: "digest with prefix", mode: v1beta1.ChartVerificationDigest, digest: "sha256:" + digest, verified: true},
+		{name: "digest mismatch", mode: v1beta1.ChartVerificationDigest, digest: "0000", reason: true},
+		{name: "no digest", mode: v1beta1.ChartVerificationDigest, reason: true},
+		{name: "no provenance", mode: v1beta1.ChartVerificationProvenance, digest: digest, reason: true},
+		{name: "unknown mode", mode: "Unknown", reason: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Verifier{Mode: tt.mode}
+			result := v.Verify("nginx-0.1.0.tgz", archive, tt.digest, nil)
+			if result.Verified != tt.verified {
+				t.Errorf("expect verified %v, got %v", tt.verified, result.Verified)
+			}
+			if (result.Reason != "") != tt.reason {
+				t.Errorf("unexpected reason %q", result.Reason)
+			}
+			if result.Mode != tt.mode || result.Digest != digest {
+				t.Errorf("unexpected result %+v", result)
+			}
+
+			// the result agrees with Chart.IsVersionVerified
+			ch := &v1beta1.Chart{Status: v1beta1.ChartStatus{Versions: []v1beta1.ChartVersionStatus{
+				{Version: "0.1.0", Available: true, Verification: result},
+			}}}
+			if ch.IsVersionVerified("0.1.0") != tt.verified {
+				t.Errorf("IsVersionVerified disagrees with the result")
+			}
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+	archive := []byte("chart archive")
+	digest := fmt.Sprintf("%x", sha256.Sum256(archive))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/charts/nginx-0.1.0.tgz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(archive)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		digest   string
+		err      bool
+	}{
+		{name: "no verifier", digest: "0000"},
+		{name: "none", verifier: &Verifier{Mode: v1beta1.ChartVerificationNone}, digest: "0000"},
+		{name: "digest", verifier: &Verifier{Mode: v1beta1.ChartVerificationDigest}, digest: digest},
+		{name: "digest mismatch", verifier: &Verifier{Mode: v1beta1.ChartVerificationDigest}, digest: "0000", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Downloader{Verifier: tt.verifier}
+			cv := &repo.ChartVersion{
+				Metadata: &chart.Metadata{Name: "nginx", Version: "0.1.0"},
+				URLs:     []string{"charts/nginx-0.1.0.tgz"},
+				Digest:   tt.digest,
+			}
+			data, result, err := d.Download(context.Background(), srv.URL, cv)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(data) != string(archive) || result == nil {
+				t.Errorf("expect the archive and the result to be returned")
+			}
+		})
+	}
+}
+
+func TestDownloadCredentials(t *testing.T) {
+	archive := []byte("chart archive")
+	// auth records the basic auth received by each server
+	auth := map[string]string{}
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, _ := r.BasicAuth()
+			auth[name] = user + ":" + password
+			w.Write(archive)
+		}))
+	}
+	repoServer, otherServer := newServer("repo"), newServer("other")
+	defer repoServer.Close()
+	defer otherServer.Close()
+
+	tests := []struct {
+		name       string
+		chartURL   string
+		passAll    bool
+		server     string
+		credential string
+	}{
+		{name: "relative", chartURL: "charts/nginx-0.1.0.tgz", server: "repo", credential: "admin:s3cret"},
+		{name: "absolute on the repo host", chartURL: repoServer.URL + "/charts/nginx-0.1.0.tgz", server: "repo", credential: "admin:s3cret"},
+		{name: "other host", chartURL: otherServer.URL + "/nginx-0.1.0.tgz", server: "other", credential: ":"},
+		{name: "pass credentials to all", chartURL: otherServer.URL + "/nginx-0.1.0.tgz", passAll: true, server: "other", credential: "admin:s3cret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k := range auth {
+				delete(auth, k)
+			}
+			d := &Downloader{Username: "admin", Password: "s3cret", PassCredentialsAll: tt.passAll}
+			cv := &repo.ChartVersion{
+				Metadata: &chart.Metadata{Name: "nginx", Version: "0.1.0"},
+				URLs:     []string{tt.chartURL},
+			}
+			if _, _, err := d.Download(context.Background(), repoServer.URL, cv); err != nil {
+				t.Fatal(err)
+			}
+			if auth[tt.server] != tt.credential {
+				t.Errorf("expect %s to receive %q, got %q", tt.server, tt.credential, auth[tt.server])
+			}
+		})
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		chartURL string
+		same     bool
+	}{
+		{chartURL: "https://charts.example.com/stable/nginx-0.1.0.tgz", same: true},
+		{chartURL: "HTTPS://Charts.Example.com/nginx-0.1.0.tgz", same: true},
+		{chartURL: "http://charts.example.com/stable/nginx-0.1.0.tgz", same: false},
+		{chartURL: "https://charts.example.com:8443/stable/nginx-0.1.0.tgz", same: false},
+		{chartURL: "https://evil.example.com/stable/nginx-0.1.0.tgz", same: false},
+		{chartURL: "https://charts.example.com.evil.com/nginx-0.1.0.tgz", same: false},
+	}
+	for _, tt := range tests {
+		if same := sameOrigin("https://charts.example.com/stable", tt.chartURL); same != tt.same {
+			t.Errorf("%s: expect same origin %v, got %v", tt.chartURL, tt.same, same)
+		}
+	}
+}
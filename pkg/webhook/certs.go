@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+// CertWatcher loads the serving certificate from disk and reloads it when the files change, so the
+// certificate mounted from a Secret can be rotated without restarting the server
+type CertWatcher struct {
+	certFile string
+	keyFile  string
+
+	lock    sync.RWMutex
+	cert    *tls.Certificate
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// NewCertWatcher creates a CertWatcher and loads the certificate, returns an error if it's invalid
+func NewCertWatcher(certFile, keyFile string) (*CertWatcher, error) {
+	w := &CertWatcher{certFile: certFile, keyFile: keyFile}
+	if err := w.reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// GetCertificate returns the current certificate, it's used as tls.Config.GetCertificate
+func (w *CertWatcher) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.cert, nil
+}
+
+// Run checks the files every interval until ctx is done. If the new files are invalid, eg: only one
+// of them is updated, the current certificate is kept and they are checked again next time.
+func (w *CertWatcher) Run(ctx context.Context, interval time.Duration) {
+	wait.Until(func() {
+		if err := w.reload(); err != nil {
+			klog.Errorf("reload certificate error: %s", err.Error())
+		}
+	}, interval, ctx.Done())
+}
+
+func (w *CertWatcher) reload() error {
+	certPEM, err := ioutil.ReadFile(w.certFile)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := ioutil.ReadFile(w.keyFile)
+	if err != nil {
+		return err
+	}
+
+	w.lock.RLock()
+	unchanged := bytes.Equal(certPEM, w.certPEM) && bytes.Equal(keyPEM, w.keyPEM)
+	w.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("load certificate %s and key %s error: %s", w.certFile, w.keyFile, err.Error())
+	}
+
+	w.lock.Lock()
+	w.cert, w.certPEM, w.keyPEM = &cert, certPEM, keyPEM
+	w.lock.Unlock()
+	klog.Infof("certificate loaded from %s", w.certFile)
+	return nil
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newKeyPair returns a self-signed certificate and its key in PEM
+func newKeyPair(t *testing.T, name string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commonName returns the common name of the certificate served by the watcher
+func commonName(t *testing.T, w *CertWatcher) string {
+	cert, err := w.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	if _, err := NewCertWatcher(certFile, keyFile); err == nil {
+		t.Error("expect error without the files")
+	}
+
+	oldCert, oldKey := newKeyPair(t, "old")
+	writeFile(t, certFile, oldCert)
+	writeFile(t, keyFile, oldKey)
+	w, err := NewCertWatcher(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := commonName(t, w); name != "old" {
+		t.Fatalf("expect certificate old, got %s", name)
+	}
+
+	// only the certificate is rotated, the old one is kept until the key is updated
+	newCert, newKey := newKeyPair(t, "new")
+	writeFile(t, certFile, newCert)
+	if err := w.reload(); err == nil {
+		t.Error("expect error with a mismatched key")
+	}
+	if name := commonName(t, w); name != "old" {
+		t.Errorf("expect certificate old, got %s", name)
+	}
+
+	writeFile(t, keyFile, newKey)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx, 10*time.Millisecond)
+	deadline := time.Now().Add(5 * time.Second)
+	for commonName(t, w) != "new" {
+		if time.Now().After(deadline) {
+			t.Fatal("expect certificate new after rotated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultConfigurationName is the default name of the webhook configurations
+const DefaultConfigurationName = "captain-webhook"
+
+// ConfigOptions is how the api server reaches the webhook server
+type ConfigOptions struct {
+	// Name is the name of the webhook configurations, default to DefaultConfigurationName
+	Name string
+	// ServiceNamespace and ServiceName are the Service in front of the webhook server
+	ServiceNamespace string
+	ServiceName      string
+	// ServicePort is the port of the Service, default to 443
+	ServicePort int32
+	// URL is used instead of the Service if it's not empty, eg: https://captain.example.com:8443, the
+	// webhook paths are appended to it
+	URL string
+	// CABundle is the PEM encoded CA to verify the serving certificate
+	CABundle []byte
+	// FailurePolicy default to Fail
+	FailurePolicy admissionregistrationv1beta1.FailurePolicyType
+}
+
+func (o *ConfigOptions) name() string {
+	if o.Name == "" {
+		return DefaultConfigurationName
+	}
+	return o.Name
+}
+
+func (o *ConfigOptions) clientConfig(path string) admissionregistrationv1beta1.WebhookClientConfig {
+	if o.URL != "" {
+		url := o.URL + path
+		return admissionregistrationv1beta1.WebhookClientConfig{URL: &url, CABundle: o.CABundle}
+	}
+
+	port := o.ServicePort
+	if port == 0 {
+		port = 443
+	}
+	return admissionregistrationv1beta1.WebhookClientConfig{
+		Service: &admissionregistrationv1beta1.ServiceReference{
+			Namespace: o.ServiceNamespace,
+			Name:      o.ServiceName,
+			Path:      &path,
+			Port:      &port,
+		},
+		CABundle: o.CABundle,
+	}
+}
+
+func (o *ConfigOptions) failurePolicy() *admissionregistrationv1beta1.FailurePolicyType {
+	policy := o.FailurePolicy
+	if policy == "" {
+		policy = admissionregistrationv1beta1.Fail
+	}
+	return &policy
+}
+
+func rule(kind Kind, versions []string, operations ...admissionregistrationv1beta1.OperationType) admissionregistrationv1beta1.RuleWithOperations {
+	scope := kind.Scope
+	return admissionregistrationv1beta1.RuleWithOperations{
+		Operations: operations,
+		Rule: admissionregistrationv1beta1.Rule{
+			APIGroups:   []string{v1beta1.SchemeGroupVersion.Group},
+			APIVersions: versions,
+			Resources:   []string{kind.Resource},
+			Scope:       &scope,
+		},
+	}
+}
+
+// MutatingWebhookConfiguration returns the MutatingWebhookConfiguration registers a webhook for each kind
+// with mutating versions
+func MutatingWebhookConfiguration(opts ConfigOptions) *admissionregistrationv1beta1.MutatingWebhookConfiguration {
+	config := &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1beta1.SchemeGroupVersion.String(),
+			Kind:       "MutatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.name()},
+	}
+
+	exact := admissionregistrationv1beta1.Exact
+	none := admissionregistrationv1beta1.SideEffectClassNone
+	for _, kind := range Kinds() {
+		if len(kind.MutatingVersions) == 0 {
+			continue
+		}
+		config.Webhooks = append(config.Webhooks, admissionregistrationv1beta1.MutatingWebhook{
+			Name:         MutatingWebhookName(kind.Resource),
+			ClientConfig: opts.clientConfig(MutatePath),
+			Rules: []admissionregistrationv1beta1.RuleWithOperations{
+				rule(kind, kind.MutatingVersions, admissionregistrationv1beta1.Create, admissionregistrationv1beta1.Update),
+			},
+			FailurePolicy:           opts.failurePolicy(),
+			MatchPolicy:             &exact,
+			SideEffects:             &none,
+			AdmissionReviewVersions: []string{"v1beta1"},
+		})
+	}
+	return config
+}
+
+// ValidatingWebhookConfiguration returns the ValidatingWebhookConfiguration registers a webhook for each
+// kind with validating versions
+func ValidatingWebhookConfiguration(opts ConfigOptions) *admissionregistrationv1beta1.ValidatingWebhookConfiguration {
+	config := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionregistrationv1beta1.SchemeGroupVersion.String(),
+			Kind:       "ValidatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.name()},
+	}
+
+	exact := admissionregistrationv1beta1.Exact
+	none := admissionregistrationv1beta1.SideEffectClassNone
+	for _, kind := range Kinds() {
+		if len(kind.ValidatingVersions) == 0 {
+			continue
+		}
+		config.Webhooks = append(config.Webhooks, admissionregistrationv1beta1.ValidatingWebhook{
+			Name:         ValidatingWebhookName(kind.Resource),
+			ClientConfig: opts.clientConfig(ValidatePath),
+			Rules: []admissionregistrationv1beta1.RuleWithOperations{
+				rule(kind, kind.ValidatingVersions, admissionregistrationv1beta1.Create,
+					admissionregistrationv1beta1.Update, admissionregistrationv1beta1.Delete),
+			},
+			FailurePolicy:           opts.failurePolicy(),
+			MatchPolicy:             &exact,
+			SideEffects:             &none,
+			AdmissionReviewVersions: []string{"v1beta1"},
+		})
+	}
+	return config
+}
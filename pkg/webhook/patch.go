@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// operation is a json patch operation, see RFC 6902
+type operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON omits the value of remove operations, the value of others should be kept even if it's empty
+func (o operation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(map[string]string{"op": o.Op, "path": o.Path})
+	}
+	type plain operation
+	return json.Marshal(plain(o))
+}
+
+// createPatch returns the json patch from original to modified, nil if they are the same. Objects are
+// compared by fields, other values(including arrays) are replaced as a whole.
+func createPatch(original, modified []byte) ([]byte, error) {
+	var a, b interface{}
+	if err := json.Unmarshal(original, &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(modified, &b); err != nil {
+		return nil, err
+	}
+
+	ops := diff("", a, b)
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(ops)
+}
+
+func diff(path string, a, b interface{}) []operation {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		return []operation{{Op: "replace", Path: path, Value: b}}
+	}
+
+	var ops []operation
+	for _, key := range sortedKeys(am) {
+		if _, ok := bm[key]; !ok {
+			ops = append(ops, operation{Op: "remove", Path: path + "/" + escape(key)})
+		}
+	}
+	for _, key := range sortedKeys(bm) {
+		av, ok := am[key]
+		if !ok {
+			ops = append(ops, operation{Op: "add", Path: path + "/" + escape(key), Value: bm[key]})
+			continue
+		}
+		ops = append(ops, diff(path+"/"+escape(key), av, bm[key])...)
+	}
+	return ops
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escape escapes a key in json pointer, eg: annotation keys contain "/"
+func escape(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
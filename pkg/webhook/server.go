@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const (
+	// DefaultAddr is the default address the webhook server listens on
+	DefaultAddr = ":8443"
+	// DefaultCertReloadInterval is how often the certificate files are checked by default
+	DefaultCertReloadInterval = time.Minute
+)
+
+// Server serves the webhooks over https
+type Server struct {
+	// Addr is the address to listen on, default to DefaultAddr
+	Addr string
+	// CertFile and KeyFile are the paths of the serving certificate and key, they are reloaded on change
+	CertFile string
+	KeyFile  string
+	// CertReloadInterval is how often the certificate files are checked, default to DefaultCertReloadInterval
+	CertReloadInterval time.Duration
+}
+
+// Run serves the webhooks until ctx is done
+func (s *Server) Run(ctx context.Context) error {
+	certs, err := NewCertWatcher(s.CertFile, s.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	interval := s.CertReloadInterval
+	if interval <= 0 {
+		interval = DefaultCertReloadInterval
+	}
+	go certs.Run(ctx, interval)
+
+	addr := s.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: NewHandler(),
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		klog.Infof("webhook server listening on %s", addr)
+		errCh <- srv.ListenAndServeTLS("", "")
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	klog.Info("shutting down webhook server")
+	shutdownCtx, stop := context.WithTimeout(context.Background(), 10*time.Second)
+	defer stop()
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,290 @@
+// Package webhook serves the admission webhooks of the app group. The kinds implementing Default() get a
+// mutating webhook, and the ones implementing ValidateCreate/ValidateUpdate/ValidateDelete get a validating
+// webhook, the same as controller-runtime does, but without depending on a manager.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1alpha1"
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog"
+)
+
+const (
+	// MutatePath is the path of the mutating webhook, it handles all the kinds
+	MutatePath = "/mutate"
+	// ValidatePath is the path of the validating webhook, it handles all the kinds
+	ValidatePath = "/validate"
+	// HealthzPath always returns ok, it can be used as the probe of the webhook server
+	HealthzPath = "/healthz"
+)
+
+// Defaulter is implemented by the kinds with a mutating webhook
+type Defaulter interface {
+	runtime.Object
+	Default()
+}
+
+// Validator is implemented by the kinds with a validating webhook
+type Validator interface {
+	runtime.Object
+	ValidateCreate() error
+	ValidateUpdate(old runtime.Object) error
+	ValidateDelete() error
+}
+
+// Kind is a webhook enabled kind
+type Kind struct {
+	Kind     string
+	Resource string
+	Scope    admissionregistrationv1beta1.ScopeType
+	// MutatingVersions and ValidatingVersions are the versions implementing Defaulter and Validator
+	MutatingVersions   []string
+	ValidatingVersions []string
+}
+
+// versions are the versions of the app group, from the oldest to the newest
+var versions = []schema.GroupVersion{v1alpha1.SchemeGroupVersion, v1beta1.SchemeGroupVersion}
+
+// Kinds returns the webhook enabled kinds
+func Kinds() []Kind {
+	kinds := []Kind{
+		{Kind: "HelmRequest", Resource: "helmrequests", Scope: admissionregistrationv1beta1.NamespacedScope},
+		{Kind: "ChartRepo", Resource: "chartrepos", Scope: admissionregistrationv1beta1.NamespacedScope},
+		{Kind: "ClusterChartRepo", Resource: "clusterchartrepos", Scope: admissionregistrationv1beta1.ClusterScope},
+	}
+	for i := range kinds {
+		for _, gv := range versions {
+			obj, err := scheme.Scheme.New(gv.WithKind(kinds[i].Kind))
+			if err != nil {
+				continue
+			}
+			if _, ok := obj.(Defaulter); ok {
+				kinds[i].MutatingVersions = append(kinds[i].MutatingVersions, gv.Version)
+			}
+			if _, ok := obj.(Validator); ok {
+				kinds[i].ValidatingVersions = append(kinds[i].ValidatingVersions, gv.Version)
+			}
+		}
+	}
+	return kinds
+}
+
+// MutatingWebhookName returns the name of the mutating webhook of the resource, eg:
+// default.helmrequests.app.alauda.io
+func MutatingWebhookName(resource string) string {
+	return fmt.Sprintf("default.%s.%s", resource, v1beta1.SchemeGroupVersion.Group)
+}
+
+// ValidatingWebhookName returns the name of the validating webhook of the resource, eg:
+// validate.helmrequests.app.alauda.io
+func ValidatingWebhookName(resource string) string {
+	return fmt.Sprintf("validate.%s.%s", resource, v1beta1.SchemeGroupVersion.Group)
+}
+
+// NewHandler returns the http handler serves the webhooks on MutatePath and ValidatePath
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(MutatePath, admitFunc(Mutate))
+	mux.Handle(ValidatePath, admitFunc(Validate))
+	mux.HandleFunc(HealthzPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+// admitFunc handles an AdmissionRequest, it's served as an http handler of AdmissionReviews
+type admitFunc func(*admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse
+
+func (f admitFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+		http.Error(w, fmt.Sprintf("unsupported content type %s, only application/json is supported", ct), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body error: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	review := admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		http.Error(w, fmt.Sprintf("decode admission review error: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "admission review has no request", http.StatusBadRequest)
+		return
+	}
+
+	resp := f(review.Request)
+	resp.UID = review.Request.UID
+	review.Response = resp
+	review.Request = nil
+	if review.APIVersion == "" {
+		review.APIVersion = admissionv1beta1.SchemeGroupVersion.String()
+		review.Kind = "AdmissionReview"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("write admission review error: %s", err.Error())
+	}
+}
+
+// Mutate calls Default() of the object and returns the changes as a json patch. Objects don't implement
+// Defaulter are allowed without changes.
+func Mutate(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return allowed()
+	}
+
+	obj, err := decode(req.Kind, req.Object)
+	if err != nil {
+		return errored(http.StatusBadRequest, err)
+	}
+	d, ok := obj.(Defaulter)
+	if !ok {
+		return allowed()
+	}
+
+	// diff with the decoded object instead of the raw one, so the unknown fields are not removed
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return errored(http.StatusInternalServerError, err)
+	}
+	d.Default()
+	defaulted, err := json.Marshal(obj)
+	if err != nil {
+		return errored(http.StatusInternalServerError, err)
+	}
+
+	patch, err := createPatch(original, defaulted)
+	if err != nil {
+		return errored(http.StatusInternalServerError, err)
+	}
+	resp := allowed()
+	if patch != nil {
+		pt := admissionv1beta1.PatchTypeJSONPatch
+		resp.Patch = patch
+		resp.PatchType = &pt
+	}
+	return resp
+}
+
+// Validate calls ValidateCreate/ValidateUpdate/ValidateDelete of the object according to the operation.
+// Objects don't implement Validator are allowed.
+func Validate(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	var err error
+	switch req.Operation {
+	case admissionv1beta1.Create:
+		err = validate(req.Kind, req.Object, func(v Validator) error {
+			return v.ValidateCreate()
+		})
+	case admissionv1beta1.Update:
+		var old runtime.Object
+		if old, err = decode(req.Kind, req.OldObject); err != nil {
+			return errored(http.StatusBadRequest, err)
+		}
+		err = validate(req.Kind, req.Object, func(v Validator) error {
+			return v.ValidateUpdate(old)
+		})
+	case admissionv1beta1.Delete:
+		// the object being deleted is in oldObject, it's empty before kubernetes 1.15
+		if len(req.OldObject.Raw) == 0 {
+			return allowed()
+		}
+		err = validate(req.Kind, req.OldObject, func(v Validator) error {
+			return v.ValidateDelete()
+		})
+	default:
+		return allowed()
+	}
+
+	if err == nil {
+		return allowed()
+	}
+	if resp, ok := err.(*badRequest); ok {
+		return errored(http.StatusBadRequest, resp.err)
+	}
+	klog.V(4).Infof("%s %s %s/%s denied: %s", req.Operation, req.Kind.Kind, req.Namespace, req.Name, err.Error())
+	return denied(err)
+}
+
+// badRequest is an error caused by the request itself instead of the validation
+type badRequest struct {
+	err error
+}
+
+func (e *badRequest) Error() string {
+	return e.err.Error()
+}
+
+func validate(gvk metav1.GroupVersionKind, raw runtime.RawExtension, check func(Validator) error) error {
+	obj, err := decode(gvk, raw)
+	if err != nil {
+		return &badRequest{err: err}
+	}
+	v, ok := obj.(Validator)
+	if !ok {
+		return nil
+	}
+	return check(v)
+}
+
+// decode decodes the raw object to the typed object of the kind
+func decode(gvk metav1.GroupVersionKind, raw runtime.RawExtension) (runtime.Object, error) {
+	obj, err := scheme.Scheme.New(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+	if err != nil {
+		return nil, err
+	}
+	if len(raw.Raw) == 0 {
+		return nil, fmt.Errorf("there is no content to decode")
+	}
+	if err := json.Unmarshal(raw.Raw, obj); err != nil {
+		return nil, fmt.Errorf("decode %s error: %s", gvk.Kind, err.Error())
+	}
+	return obj, nil
+}
+
+func allowed() *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: true,
+		Result:  &metav1.Status{Code: http.StatusOK},
+	}
+}
+
+func denied(err error) *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: err.Error(),
+		},
+	}
+}
+
+func errored(code int32, err error) *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Code:    code,
+			Message: err.Error(),
+		},
+	}
+}
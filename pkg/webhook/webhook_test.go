@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alauda/helm-crds/pkg/apis/app/v1beta1"
+	"github.com/alauda/helm-crds/pkg/client/clientset/versioned/scheme"
+	jsonpatch "github.com/evanphx/json-patch"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var helmRequestKind = metav1.GroupVersionKind{Group: "app.alauda.io", Version: "v1beta1", Kind: "HelmRequest"}
+
+// testGroupVersion has a kind denies all the deletes, none of the app kinds does that
+var testGroupVersion = schema.GroupVersion{Group: "test.alauda.io", Version: "v1"}
+
+type protected struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+func (in *protected) DeepCopyObject() runtime.Object {
+	out := *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return &out
+}
+
+func (in *protected) ValidateCreate() error                   { return nil }
+func (in *protected) ValidateUpdate(old runtime.Object) error { return nil }
+func (in *protected) ValidateDelete() error {
+	return fmt.Errorf("%s is protected", in.Name)
+}
+
+func init() {
+	scheme.Scheme.AddKnownTypes(testGroupVersion, &protected{})
+}
+
+// review posts the request to the path of the handler and returns the response
+func review(t *testing.T, handler http.Handler, path string, req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	body, err := json.Marshal(admissionv1beta1.AdmissionReview{Request: req})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expect status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	result := admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Response == nil {
+		t.Fatal("expect a response in the admission review")
+	}
+	if result.Response.UID != req.UID {
+		t.Errorf("expect uid %s, got %s", req.UID, result.Response.UID)
+	}
+	return result.Response
+}
+
+func TestMutate(t *testing.T) {
+	tests := []struct {
+		name       string
+		object     string
+		finalizers []string
+		noPatch    bool
+	}{
+		{
+			name:       "no finalizers",
+			object:     `{"apiVersion":"app.alauda.io/v1beta1","kind":"HelmRequest","metadata":{"name":"nginx","namespace":"default"},"spec":{"chart":"stable/nginx"}}`,
+			finalizers: []string{v1beta1.FinalizerName},
+		},
+		{
+			name:       "existing finalizers",
+			object:     `{"apiVersion":"app.alauda.io/v1beta1","kind":"HelmRequest","metadata":{"name":"nginx","namespace":"default","finalizers":["foregroundDeletion","example.com/cleanup"]},"spec":{"chart":"stable/nginx","unknownField":"kept"}}`,
+			finalizers: []string{"foregroundDeletion", "example.com/cleanup", v1beta1.FinalizerName},
+		},
+		{
+			name:       "defaulted",
+			object:     `{"apiVersion":"app.alauda.io/v1beta1","kind":"HelmRequest","metadata":{"name":"nginx","namespace":"default","finalizers":["example.com/cleanup","captain.cpaas.io"]},"spec":{"chart":"stable/nginx","releaseName":"nginx","namespace":"default"}}`,
+			finalizers: []string{"example.com/cleanup", v1beta1.FinalizerName},
+			noPatch:    true,
+		},
+	}
+	handler := NewHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := review(t, handler, MutatePath, &admissionv1beta1.AdmissionRequest{
+				UID:       "uid",
+				Kind:      helmRequestKind,
+				Operation: admissionv1beta1.Create,
+				Object:    runtime.RawExtension{Raw: []byte(tt.object)},
+			})
+			if !resp.Allowed {
+				t.Fatalf("expect allowed, got %v", resp.Result)
+			}
+			if tt.noPatch {
+				if resp.Patch != nil {
+					t.Errorf("expect no patch, got %s", resp.Patch)
+				}
+				return
+			}
+			if resp.PatchType == nil || *resp.PatchType != admissionv1beta1.PatchTypeJSONPatch {
+				t.Fatalf("expect patch type %s, got %v", admissionv1beta1.PatchTypeJSONPatch, resp.PatchType)
+			}
+
+			patch, err := jsonpatch.DecodePatch(resp.Patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			patched, err := patch.Apply([]byte(tt.object))
+			if err != nil {
+				t.Fatalf("apply patch %s error: %s", resp.Patch, err.Error())
+			}
+
+			hr := v1beta1.HelmRequest{}
+			if err := json.Unmarshal(patched, &hr); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(hr.Finalizers, tt.finalizers) {
+				t.Errorf("expect finalizers %v, got %v", tt.finalizers, hr.Finalizers)
+			}
+			if hr.Spec.ReleaseName != "nginx" || hr.Spec.Namespace != "default" {
+				t.Errorf("expect release nginx in default, got %s in %s", hr.Spec.ReleaseName, hr.Spec.Namespace)
+			}
+			if strings.Contains(tt.object, "unknownField") && !strings.Contains(string(patched), `"unknownField":"kept"`) {
+				t.Errorf("expect unknown fields kept, got %s", patched)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	nginx := `{"apiVersion":"app.alauda.io/v1beta1","kind":"HelmRequest","metadata":{"name":"nginx","namespace":"default"},"spec":{"chart":"stable/nginx"}}`
+	redis := `{"apiVersion":"app.alauda.io/v1beta1","kind":"HelmRequest","metadata":{"name":"nginx","namespace":"default"},"spec":{"chart":"stable/redis"}}`
+	invalid := `{"apiVersion":"app.alauda.io/v1beta1","kind":"HelmRequest","metadata":{"name":"nginx","namespace":"default"},"spec":{"chart":"stable/"}}`
+	protectedKind := metav1.GroupVersionKind{Group: testGroupVersion.Group, Version: testGroupVersion.Version, Kind: "protected"}
+	protectedObject := `{"apiVersion":"test.alauda.io/v1","kind":"protected","metadata":{"name":"important"}}`
+
+	tests := []struct {
+		name      string
+		kind      metav1.GroupVersionKind
+		operation admissionv1beta1.Operation
+		object    string
+		oldObject string
+		code      int32
+		message   string
+	}{
+		{name: "create", kind: helmRequestKind, operation: admissionv1beta1.Create, object: nginx, code: http.StatusOK},
+		{name: "create invalid chart", kind: helmRequestKind, operation: admissionv1beta1.Create, object: invalid, code: http.StatusForbidden},
+		{name: "update", kind: helmRequestKind, operation: admissionv1beta1.Update, object: nginx, oldObject: nginx, code: http.StatusOK},
+		{name: "update chart name", kind: helmRequestKind, operation: admissionv1beta1.Update, object: redis, oldObject: nginx, code: http.StatusForbidden, message: "chart name cannot be updated"},
+		{name: "update without old object", kind: helmRequestKind, operation: admissionv1beta1.Update, object: nginx, code: http.StatusBadRequest},
+		{name: "delete", kind: helmRequestKind, operation: admissionv1beta1.Delete, oldObject: nginx, code: http.StatusOK},
+		{name: "delete protected", kind: protectedKind, operation: admissionv1beta1.Delete, oldObject: protectedObject, code: http.StatusForbidden, message: "important is protected"},
+		{name: "delete without old object", kind: protectedKind, operation: admissionv1beta1.Delete, code: http.StatusOK},
+		{name: "malformed", kind: helmRequestKind, operation: admissionv1beta1.Create, object: `{"spec":"nginx"}`, code: http.StatusBadRequest},
+	}
+	handler := NewHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &admissionv1beta1.AdmissionRequest{UID: "uid", Kind: tt.kind, Operation: tt.operation}
+			if tt.object != "" {
+				req.Object.Raw = []byte(tt.object)
+			}
+			if tt.oldObject != "" {
+				req.OldObject.Raw = []byte(tt.oldObject)
+			}
+			resp := review(t, handler, ValidatePath, req)
+			if resp.Allowed != (tt.code == http.StatusOK) {
+				t.Errorf("expect allowed %v, got %v", tt.code == http.StatusOK, resp.Allowed)
+			}
+			if resp.Result == nil || resp.Result.Code != tt.code {
+				t.Fatalf("expect code %d, got %v", tt.code, resp.Result)
+			}
+			if !strings.Contains(resp.Result.Message, tt.message) {
+				t.Errorf("expect message %q, got %q", tt.message, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestHandlerRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		code        int
+	}{
+		{name: "get", method: http.MethodGet, path: MutatePath, contentType: "application/json", code: http.StatusMethodNotAllowed},
+		{name: "put", method: http.MethodPut, path: ValidatePath, contentType: "application/json", code: http.StatusMethodNotAllowed},
+		{name: "text", method: http.MethodPost, path: MutatePath, contentType: "text/plain", code: http.StatusUnsupportedMediaType},
+		{name: "no content type", method: http.MethodPost, path: ValidatePath, code: http.StatusUnsupportedMediaType},
+		{name: "no request", method: http.MethodPost, path: ValidatePath, contentType: "application/json", code: http.StatusBadRequest},
+		{name: "healthz", method: http.MethodGet, path: HealthzPath, code: http.StatusOK},
+	}
+	handler := NewHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader("{}"))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Errorf("expect status %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}